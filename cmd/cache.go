@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/spf13/cobra"
+)
+
+// NewCacheCommand creates the cache parent command with subcommands.
+func NewCacheCommand() *cobra.Command {
+	return newCacheCommand(openCacheStore, func(cmd *cobra.Command, args []string) error {
+		ispAuth, svc, _, err := bootstrapSCAService()
+		if err != nil {
+			return err
+		}
+
+		cfg, _, err := config.LoadDefaultWithPath()
+		if err != nil {
+			return err
+		}
+
+		// refresh: warming replaces what is cached with live data.
+		cachedLister, err := buildCachedLister(cfg, true, svc, svc)
+		if err != nil {
+			return err
+		}
+
+		return runCacheWarm(cmd, ispAuth, cachedLister, cachedLister)
+	})
+}
+
+// NewCacheCommandWithDeps creates a cache command over store with injected
+// dependencies for testing. warm fetches through the given listers, which are
+// expected to write store.
+func NewCacheCommandWithDeps(store *cache.Store, auth authLoader, eligLister eligibilityLister, groupsElig groupsEligibilityLister) *cobra.Command {
+	return newCacheCommand(func() (*cache.Store, error) { return store, nil }, func(cmd *cobra.Command, args []string) error {
+		return runCacheWarm(cmd, auth, eligLister, groupsElig)
+	})
+}
+
+func newCacheCommand(openStore func() (*cache.Store, error), runWarm func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and manage the local cache",
+		Long: `Inspect and manage the cache of eligibility, on-demand roles and session
+state that grant keeps for the current profile, tenant and user.
+
+Examples:
+  grant cache list
+  grant cache clear --kind eligibility
+  grant cache warm
+  grant cache stats --output json`,
+	}
+
+	withStore := func(run func(*cobra.Command, *cache.Store) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			store, err := openStore()
+			if err != nil {
+				return err
+			}
+			return run(cmd, store)
+		}
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cache entries with their age, size and item count",
+		Args:  cobra.NoArgs,
+		RunE:  withStore(runCacheList),
+	}
+
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Delete cache entries",
+		Long: `Delete cache entries, all of them or only those of the given kinds:
+` + strings.Join(cache.Kinds, ", ") + `. Lookup statistics are kept; reset them
+with 'grant cache stats --reset'.`,
+		Example: "  grant cache clear\n  grant cache clear --kind eligibility,groups",
+		Args:    cobra.NoArgs,
+		RunE:    withStore(runCacheClear),
+	}
+	clearCmd.Flags().StringSlice("kind", nil, "Only delete entries of these kinds ("+strings.Join(cache.Kinds, ", ")+")")
+
+	warmCmd := &cobra.Command{
+		Use:   "warm",
+		Short: "Prefetch eligibility for every provider and groups",
+		Long: `Fetch cloud eligibility for every supported provider and Entra ID group
+eligibility concurrently, replacing what is cached, so later commands start
+from a fresh cache.`,
+		Args: cobra.NoArgs,
+		RunE: runWarm,
+	}
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show cache hit and miss counters",
+		Long: `Show how often each kind of cache entry was found (hit), had to be fetched
+(miss, including --refresh), or was served expired because the API was
+unreachable (stale), counted across every grant command since the counters
+were last reset.`,
+		Args: cobra.NoArgs,
+		RunE: withStore(runCacheStats),
+	}
+	statsCmd.Flags().Bool("reset", false, "Reset the counters")
+
+	cmd.AddCommand(listCmd, clearCmd, warmCmd, statsCmd)
+	return cmd
+}
+
+// openCacheStore opens the current identity's cache with the configured
+// policies. Its own TTL is the session timestamps'.
+func openCacheStore() (*cache.Store, error) {
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve cache directory: %w", err)
+	}
+	store := newCacheStore(dir, sessionStoreTTL)
+	if err := applyCachePolicies(cfg, store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+func runCacheList(cmd *cobra.Command, store *cache.Store) error {
+	entries, err := cache.Entries(store)
+	if err != nil {
+		return fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	now := time.Now()
+	out := make([]cacheEntryOutput, 0, len(entries))
+	for _, e := range entries {
+		o := cacheEntryOutput{Key: e.Key, Kind: e.Kind, SizeBytes: e.Size, Items: e.Items, Expired: true}
+		if !e.CachedAt.IsZero() {
+			age := now.Sub(e.CachedAt)
+			o.CachedAt = e.CachedAt
+			o.AgeSeconds = int(age.Seconds())
+			o.Expired = age > store.TTLOf(e.Key)
+		}
+		out = append(out, o)
+	}
+
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), out)
+	}
+
+	if len(out) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "Cache is empty.")
+		return nil
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tKIND\tAGE\tSIZE\tITEMS\tSTATUS")
+	for _, o := range out {
+		age, status := "-", "unreadable"
+		if !o.CachedAt.IsZero() {
+			age = formatAge(time.Duration(o.AgeSeconds) * time.Second)
+			status = "fresh"
+			if o.Expired {
+				status = "expired"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d B\t%d\t%s\n", o.Key, o.Kind, age, o.SizeBytes, o.Items, status)
+	}
+	return w.Flush()
+}
+
+func runCacheClear(cmd *cobra.Command, store *cache.Store) error {
+	kinds, _ := cmd.Flags().GetStringSlice("kind")
+	for _, k := range kinds {
+		if !slices.Contains(cache.Kinds, k) {
+			return usageErrorf("unknown cache kind %q, valid kinds: %s", k, strings.Join(cache.Kinds, ", "))
+		}
+	}
+
+	removed, err := cache.Clear(store, kinds...)
+	if err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	if removed == nil {
+		removed = []string{}
+	}
+
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), cacheClearOutput{Removed: removed})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cache entries\n", len(removed))
+	return nil
+}
+
+func runCacheWarm(cmd *cobra.Command, auth authLoader, eligLister eligibilityLister, groupsElig groupsEligibilityLister) error {
+	if _, err := auth.LoadAuthentication(nil, true); err != nil {
+		return notAuthenticatedError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+	defer cancel()
+
+	// One slot per fetch keeps the output in a stable order however the
+	// concurrent fetches finish.
+	out := make([]cacheWarmOutput, len(supportedCSPs)+1)
+	errs := make([]error, len(out))
+	var wg sync.WaitGroup
+	for i, csp := range supportedCSPs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out[i] = cacheWarmOutput{Kind: cache.KindEligibility, Provider: strings.ToLower(string(csp))}
+			resp, err := listEligibilityReported(ctx, eligLister, csp)
+			if err == nil {
+				out[i].Items = len(resp.Response)
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		last := len(out) - 1
+		out[last] = cacheWarmOutput{Kind: cache.KindGroups, Provider: "azure"}
+		resp, err := groupsElig.ListGroupsEligibility(ctx, models.CSPAzure)
+		ev := eligibilityEventData{Kind: "groups", Provider: "azure"}
+		if err == nil {
+			out[last].Items = len(resp.Response)
+			ev.Count = out[last].Items
+		} else {
+			ev.Error = err.Error()
+		}
+		emitEvent(eventEligibilityFetched, ev)
+		errs[last] = err
+	}()
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			out[i].Error = err.Error()
+			failed++
+		}
+	}
+
+	if isStructuredOutput() {
+		if err := writeOutput(cmd.OutOrStdout(), out); err != nil {
+			return err
+		}
+	} else {
+		for _, o := range out {
+			if o.Error != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-12s %-6s failed: %s\n", o.Kind, o.Provider, o.Error)
+				continue
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-12s %-6s %d cached\n", o.Kind, o.Provider, o.Items)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("cache warm: %d of %d fetches failed: %w", failed, len(out), errors.Join(errs...))
+	}
+	return nil
+}
+
+func runCacheStats(cmd *cobra.Command, store *cache.Store) error {
+	if reset, _ := cmd.Flags().GetBool("reset"); reset {
+		cache.ResetStats(store)
+		if isStructuredOutput() {
+			return writeOutput(cmd.OutOrStdout(), cacheStatsOutput{Kinds: []cacheKindStatsOutput{}})
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "Cache statistics reset")
+		return nil
+	}
+
+	st := cache.LoadStats(store)
+	out := cacheStatsOutput{Since: st.Since, Kinds: []cacheKindStatsOutput{}}
+	for _, kind := range cache.Kinds {
+		c, ok := st.Kinds[kind]
+		if !ok {
+			continue
+		}
+		k := cacheKindStatsOutput{Kind: kind, Hits: c.Hits, Misses: c.Misses, Stale: c.Stale}
+		if lookups := c.Hits + c.Misses; lookups > 0 {
+			k.HitRate = float64(c.Hits) / float64(lookups)
+		}
+		out.Kinds = append(out.Kinds, k)
+	}
+
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), out)
+	}
+
+	if len(out.Kinds) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No cache lookups recorded.")
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Since %s\n\n", out.Since.Local().Format(time.DateTime))
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tHITS\tMISSES\tSTALE\tHIT RATE")
+	for _, k := range out.Kinds {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.0f%%\n", k.Kind, k.Hits, k.Misses, k.Stale, k.HitRate*100)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"sync"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	sdkmodels "github.com/cyberark/idsec-sdk-golang/pkg/models"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+	"github.com/cyberark/idsec-sdk-golang/pkg/profiles"
+)
+
+// loadCacheNamespace returns the cache namespace of the identity in the active
+// SDK profile. It reads the profile file only, so offline commands can use it.
+// Package-level var for test injection.
+var loadCacheNamespace = func() cache.Namespace {
+	name := activeProfile()
+	loader := profiles.DefaultProfilesLoader()
+	profile, err := (*loader).LoadProfile(name)
+	if err != nil {
+		log.Info("failed to load profile for the cache namespace: %v", err)
+	}
+	return namespaceOf(name, profile)
+}
+
+// namespaceOf returns the cache namespace of a profile; a missing profile has
+// an empty identity.
+func namespaceOf(name string, profile *sdkmodels.IdsecProfile) cache.Namespace {
+	ns := cache.Namespace{Profile: name}
+	if profile == nil {
+		return ns
+	}
+	if ap := profile.AuthProfiles["isp"]; ap != nil {
+		ns.Username = ap.Username
+		if settings, ok := ap.AuthMethodSettings.(*authmodels.IdentityIdsecAuthMethodSettings); ok && settings != nil {
+			ns.TenantURL = settings.IdentityURL
+		}
+	}
+	return ns
+}
+
+// discardLegacyCacheOnce limits the legacy cache sweep to once per process.
+var discardLegacyCacheOnce sync.Once
+
+// cacheDir returns the cache directory of the configured identity. The first
+// call in a process discards entries left un-namespaced by older versions.
+func cacheDir() (string, error) {
+	root, err := cache.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	discardLegacyCacheOnce.Do(func() {
+		if err := cache.DiscardLegacy(root); err != nil {
+			log.Info("failed to discard legacy cache entries: %v", err)
+		}
+	})
+	return loadCacheNamespace().Dir(root), nil
+}
+
+// invalidateCache deletes everything cached for ns. Best-effort. Package-level
+// var for test injection.
+var invalidateCache = func(ns cache.Namespace) {
+	root, err := cache.CacheDir()
+	if err != nil {
+		log.Info("failed to invalidate cache: %v", err)
+		return
+	}
+	if err := cache.RemoveNamespace(root, ns); err != nil {
+		log.Info("failed to invalidate cache: %v", err)
+	}
+}
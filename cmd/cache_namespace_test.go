@@ -0,0 +1,119 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	sdkmodels "github.com/cyberark/idsec-sdk-golang/pkg/models"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+)
+
+// stubCacheNamespace makes loadCacheNamespace return ns and records every
+// invalidateCache call, restoring both when the test ends.
+func stubCacheNamespace(t *testing.T, ns cache.Namespace) *[]cache.Namespace {
+	t.Helper()
+	origLoad, origInvalidate := loadCacheNamespace, invalidateCache
+	t.Cleanup(func() { loadCacheNamespace, invalidateCache = origLoad, origInvalidate })
+
+	var invalidated []cache.Namespace
+	loadCacheNamespace = func() cache.Namespace { return ns }
+	invalidateCache = func(ns cache.Namespace) { invalidated = append(invalidated, ns) }
+	return &invalidated
+}
+
+func TestNamespaceOf(t *testing.T) {
+	profile := &sdkmodels.IdsecProfile{
+		ProfileName: "grant",
+		AuthProfiles: map[string]*authmodels.IdsecAuthProfile{
+			"isp": {
+				Username:           "alice@example.com",
+				AuthMethodSettings: &authmodels.IdentityIdsecAuthMethodSettings{IdentityURL: "https://abc.id.cyberark.cloud"},
+			},
+		},
+	}
+
+	got := namespaceOf("grant", profile)
+	want := cache.Namespace{Profile: "grant", TenantURL: "https://abc.id.cyberark.cloud", Username: "alice@example.com"}
+	if got != want {
+		t.Errorf("namespaceOf() = %+v, want %+v", got, want)
+	}
+
+	if got := namespaceOf("grant", nil); got != (cache.Namespace{Profile: "grant"}) {
+		t.Errorf("namespaceOf(nil) = %+v, want an empty identity", got)
+	}
+}
+
+func TestCacheDir_NamespacedAndDiscardsLegacy(t *testing.T) {
+	ns := cache.Namespace{Profile: "grant", Username: "alice@example.com"}
+	stubCacheNamespace(t, ns)
+	discardLegacyCacheOnce = sync.Once{}
+	t.Cleanup(func() { discardLegacyCacheOnce = sync.Once{} })
+
+	root, err := cache.CacheDir()
+	if err != nil {
+		t.Fatalf("cache.CacheDir(): %v", err)
+	}
+	if err := cache.Set(cache.NewStore(root, time.Hour), "eligibility_azure", "another tenant's"); err != nil {
+		t.Fatalf("seed legacy entry: %v", err)
+	}
+
+	dir, err := cacheDir()
+	if err != nil {
+		t.Fatalf("cacheDir() error = %v", err)
+	}
+	if dir != ns.Dir(root) {
+		t.Errorf("cacheDir() = %q, want %q", dir, ns.Dir(root))
+	}
+	if _, err := os.Stat(filepath.Join(root, "eligibility_azure.json")); !os.IsNotExist(err) {
+		t.Errorf("legacy entry survived the migration (stat err = %v)", err)
+	}
+}
+
+func TestLogout_InvalidatesCacheNamespace(t *testing.T) {
+	ns := cache.Namespace{Profile: "grant", Username: "alice@example.com"}
+	invalidated := stubCacheNamespace(t, ns)
+
+	if _, err := executeCommand(NewLogoutCommandWithDeps(&mockKeyringClearer{})); err != nil {
+		t.Fatalf("logout error = %v", err)
+	}
+	if len(*invalidated) != 1 || (*invalidated)[0] != ns {
+		t.Errorf("invalidated = %+v, want [%+v]", *invalidated, ns)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG and IDSEC_PROFILES_FOLDER for the process.
+func TestConfigure_IdentityChangeInvalidatesCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous cache.Namespace
+		want     bool
+	}{
+		{"same identity keeps the cache", cache.Namespace{Profile: "grant", TenantURL: "https://example.cyberark.cloud", Username: "Test.User@example.com"}, false},
+		{"other user", cache.Namespace{Profile: "grant", TenantURL: "https://example.cyberark.cloud", Username: "someone@example.com"}, true},
+		{"other tenant", cache.Namespace{Profile: "grant", TenantURL: "https://other.cyberark.cloud", Username: "test.user@example.com"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("GRANT_CONFIG", filepath.Join(dir, "config.yaml"))
+			t.Setenv("IDSEC_PROFILES_FOLDER", filepath.Join(dir, "profiles"))
+			invalidated := stubCacheNamespace(t, tt.previous)
+
+			cmd := NewConfigureCommandWithDeps(&mockProfileSaver{}, "https://example.cyberark.cloud", "test.user@example.com")
+			if _, err := executeCommand(cmd); err != nil {
+				t.Fatalf("configure() error = %v", err)
+			}
+
+			if got := len(*invalidated) == 1 && (*invalidated)[0] == tt.previous; got != tt.want {
+				t.Errorf("invalidated = %+v, want previous namespace invalidated: %v", *invalidated, tt.want)
+			}
+		})
+	}
+}
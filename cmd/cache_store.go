@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/cyberark/idsec-sdk-golang/pkg/common/keyring"
+)
+
+// cacheEncryptionKey returns the key that seals cache entries when
+// cache_encrypt is set, or nil. The config is read at most once per process;
+// the keyring only when a store first needs the key, and at most once.
+// Package-level var for test injection.
+var cacheEncryptionKey = sync.OnceValue(func() cache.KeyFunc {
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err != nil || !cfg.CacheEncrypt {
+		return nil
+	}
+	return sync.OnceValues(func() ([]byte, error) {
+		// false leaves the backend to the SDK, which honours IDSEC_BASIC_KEYRING
+		// the same way it does for the tokens.
+		kr, err := keyring.NewIdsecKeyring("grant").GetKeyring(false)
+		if err != nil {
+			return nil, err
+		}
+		return cache.KeyringKey(kr, "grant")()
+	})
+})
+
+// newCacheStore opens the cache store in dir, encrypting its entries when
+// cache_encrypt is set. Opening it touches neither the keyring nor the lock:
+// the key is loaded by the first write or sealed read, and the first write
+// seals the entries written before encryption was turned on.
+func newCacheStore(dir string, ttl time.Duration) *cache.Store {
+	store := cache.NewStore(dir, ttl)
+	if key := cacheEncryptionKey(); key != nil {
+		store.Encrypt(key)
+	}
+	return store
+}
+
+// applyCachePolicies gives each kind of entry in store its TTL, max-stale and
+// disabled setting from cfg.
+func applyCachePolicies(cfg *config.Config, store *cache.Store) error {
+	for _, kind := range config.CacheKinds {
+		p, err := config.ParseCachePolicy(cfg, kind)
+		if err != nil {
+			return err
+		}
+		store.SetPolicy(kind, cache.Policy(p))
+	}
+	return nil
+}
+
+// sessionCachePolicy is the policy of the last-known session list, read from
+// the config once per process: session tracking runs deep inside commands
+// that have no config at hand. Package-level var for test injection.
+var sessionCachePolicy = sync.OnceValue(func() cache.Policy {
+	p := config.CachePolicy{TTL: config.DefaultSessionCacheTTL}
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err == nil {
+		p, err = config.ParseCachePolicy(cfg, config.CacheKindSessions)
+	}
+	if err != nil {
+		log.Info("failed to read the sessions cache policy: %v", err)
+		return cache.Policy{TTL: config.DefaultSessionCacheTTL}
+	}
+	return cache.Policy(p)
+})
@@ -0,0 +1,104 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	"github.com/aaearon/grant-cli/internal/config"
+)
+
+func TestNewCacheStore_Encryption(t *testing.T) {
+	origKey := cacheEncryptionKey
+	t.Cleanup(func() { cacheEncryptionKey = origKey })
+
+	dir := t.TempDir()
+	cacheEncryptionKey = func() cache.KeyFunc { return nil }
+	if err := cache.Set(newCacheStore(dir, time.Hour), "eligibility_azure", []string{"Prod-Subscription"}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "eligibility_azure.json")
+	if data, _ := os.ReadFile(path); !bytes.Contains(data, []byte("Prod-Subscription")) {
+		t.Fatalf("cache_encrypt off wrote %s, want plaintext", data)
+	}
+
+	key := bytes.Repeat([]byte{7}, 32)
+	cacheEncryptionKey = func() cache.KeyFunc {
+		return func() ([]byte, error) { return key, nil }
+	}
+	store := newCacheStore(dir, time.Hour)
+	if err := cache.Set(store, "eligibility_aws", []string{"Dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); bytes.Contains(data, []byte("Prod-Subscription")) {
+		t.Errorf("plaintext entry was not sealed by the first write: %s", data)
+	}
+	var got []string
+	if !cache.Get(store, "eligibility_azure", &got) || got[0] != "Prod-Subscription" {
+		t.Errorf("Get() after sealing = %v", got)
+	}
+}
+
+func TestNewOfflineSessionStore_NeverLoadsKey(t *testing.T) {
+	origKey := cacheEncryptionKey
+	t.Cleanup(func() { cacheEncryptionKey = origKey })
+
+	dir := t.TempDir()
+	key := bytes.Repeat([]byte{7}, 32)
+	cacheEncryptionKey = func() cache.KeyFunc {
+		return func() ([]byte, error) { return key, nil }
+	}
+	if err := cache.AddKnownSession(newSessionStore(dir), cache.KnownSession{SessionID: "s1", Provider: "aws", Target: "Prod"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheEncryptionKey = func() cache.KeyFunc {
+		return func() ([]byte, error) {
+			t.Error("the prompt store loaded the cache key")
+			return nil, errors.New("keyring unavailable")
+		}
+	}
+	if known := cache.KnownSessions(newOfflineSessionStore(dir)); known != nil {
+		t.Errorf("KnownSessions() = %+v, want an encrypted snapshot to read as a miss", known)
+	}
+}
+
+func TestApplyCachePolicies(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CacheTTL = "2h"
+	cfg.Cache = map[string]config.CacheKindConfig{
+		config.CacheKindGroups:   {TTL: "168h"},
+		config.CacheKindOnDemand: {Disabled: true},
+	}
+	store := cache.NewStore(t.TempDir(), sessionStoreTTL)
+	if err := applyCachePolicies(cfg, store); err != nil {
+		t.Fatalf("applyCachePolicies() error = %v", err)
+	}
+
+	for key, want := range map[string]time.Duration{
+		"eligibility_aws":          2 * time.Hour,
+		"groups_eligibility_azure": 168 * time.Hour,
+		"session_snapshot":         config.DefaultSessionCacheTTL,
+	} {
+		if got := store.TTLOf(key); got != want {
+			t.Errorf("TTLOf(%s) = %v, want %v", key, got, want)
+		}
+	}
+	if err := cache.Set(store, "ondemand_roles_aws_x", []string{"role"}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := cache.Entries(store); len(entries) != 0 {
+		t.Errorf("disabled kind was cached: %+v", entries)
+	}
+
+	cfg.Cache = map[string]config.CacheKindConfig{config.CacheKindEligibility: {TTL: "-1h"}}
+	if err := applyCachePolicies(cfg, store); err == nil {
+		t.Error("applyCachePolicies() accepted an invalid TTL")
+	}
+}
@@ -0,0 +1,214 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/spf13/cobra"
+)
+
+// cacheFixtureStore returns a store holding fresh azure cloud and groups
+// eligibility.
+func cacheFixtureStore(t *testing.T) *cache.Store {
+	t.Helper()
+	store := cache.NewStore(t.TempDir(), time.Hour)
+	azure := models.EligibilityResponse{Response: []models.EligibleTarget{{}, {}}, Total: 2}
+	if err := cache.Set(store, "eligibility_azure", azure); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Set(store, "groups_eligibility_azure", models.GroupsEligibilityResponse{Response: []models.GroupsEligibleTarget{{}}}); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func newCacheTestRoot(store *cache.Store, auth *mockAuthLoader, elig *mockEligibilityLister, groups *mockGroupsEligibilityLister) *cobra.Command {
+	root := newTestRootCommand()
+	root.AddCommand(NewCacheCommandWithDeps(store, auth, elig, groups))
+	return root
+}
+
+func TestCacheList(t *testing.T) {
+	store := cacheFixtureStore(t)
+	root := newCacheTestRoot(store, authedLoader(), &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+
+	output, err := executeCommand(root, "cache", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+	for _, want := range []string{"KEY", "eligibility_azure", "groups_eligibility_azure", "fresh"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	root = newCacheTestRoot(store, authedLoader(), &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+	stdout, _, err := executeCommandStreams(root, "cache", "list", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var entries []cacheEntryOutput
+	if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want 2", entries)
+	}
+	if e := entries[0]; e.Key != "eligibility_azure" || e.Kind != cache.KindEligibility || e.Items != 2 || e.Expired || e.SizeBytes == 0 {
+		t.Errorf("entries[0] = %+v", e)
+	}
+}
+
+func TestCacheList_Empty(t *testing.T) {
+	root := newCacheTestRoot(cache.NewStore(t.TempDir(), time.Hour), authedLoader(), &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+	output, err := executeCommand(root, "cache", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "Cache is empty.") {
+		t.Errorf("output = %q", output)
+	}
+}
+
+func TestCacheClear(t *testing.T) {
+	store := cacheFixtureStore(t)
+
+	root := newCacheTestRoot(store, authedLoader(), &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+	stdout, _, err := executeCommandStreams(root, "cache", "clear", "--kind", "groups", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out cacheClearOutput
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(out.Removed) != 1 || out.Removed[0] != "groups_eligibility_azure" {
+		t.Errorf("removed = %v, want only the groups entry", out.Removed)
+	}
+
+	root = newCacheTestRoot(store, authedLoader(), &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+	output, err := executeCommand(root, "cache", "clear")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "Removed 1 cache entries") {
+		t.Errorf("output = %q", output)
+	}
+	if entries, _ := cache.Entries(store); len(entries) != 0 {
+		t.Errorf("entries after clear = %+v", entries)
+	}
+}
+
+func TestCacheClear_UnknownKind(t *testing.T) {
+	root := newCacheTestRoot(cacheFixtureStore(t), authedLoader(), &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+	_, err := executeCommand(root, "cache", "clear", "--kind", "bogus")
+	if err == nil || !strings.Contains(err.Error(), `unknown cache kind "bogus"`) {
+		t.Fatalf("err = %v, want unknown kind", err)
+	}
+	if c := classifyError(err, true); c.code != codeUsage {
+		t.Errorf("code = %s, want %s", c.code, codeUsage)
+	}
+}
+
+func TestCacheWarm(t *testing.T) {
+	elig := &mockEligibilityLister{listFunc: func(ctx context.Context, csp models.CSP) (*models.EligibilityResponse, error) {
+		if csp == models.CSPGCP {
+			return nil, errors.New("gcp unavailable")
+		}
+		return &models.EligibilityResponse{Response: []models.EligibleTarget{{CSP: csp}}}, nil
+	}}
+	groups := &mockGroupsEligibilityLister{response: &models.GroupsEligibilityResponse{Response: []models.GroupsEligibleTarget{{}, {}}}}
+
+	root := newCacheTestRoot(cache.NewStore(t.TempDir(), time.Hour), authedLoader(), elig, groups)
+	stdout, _, err := executeCommandStreams(root, "cache", "warm", "--output", "json")
+	if err == nil || !strings.Contains(err.Error(), "1 of 4 fetches failed") {
+		t.Fatalf("err = %v, want one failed fetch", err)
+	}
+
+	var out []cacheWarmOutput
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	want := []cacheWarmOutput{
+		{Kind: cache.KindEligibility, Provider: "azure", Items: 1},
+		{Kind: cache.KindEligibility, Provider: "aws", Items: 1},
+		{Kind: cache.KindEligibility, Provider: "gcp", Error: "gcp unavailable"},
+		{Kind: cache.KindGroups, Provider: "azure", Items: 2},
+	}
+	if len(out) != len(want) {
+		t.Fatalf("out = %+v, want %+v", out, want)
+	}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Errorf("out[%d] = %+v, want %+v", i, out[i], want[i])
+		}
+	}
+}
+
+func TestCacheWarm_NotAuthenticated(t *testing.T) {
+	auth := &mockAuthLoader{loadErr: errors.New("no token")}
+	root := newCacheTestRoot(cache.NewStore(t.TempDir(), time.Hour), auth, &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+	_, err := executeCommand(root, "cache", "warm")
+	if err == nil || !strings.Contains(err.Error(), "grant login") {
+		t.Fatalf("err = %v, want login hint", err)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	store := cache.NewStore(t.TempDir(), time.Hour)
+	r := cache.NewStatsRecorder()
+	for _, hit := range []bool{true, true, true, false} {
+		r.RecordLookup(store, "eligibility_azure", hit)
+	}
+	r.RecordStale(store, "groups_eligibility_azure")
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	root := newCacheTestRoot(store, authedLoader(), &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+	stdout, _, err := executeCommandStreams(root, "cache", "stats", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out cacheStatsOutput
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	want := []cacheKindStatsOutput{
+		{Kind: cache.KindEligibility, Hits: 3, Misses: 1, HitRate: 0.75},
+		{Kind: cache.KindGroups, Stale: 1},
+	}
+	if out.Since.IsZero() || len(out.Kinds) != len(want) {
+		t.Fatalf("out = %+v, want %+v", out, want)
+	}
+	for i := range want {
+		if out.Kinds[i] != want[i] {
+			t.Errorf("kinds[%d] = %+v, want %+v", i, out.Kinds[i], want[i])
+		}
+	}
+
+	root = newCacheTestRoot(store, authedLoader(), &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+	output, err := executeCommand(root, "cache", "stats")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "HIT RATE") || !strings.Contains(output, "75%") {
+		t.Errorf("output = %q", output)
+	}
+
+	root = newCacheTestRoot(store, authedLoader(), &mockEligibilityLister{}, &mockGroupsEligibilityLister{})
+	if _, err := executeCommand(root, "cache", "stats", "--reset"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st := cache.LoadStats(store); len(st.Kinds) != 0 {
+		t.Errorf("stats after reset = %+v", st)
+	}
+}
@@ -6,6 +6,8 @@ func init() {
 		NewLogoutCommand(),
 		NewConfigureCommand(),
 		NewStatusCommand(),
+		NewPromptCommand(),
+		NewSchemaCommand(),
 		NewVersionCommand(),
 		NewFavoritesCommand(),
 		NewEnvCommand(),
@@ -13,5 +15,8 @@ func init() {
 		NewUpdateCommand(),
 		NewListCommand(),
 		NewRequestCommand(),
+		NewCacheCommand(),
+		NewProfilesCommand(),
+		NewConfigCommand(),
 	)
 }
@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// runEditor opens path in the user's editor and waits for it to exit.
+// Overridden in tests.
+var runEditor = func(cmd *cobra.Command, path string) error {
+	editor := editorCommand()
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = cmd.OutOrStdout()
+	c.Stderr = cmd.ErrOrStderr()
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", strings.Join(editor, " "), err)
+	}
+	return nil
+}
+
+// editorCommand returns $VISUAL or $EDITOR split into words, or the platform
+// default editor.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if words := strings.Fields(os.Getenv(env)); len(words) > 0 {
+			return words
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// NewConfigCommand creates the config parent command with subcommands.
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and change the grant config file",
+		Long: `View and change settings in ~/.grant/config.yaml (or $GRANT_CONFIG) without
+editing it by hand. Values are checked before anything is written.
+
+The effective config merges, lowest precedence first, /etc/grant/config.yaml
+(or $GRANT_SYSTEM_CONFIG), the team file ($GRANT_TEAM_CONFIG or team_config),
+the user file and the nearest .grant.yaml above the working directory. set,
+unset and edit change only the user file.
+
+Keys: ` + strings.Join(config.Keys(), ", ") + `
+
+Favorites are managed with 'grant favorites'.
+
+Examples:
+  grant config get cache_ttl
+  grant config set default_provider aws
+  grant config set cache.groups.ttl 168h
+  grant config unset cache_ttl
+  grant config view
+  grant config validate
+  grant config edit
+  grant config upgrade`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the effective value of a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigGet(cmd, args[0])
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a setting",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigSet(cmd, args[0], args[1])
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a setting so its default applies",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigUnset(cmd, args[0])
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "view",
+		Short: "Show every effective setting and where it comes from",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigView(cmd)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate [file]",
+		Short: "Check the config file, including favorites",
+		Long: `Check a config file the way grant loads it, and additionally report unknown
+keys with their line and check default_provider and every favorite's type,
+provider, target, role and group. All problems are reported at once. Without a file, checks every config layer
+grant uses (system, team, user and project) and their merge.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return runConfigValidate(cmd, path)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "edit",
+		Short: "Edit the config file in $EDITOR",
+		Long: `Open a copy of the user config file in $VISUAL or $EDITOR. When the editor
+exits the copy is validated like 'grant config validate' and saved only if it
+is valid; otherwise the config file is left unchanged.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigEdit(cmd)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the config file to the current schema version",
+		Long: `Rewrite the user config file in the current schema version, first copying it
+to config.yaml.bak-<timestamp>. Older files are otherwise migrated in memory
+whenever grant reads them, and upgraded in place the next time grant changes
+the file.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigUpgrade(cmd)
+		},
+	})
+	return cmd
+}
+
+// loadConfigSetting returns the effective value and source of key.
+func loadConfigSetting(key string) (config.Setting, error) {
+	l, err := config.LoadLayers()
+	if err != nil {
+		return config.Setting{}, err
+	}
+	if _, err := config.Get(l.Config, key); err != nil {
+		return config.Setting{}, withCode(codeUsage, err)
+	}
+	for _, s := range l.Settings() {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	return config.Setting{}, fmt.Errorf("config key %q not found", key)
+}
+
+func runConfigGet(cmd *cobra.Command, key string) error {
+	s, err := loadConfigSetting(key)
+	if err != nil {
+		return err
+	}
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), configValueOutput(s))
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), s.Value)
+	return nil
+}
+
+// updateConfig loads the user config, applies change and saves the result.
+func updateConfig(change func(cfg *config.Config) error) error {
+	cfg, cfgPath, err := config.LoadUserWithPath()
+	if err != nil {
+		return err
+	}
+	if err := change(cfg); err != nil {
+		return err
+	}
+	if err := config.Save(cfg, cfgPath); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, key, value string) error {
+	if key == "profile" {
+		if err := validateProfileName(value); err != nil {
+			return withCode(codeUsage, err)
+		}
+	}
+	err := updateConfig(func(cfg *config.Config) error {
+		if err := config.Set(cfg, key, value); err != nil {
+			return withCode(codeUsage, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Set %s = %s\n", key, value)
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, key string) error {
+	err := updateConfig(func(cfg *config.Config) error {
+		if err := config.Unset(cfg, key); err != nil {
+			return withCode(codeUsage, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Unset %s\n", key)
+	return nil
+}
+
+func runConfigView(cmd *cobra.Command) error {
+	l, err := config.LoadLayers()
+	if err != nil {
+		return err
+	}
+	settings := l.Settings()
+
+	if isStructuredOutput() {
+		out := make([]configValueOutput, len(settings))
+		for i, s := range settings {
+			out[i] = configValueOutput(s)
+		}
+		return writeOutput(cmd.OutOrStdout(), out)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, s := range settings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
+	}
+	return w.Flush()
+}
+
+func runConfigValidate(cmd *cobra.Command, path string) error {
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+		if _, err := loadValidConfig(path); err != nil {
+			return fmt.Errorf("invalid config %s:\n%w", path, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", path)
+		return nil
+	}
+
+	// Every layer on its own, then the merge.
+	l, err := config.LoadLayers()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, layer := range l.Layers {
+		if layer.Config == nil {
+			continue
+		}
+		err := config.Validate(layer.Config)
+		if layer.Name != config.LayerEnv {
+			err = errors.Join(err, checkConfigKeys(layer.Path))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s config %s:\n%w", layer.Name, layer.Path, err))
+			continue
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", layer.Path)
+	}
+	if len(errs) == 0 {
+		if err := config.Validate(l.Config); err != nil {
+			errs = append(errs, fmt.Errorf("invalid merged config:\n%w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadValidConfig loads the config at path and runs the full validation.
+func loadValidConfig(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := errors.Join(config.Validate(cfg), checkConfigKeys(path)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// checkConfigKeys reports the keys of the config file at path grant does not
+// know.
+func checkConfigKeys(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	return config.CheckKeys(data)
+}
+
+func runConfigUpgrade(cmd *cobra.Command) error {
+	cfgPath, err := config.ConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine config path: %w", err)
+	}
+	backup, err := config.UpgradeFile(cfgPath)
+	if err != nil {
+		return err
+	}
+	if backup != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "Upgraded %s to version %d, previous file saved to %s\n", cfgPath, config.CurrentVersion, backup)
+		return nil
+	}
+	// Nothing was upgraded: report why a file was left alone.
+	if _, err := config.Load(cfgPath); err != nil {
+		return fmt.Errorf("failed to load config %s: %w", cfgPath, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s is already at version %d\n", cfgPath, config.CurrentVersion)
+	return nil
+}
+
+func runConfigEdit(cmd *cobra.Command) error {
+	cfgPath, err := config.ConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine config path: %w", err)
+	}
+
+	// Edit a copy: the config file changes only once the edit validates.
+	tmp, err := os.CreateTemp("", "grant-config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create a copy of the config: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Close()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	data, err := os.ReadFile(cfgPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		err = config.Save(config.DefaultConfig(), tmpPath)
+	case err == nil:
+		// Copy the text as is, so a config grant cannot load can still be
+		// repaired here.
+		err = os.WriteFile(tmpPath, data, 0o600)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create a copy of the config: %w", err)
+	}
+
+	if err := runEditor(cmd, tmpPath); err != nil {
+		return err
+	}
+
+	if _, err := loadValidConfig(tmpPath); err != nil {
+		return fmt.Errorf("edited config is invalid, %s was not changed:\n%w", cfgPath, err)
+	}
+	// Save the text as edited, comments and layout included.
+	edited, err := os.ReadFile(tmpPath)
+	if err == nil {
+		err = config.WriteFile(cfgPath, edited)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Config saved to %s\n", cfgPath)
+	return nil
+}
@@ -0,0 +1,271 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// writeConfigFile points GRANT_CONFIG at a file holding content and returns
+// its path.
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(cfgPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GRANT_CONFIG", cfgPath)
+	return cfgPath
+}
+
+func newConfigTestRoot() *cobra.Command {
+	root := newTestRootCommand()
+	root.AddCommand(NewConfigCommand())
+	return root
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestConfigSetGetUnset(t *testing.T) {
+	cfgPath := writeConfigFile(t, "default_provider: azure\nfavorites:\n  prod:\n    provider: aws\n    target: Prod\n    role: Admin\n")
+
+	if _, err := executeCommand(newConfigTestRoot(), "config", "set", "cache_ttl", "30m"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	output, err := executeCommand(newConfigTestRoot(), "config", "get", "cache_ttl")
+	if err != nil || strings.TrimSpace(output) != "30m" {
+		t.Errorf("get cache_ttl = %q, %v; want 30m", output, err)
+	}
+
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CacheTTL != "30m" || len(cfg.Favorites) != 1 {
+		t.Errorf("config after set = %+v, want cache_ttl set and favorites kept", cfg)
+	}
+
+	if _, err := executeCommand(newConfigTestRoot(), "config", "unset", "cache_ttl"); err != nil {
+		t.Fatalf("unset: %v", err)
+	}
+	stdout, _, err := executeCommandStreams(newConfigTestRoot(), "config", "get", "cache_ttl", "--output", "json")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	var got configValueOutput
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if want := (configValueOutput{Key: "cache_ttl", Value: "4h", Source: config.SourceDefault}); got != want {
+		t.Errorf("get = %+v, want %+v", got, want)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestConfigSet_Invalid(t *testing.T) {
+	content := fmt.Sprintf("version: %d\ndefault_provider: aws\n", config.CurrentVersion)
+	cfgPath := writeConfigFile(t, content)
+
+	for _, args := range [][]string{
+		{"config", "set", "cache_ttl", "0"},
+		{"config", "set", "default_provider", "oracle"},
+		{"config", "set", "no_such_key", "1"},
+		{"config", "set", "profile", "../x"},
+	} {
+		_, err := executeCommand(newConfigTestRoot(), args...)
+		if err == nil {
+			t.Errorf("%v succeeded, want an error", args)
+			continue
+		}
+		if got := classifyError(err, true); got.code != codeUsage {
+			t.Errorf("%v: code = %s, want %s", args, got.code, codeUsage)
+		}
+	}
+	data, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Errorf("config rewritten by rejected values:\n%s", data)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestConfigView(t *testing.T) {
+	cfgPath := writeConfigFile(t, "default_provider: aws\n")
+
+	output, err := executeCommand(newConfigTestRoot(), "config", "view")
+	if err != nil {
+		t.Fatalf("view: %v", err)
+	}
+	for _, want := range []string{"KEY", "SOURCE", "default_provider", cfgPath, "cache.sessions.ttl"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestConfigValidate(t *testing.T) {
+	writeConfigFile(t, "default_provider: aws\n")
+	if _, err := executeCommand(newConfigTestRoot(), "config", "validate"); err != nil {
+		t.Errorf("validate valid config: %v", err)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.yaml")
+	content := "favorites:\n  grp:\n    type: groups\n    provider: azure\n  x:\n    provider: oci\n    target: T\n    role: R\n"
+	if err := os.WriteFile(bad, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := executeCommand(newConfigTestRoot(), "config", "validate", bad)
+	if err == nil {
+		t.Fatal("validate succeeded, want errors")
+	}
+	for _, want := range []string{`favorite "grp": group is required`, `favorite "x": invalid provider "oci"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q:\n%v", want, err)
+		}
+	}
+
+	writeConfigFile(t, fmt.Sprintf("version: %d\ncache_tll: 2h\n", config.CurrentVersion))
+	_, err = executeCommand(newConfigTestRoot(), "config", "validate")
+	if err == nil || !strings.Contains(err.Error(), `line 2: unknown key "cache_tll"`) {
+		t.Errorf("validate with a misspelt key = %v, want it reported with its line", err)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestConfigUpgrade(t *testing.T) {
+	legacy := "# mine\ncache_ttl: 2h\n"
+	cfgPath := writeConfigFile(t, legacy)
+
+	if _, err := executeCommand(newConfigTestRoot(), "config", "get", "cache_ttl"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if data, _ := os.ReadFile(cfgPath); string(data) != legacy {
+		t.Fatalf("a read rewrote the config:\n%s", data)
+	}
+
+	output, err := executeCommand(newConfigTestRoot(), "config", "upgrade")
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if !strings.Contains(output, fmt.Sprintf("to version %d", config.CurrentVersion)) {
+		t.Errorf("output = %q", output)
+	}
+	data, _ := os.ReadFile(cfgPath)
+	if !strings.Contains(string(data), fmt.Sprintf("version: %d", config.CurrentVersion)) || !strings.Contains(string(data), "# mine") {
+		t.Errorf("upgraded config:\n%s", data)
+	}
+	if backups, _ := filepath.Glob(cfgPath + ".bak-*"); len(backups) != 1 {
+		t.Errorf("backups = %v, want one", backups)
+	}
+
+	output, err = executeCommand(newConfigTestRoot(), "config", "upgrade")
+	if err != nil || !strings.Contains(output, "already at version") {
+		t.Errorf("second upgrade = %q, %v; want nothing to do", output, err)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG and replaces runEditor.
+func TestConfigEdit(t *testing.T) {
+	original := "default_provider: azure\ncache_ttl: garbage\n"
+
+	tests := []struct {
+		name    string
+		edit    string
+		editErr error
+		wantErr string
+		want    string
+	}{
+		{name: "valid edit is saved", edit: "default_provider: gcp\n", want: "gcp"},
+		{name: "comments are kept", edit: "# my tenant\ndefault_provider:   gcp # not aws\n", want: "gcp"},
+		{name: "invalid edit rolls back", edit: "default_provider: oracle\n", wantErr: "edited config is invalid"},
+		{name: "unknown key rolls back", edit: "default_provider: gcp\ncache_tll: 2h\n", wantErr: `line 2: unknown key "cache_tll"`},
+		{name: "editor failure rolls back", editErr: errors.New("exit status 1"), wantErr: "exit status 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgPath := writeConfigFile(t, original)
+			saved := runEditor
+			defer func() { runEditor = saved }()
+			runEditor = func(_ *cobra.Command, path string) error {
+				data, err := os.ReadFile(path)
+				if err != nil || string(data) != original {
+					t.Errorf("editor opened %q, %v; want a copy of the config", data, err)
+				}
+				if tt.editErr != nil {
+					return tt.editErr
+				}
+				return os.WriteFile(path, []byte(tt.edit), 0o600)
+			}
+
+			_, err := executeCommand(newConfigTestRoot(), "config", "edit")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("edit = %v, want error containing %q", err, tt.wantErr)
+				}
+				data, _ := os.ReadFile(cfgPath)
+				if string(data) != original {
+					t.Errorf("config changed after a failed edit:\n%s", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("edit: %v", err)
+			}
+			cfg, err := config.Load(cfgPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.DefaultProvider != tt.want {
+				t.Errorf("default_provider = %q, want %q", cfg.DefaultProvider, tt.want)
+			}
+			if data, _ := os.ReadFile(cfgPath); string(data) != tt.edit {
+				t.Errorf("saved config = %q, want the edited text %q", data, tt.edit)
+			}
+		})
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG and GRANT_* overrides for the process.
+func TestConfig_EnvOverrides(t *testing.T) {
+	writeConfigFile(t, "cache_ttl: 2h\n")
+	t.Setenv("GRANT_CACHE_TTL", "15m")
+	t.Setenv("GRANT_OUTPUT", "json")
+
+	stdout, _, err := executeCommandStreams(newConfigTestRoot(), "config", "get", "cache_ttl")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	var got configValueOutput
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("GRANT_OUTPUT=json did not select JSON: %v\n%s", err, stdout)
+	}
+	if want := (configValueOutput{Key: "cache_ttl", Value: "15m", Source: "env GRANT_CACHE_TTL"}); got != want {
+		t.Errorf("get = %+v, want %+v", got, want)
+	}
+
+	// --output beats GRANT_OUTPUT.
+	output, err := executeCommand(newConfigTestRoot(), "config", "get", "cache_ttl", "--output", "text")
+	if err != nil || strings.TrimSpace(output) != "15m" {
+		t.Errorf("get --output text = %q, %v; want 15m", output, err)
+	}
+
+	t.Setenv("GRANT_OUTPUT", "xml")
+	_, err = executeCommand(newConfigTestRoot(), "config", "get", "cache_ttl")
+	if err == nil || !strings.Contains(err.Error(), "GRANT_OUTPUT") {
+		t.Fatalf("err = %v, want an error naming GRANT_OUTPUT", err)
+	}
+	if got := classifyError(err, true); got.code != codeUsage {
+		t.Errorf("code = %s, want %s", got.code, codeUsage)
+	}
+}
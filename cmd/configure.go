@@ -26,6 +26,9 @@ This command creates two configuration files:
 - SDK profile at ~/.idsec/profiles/grant (override with IDSEC_PROFILES_FOLDER)
 - App config at ~/.grant/config.yaml
 
+With --profile <name> (or GRANT_PROFILE) it creates or updates the named
+profile instead, e.g. for another tenant, without touching the current one.
+
 The Identity URL is optional — the SDK can auto-discover it from your username.
 If provided, it must be HTTPS (e.g., https://abc1234.id.cyberark.cloud).
 
@@ -87,9 +90,10 @@ func runConfigure(cmd *cobra.Command, saver profileSaver, tenantURL, username st
 	}
 
 	// Create SDK profile
+	name := activeProfile()
 	profile := &models.IdsecProfile{
-		ProfileName:        "grant",
-		ProfileDescription: "SCA CLI Profile",
+		ProfileName:        name,
+		ProfileDescription: grantProfileDescription,
 		AuthProfiles: map[string]*authmodels.IdsecAuthProfile{
 			"isp": {
 				Username:   username,
@@ -104,15 +108,22 @@ func runConfigure(cmd *cobra.Command, saver profileSaver, tenantURL, username st
 	}
 
 	// Save SDK profile
+	previous := loadCacheNamespace()
 	log.Info("Saving profile...")
 	if err := saver.SaveProfile(profile); err != nil {
 		return fmt.Errorf("failed to save profile: %w", err)
 	}
 
+	// A different tenant or user gets a fresh namespace anyway; drop the old
+	// one rather than leave another identity's eligibility on disk.
+	if current := namespaceOf(profile.ProfileName, profile); current.Name() != previous.Name() {
+		invalidateCache(previous)
+	}
+
 	// Get profile directory for success message. Must use the SDK's own resolver
 	// so the printed path matches what the profile loader will later read.
 	profileDir := profiles.GetProfilesFolder()
-	profilePath := filepath.Join(profileDir, "grant")
+	profilePath := filepath.Join(profileDir, name)
 
 	// Merge onto the existing app config so a re-run keeps everything the user
 	// was not just prompted for (favorites, default_provider, cache_ttl).
@@ -132,13 +143,14 @@ func runConfigure(cmd *cobra.Command, saver profileSaver, tenantURL, username st
 			cfgPath, err)
 		cfg = config.DefaultConfig()
 	}
-	cfg.Profile = "grant"
+	// A profile chosen with --profile or GRANT_PROFILE is configured alongside
+	// the current one; 'grant profiles use' switches to it.
+	if explicitProfile() == "" {
+		cfg.Profile = name
+	}
 	if cfg.Favorites == nil {
 		cfg.Favorites = make(map[string]config.Favorite)
 	}
-	if cfg.DefaultProvider == "" {
-		cfg.DefaultProvider = config.DefaultConfig().DefaultProvider
-	}
 
 	// Save app config
 	log.Info("Saving config...")
@@ -149,6 +161,9 @@ func runConfigure(cmd *cobra.Command, saver profileSaver, tenantURL, username st
 	// Success message
 	fmt.Fprintf(cmd.OutOrStdout(), "Profile saved to %s\n", profilePath)
 	fmt.Fprintf(cmd.OutOrStdout(), "Config saved to %s\n", cfgPath)
+	if cfg.Profile != name {
+		fmt.Fprintf(cmd.OutOrStdout(), "Use it with --profile %s, or make it current with 'grant profiles use %s'\n", name, name)
+	}
 
 	return nil
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -245,8 +246,8 @@ func TestConfigureCommand(t *testing.T) {
 					if cfg.Profile != "grant" {
 						t.Errorf("expected Profile='grant', got %q", cfg.Profile)
 					}
-					if cfg.DefaultProvider != "azure" {
-						t.Errorf("expected DefaultProvider='azure', got %q", cfg.DefaultProvider)
+					if cfg.DefaultProvider != "" {
+						t.Errorf("expected DefaultProvider unset, got %q", cfg.DefaultProvider)
 					}
 					if cfg.Favorites == nil {
 						t.Error("expected Favorites to be initialized")
@@ -552,8 +553,8 @@ func TestConfigure_PreservesExistingConfigOnRerun(t *testing.T) {
 	if !ok {
 		t.Fatalf("favorites = %v after configure, want the %q favorite preserved", cfg.Favorites, "prod")
 	}
-	want := config.Favorite{Provider: "azure", Target: "Prod-EastUS", Role: "Contributor"}
-	if fav != want {
+	want := config.Favorite{Type: config.FavoriteTypeCloud, Provider: "azure", Target: "Prod-EastUS", Role: "Contributor"}
+	if !reflect.DeepEqual(fav, want) {
 		t.Errorf("favorites[%q] = %+v, want %+v", "prod", fav, want)
 	}
 	if cfg.Profile != "grant" {
@@ -590,8 +591,8 @@ func TestConfigure_FirstRunMergesOntoDefaults(t *testing.T) {
 	if cfg.Profile != "grant" {
 		t.Errorf("profile = %q, want %q", cfg.Profile, "grant")
 	}
-	if cfg.DefaultProvider != "azure" {
-		t.Errorf("default_provider = %q, want the default %q", cfg.DefaultProvider, "azure")
+	if cfg.DefaultProvider != "" {
+		t.Errorf("default_provider = %q, want it left unset so every provider is queried", cfg.DefaultProvider)
 	}
 	if cfg.Favorites == nil {
 		t.Error("favorites = nil, want an initialized map")
@@ -664,7 +665,7 @@ func TestConfigure_UnloadableConfigIsRebuiltFromDefaults(t *testing.T) {
 	if len(cfg.Favorites) != 0 {
 		t.Errorf("favorites = %v after configure, want them gone (unreadable config cannot be merged)", cfg.Favorites)
 	}
-	if cfg.DefaultProvider != "azure" {
-		t.Errorf("default_provider = %q after configure, want the hardcoded %q (the user's \"aws\" is lost)", cfg.DefaultProvider, "azure")
+	if cfg.DefaultProvider != "" {
+		t.Errorf("default_provider = %q after configure, want the default, unset (the user's \"aws\" is lost)", cfg.DefaultProvider)
 	}
 }
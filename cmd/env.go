@@ -130,8 +130,10 @@ func runEnvWithDeps(
 		return err
 	}
 
-	// Record session timestamp for remaining-time tracking (best-effort)
+	// Record session timestamp for remaining-time tracking and the session
+	// for 'grant prompt' (best-effort)
 	recordSessionTimestamp(res.result.SessionID)
+	rememberSession(knownCloudSession(res))
 
 	// Defense in depth: AWS itself returning no credentials.
 	if res.result.AccessCredentials == nil {
@@ -143,8 +145,8 @@ func runEnvWithDeps(
 		return fmt.Errorf("failed to parse access credentials: %w", err)
 	}
 
-	if isJSONOutput() {
-		return writeJSON(cmd.OutOrStdout(), awsCredentialOutput{
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), awsCredentialOutput{
 			AccessKeyID:     awsCreds.AccessKeyID,
 			SecretAccessKey: awsCreds.SecretAccessKey,
 			SessionToken:    awsCreds.SessionToken,
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/aaearon/grant-cli/internal/sdkclient"
+)
+
+// errorCode is the stable, machine-readable class of a failed command. It is
+// part of the output contract: wrappers branch on it instead of the message.
+type errorCode string
+
+const (
+	codeError                errorCode = "ERROR"
+	codeUsage                errorCode = "USAGE"
+	codeNotAuthenticated     errorCode = "NOT_AUTHENTICATED"
+	codeTargetNotFound       errorCode = "TARGET_NOT_FOUND"
+	codeNotEligible          errorCode = "NOT_ELIGIBLE"
+	codeElevationFailed      errorCode = "ELEVATION_FAILED"
+	codeAPIError             errorCode = "API_ERROR"
+	codeTimeout              errorCode = "TIMEOUT"
+	codeRevocationIncomplete errorCode = "REVOCATION_INCOMPLETE"
+	codeRequirementNotMet    errorCode = "REQUIREMENT_NOT_MET"
+)
+
+// Process exit codes. 1 remains the generic failure, so the documented revoke
+// and status --require contracts are unchanged.
+const (
+	exitFailure          = 1 // give up: the operation ran and failed
+	exitUsage            = 2 // fix the command line
+	exitNotAuthenticated = 3 // run grant login, then retry
+	exitNotFound         = 4 // the target, group or favorite does not exist or is not eligible
+	exitAPIError         = 5 // the service returned an HTTP error; see httpStatus
+	exitTimeout          = 6 // a request timed out; retrying may help
+)
+
+// exitCodes maps every errorCode to its process exit code.
+var exitCodes = map[errorCode]int{
+	codeError:                exitFailure,
+	codeUsage:                exitUsage,
+	codeNotAuthenticated:     exitNotAuthenticated,
+	codeTargetNotFound:       exitNotFound,
+	codeNotEligible:          exitNotFound,
+	codeElevationFailed:      exitFailure,
+	codeAPIError:             exitAPIError,
+	codeTimeout:              exitTimeout,
+	codeRevocationIncomplete: exitFailure,
+	codeRequirementNotMet:    exitFailure,
+}
+
+// cliError attaches an errorCode, and what the service said about the failure,
+// to an error. Its message is the wrapped error's, so text output is unchanged.
+type cliError struct {
+	code       errorCode
+	err        error
+	httpStatus int
+	info       *models.ErrorInfo
+}
+
+func (e *cliError) Error() string { return e.err.Error() }
+
+func (e *cliError) Unwrap() error { return e.err }
+
+// withCode tags err with code.
+func withCode(code errorCode, err error) error {
+	return &cliError{code: code, err: err}
+}
+
+// usageErrorf returns an error for an invalid combination of flags or
+// arguments that cobra's own validation cannot express.
+func usageErrorf(format string, a ...any) error {
+	return withCode(codeUsage, fmt.Errorf(format, a...))
+}
+
+// notAuthenticatedError wraps an authentication failure with the login hint.
+func notAuthenticatedError(err error) error {
+	return withCode(codeNotAuthenticated, fmt.Errorf("not authenticated, run 'grant login' first: %w", err))
+}
+
+// elevationFailedError reports an elevation the service refused, keeping the
+// service's ErrorInfo for the structured error output.
+func elevationFailedError(info *models.ErrorInfo) error {
+	return &cliError{
+		code: codeElevationFailed,
+		err:  fmt.Errorf("elevation failed: %s - %s\n%s", info.Code, info.Message, info.Description),
+		info: info,
+	}
+}
+
+// classifyError resolves the errorCode of a command failure. Explicitly tagged
+// errors win; otherwise sentinel errors, API status errors and timeouts are
+// recognized anywhere in the chain. argsValidated is false when the command
+// failed before PersistentPreRunE, i.e. in cobra's flag or argument checks.
+func classifyError(err error, argsValidated bool) *cliError {
+	var tagged *cliError
+	if errors.As(err, &tagged) {
+		c := *tagged
+		c.err = err
+		if c.httpStatus == 0 {
+			c.httpStatus = httpStatusOf(err)
+		}
+		if c.info == nil {
+			c.info = apiErrorInfo(err)
+		}
+		return &c
+	}
+
+	c := &cliError{code: codeError, err: err, httpStatus: httpStatusOf(err), info: apiErrorInfo(err)}
+	switch {
+	case errors.Is(err, errRequirementNotMet):
+		c.code = codeRequirementNotMet
+	case errors.Is(err, errRevocationIncomplete), errors.Is(err, errRevocationNotConfirmed):
+		c.code = codeRevocationIncomplete
+	case c.httpStatus == http.StatusUnauthorized:
+		c.code = codeNotAuthenticated
+	case c.httpStatus != 0:
+		c.code = codeAPIError
+	case isTimeout(err):
+		c.code = codeTimeout
+	case !argsValidated:
+		c.code = codeUsage
+	}
+	return c
+}
+
+// httpStatusOf returns the HTTP status of an API error in err's chain, or 0.
+func httpStatusOf(err error) int {
+	var se *sdkclient.StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode
+	}
+	return 0
+}
+
+// apiErrorInfo returns the service's description of an API error in err's
+// chain, parsed from the response body, or nil.
+func apiErrorInfo(err error) *models.ErrorInfo {
+	var se *sdkclient.StatusError
+	if !errors.As(err, &se) {
+		return nil
+	}
+	d := se.Detail()
+	if d == nil {
+		return nil
+	}
+	return &models.ErrorInfo{Code: d.Code, Message: d.Message, Description: d.Description, Link: d.Link}
+}
+
+// isTimeout reports whether err is a deadline or network timeout.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// exitCode returns the process exit code for a classified error.
+func (e *cliError) exitCode() int {
+	if code, ok := exitCodes[e.code]; ok {
+		return code
+	}
+	return exitFailure
+}
+
+// output returns the structured error envelope for e.
+func (e *cliError) output() errorOutput {
+	out := errorOutput{Error: errorDetail{
+		Code:       string(e.code),
+		Message:    e.err.Error(),
+		ExitCode:   e.exitCode(),
+		HTTPStatus: e.httpStatus,
+	}}
+	if e.info != nil {
+		out.Error.ErrorInfo = toErrorInfoOutput(e.info)
+	}
+	return out
+}
+
+// toErrorInfoOutput converts the service's ErrorInfo for output.
+func toErrorInfoOutput(info *models.ErrorInfo) *errorInfoOutput {
+	return &errorInfoOutput{
+		Code:        info.Code,
+		Message:     info.Message,
+		Description: info.Description,
+		Link:        info.Link,
+	}
+}
+
+// reportError writes a failed command's error to w: the error envelope as JSON
+// for any structured --output format (JSON is also valid YAML, and a CSV or
+// template rendering of an error would not be parseable), otherwise the
+// message and, when it would help, the --verbose hint. With --output ndjson the
+// envelope's detail ends the event stream as an error event instead.
+func reportError(w io.Writer, e *cliError, showHint bool) {
+	if outputFormat == formatNDJSON {
+		if !eventsEnabled() {
+			startEvents(w)
+			defer startEvents(nil)
+		}
+		emitEvent(eventError, e.output().Error)
+		return
+	}
+	if isStructuredOutput() {
+		if err := writeJSON(w, e.output()); err == nil {
+			return
+		}
+	}
+	fmt.Fprintln(w, e.err)
+	if showHint {
+		fmt.Fprintln(w, "Hint: re-run with --verbose for more details")
+	}
+}
@@ -0,0 +1,200 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/aaearon/grant-cli/internal/sdkclient"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+)
+
+func TestClassifyError(t *testing.T) {
+	apiErr := func(status int) error {
+		return fmt.Errorf("failed to list sessions: %w", &sdkclient.StatusError{Operation: "sessions", StatusCode: status, Body: "nope"})
+	}
+
+	tests := []struct {
+		name          string
+		err           error
+		argsValidated bool
+		wantCode      errorCode
+		wantExit      int
+		wantStatus    int
+	}{
+		{"untagged error", errors.New("boom"), true, codeError, exitFailure, 0},
+		{"cobra validation failure", errors.New(`unknown flag: --nope`), false, codeUsage, exitUsage, 0},
+		{"tagged usage error", usageErrorf("--all cannot be used with session ID arguments"), true, codeUsage, exitUsage, 0},
+		{"not authenticated", notAuthenticatedError(errors.New("no token")), true, codeNotAuthenticated, exitNotAuthenticated, 0},
+		{"tagged not found", withCode(codeTargetNotFound, errors.New("missing")), true, codeTargetNotFound, exitNotFound, 0},
+		{"not eligible", withCode(codeNotEligible, errors.New("none")), true, codeNotEligible, exitNotFound, 0},
+		{"API error", apiErr(500), true, codeAPIError, exitAPIError, 500},
+		{"API 401 means re-login", apiErr(401), true, codeNotAuthenticated, exitNotAuthenticated, 401},
+		{"tag wins over the API status", withCode(codeNotEligible, apiErr(403)), true, codeNotEligible, exitNotFound, 403},
+		{"deadline exceeded", fmt.Errorf("failed to fetch: %w", context.DeadlineExceeded), true, codeTimeout, exitTimeout, 0},
+		{"requirement not met", fmt.Errorf("%w (no_matching_session): x", errRequirementNotMet), true, codeRequirementNotMet, exitFailure, 0},
+		{"revocation incomplete", errRevocationIncomplete, true, codeRevocationIncomplete, exitFailure, 0},
+		{"revocation not confirmed", errRevocationNotConfirmed, true, codeRevocationIncomplete, exitFailure, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyError(tt.err, tt.argsValidated)
+			if got.code != tt.wantCode || got.exitCode() != tt.wantExit || got.httpStatus != tt.wantStatus {
+				t.Errorf("classifyError() = %s/exit %d/http %d, want %s/exit %d/http %d",
+					got.code, got.exitCode(), got.httpStatus, tt.wantCode, tt.wantExit, tt.wantStatus)
+			}
+			if got.Error() != tt.err.Error() {
+				t.Errorf("message = %q, want it unchanged: %q", got.Error(), tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestExitCodes_CoverEveryCode(t *testing.T) {
+	for _, code := range []errorCode{
+		codeError, codeUsage, codeNotAuthenticated, codeTargetNotFound, codeNotEligible,
+		codeElevationFailed, codeAPIError, codeTimeout, codeRevocationIncomplete, codeRequirementNotMet,
+	} {
+		if _, ok := exitCodes[code]; !ok {
+			t.Errorf("no exit code for %s", code)
+		}
+	}
+}
+
+// TestClassifyError_APIErrorInfo checks that any API failure, not only a
+// refused elevation, carries the service's error detail into the envelope.
+func TestClassifyError_APIErrorInfo(t *testing.T) {
+	body := `{"code":"EL0001","message":"not allowed","description":"missing permission"}`
+	for _, err := range []error{
+		fmt.Errorf("failed to fetch eligible targets: %w", &sdkclient.StatusError{Operation: "list eligibility request", StatusCode: 403, Body: body}),
+		fmt.Errorf("failed to list sessions: %w", &sdkclient.StatusError{Operation: "list sessions request", StatusCode: 403, Body: body}),
+		withCode(codeTargetNotFound, fmt.Errorf("revoke: %w", &sdkclient.StatusError{Operation: "revoke sessions request", StatusCode: 403, Body: `{"errorInfo":` + body + `}`})),
+	} {
+		got := classifyError(err, true).output().Error
+		if got.HTTPStatus != 403 || got.ErrorInfo == nil ||
+			*got.ErrorInfo != (errorInfoOutput{Code: "EL0001", Message: "not allowed", Description: "missing permission"}) {
+			t.Errorf("%v: envelope = %+v, errorInfo %+v", err, got, got.ErrorInfo)
+		}
+	}
+
+	plain := &sdkclient.StatusError{Operation: "list sessions request", StatusCode: 502, Body: "Bad Gateway"}
+	if got := classifyError(plain, true).output().Error; got.ErrorInfo != nil || got.HTTPStatus != 502 {
+		t.Errorf("non-JSON body: envelope = %+v", got)
+	}
+}
+
+func TestReportError(t *testing.T) {
+	info := &models.ErrorInfo{Code: "POLICY_DENIED", Message: "denied", Description: "no policy", Link: "https://docs.example.test/denied"}
+	classified := classifyError(elevationFailedError(info), true)
+
+	t.Run("text keeps the message and hint", func(t *testing.T) {
+		defer restoreCommandGlobals(outputFormat, verbose)
+		outputFormat = "text"
+
+		var buf strings.Builder
+		reportError(&buf, classified, true)
+		want := "elevation failed: POLICY_DENIED - denied\nno policy\nHint: re-run with --verbose for more details\n"
+		if buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("structured formats get the JSON envelope", func(t *testing.T) {
+		defer restoreCommandGlobals(outputFormat, verbose)
+
+		for _, format := range []string{"json", "yaml", "csv"} {
+			outputFormat = format
+
+			var buf strings.Builder
+			reportError(&buf, classified, true)
+
+			var got errorOutput
+			if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+				t.Fatalf("%s: not a JSON envelope: %v\n%s", format, err, buf.String())
+			}
+			want := errorDetail{
+				Code:      "ELEVATION_FAILED",
+				Message:   "elevation failed: POLICY_DENIED - denied\nno policy",
+				ExitCode:  exitFailure,
+				ErrorInfo: &errorInfoOutput{Code: "POLICY_DENIED", Message: "denied", Description: "no policy", Link: "https://docs.example.test/denied"},
+			}
+			if got.Error.Code != want.Code || got.Error.Message != want.Message || got.Error.ExitCode != want.ExitCode || *got.Error.ErrorInfo != *want.ErrorInfo {
+				t.Errorf("%s: envelope = %+v, want %+v", format, got.Error, want)
+			}
+		}
+	})
+}
+
+// TestElevate_ErrorCodes checks that the elevation path tags its failures, so
+// a wrapper can tell a missing target from a refused elevation.
+func TestElevate_ErrorCodes(t *testing.T) {
+	target := models.EligibleTarget{
+		OrganizationID: "org", WorkspaceID: "sub-1", WorkspaceName: "Prod", WorkspaceType: models.WorkspaceTypeSubscription,
+		RoleInfo: models.RoleInfo{ID: "role-1", Name: "Contributor"},
+	}
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+
+	tests := []struct {
+		name     string
+		auth     *mockAuthLoader
+		elevate  *mockElevateService
+		args     []string
+		wantCode errorCode
+	}{
+		{
+			name:     "not authenticated",
+			auth:     &mockAuthLoader{loadErr: errors.New("no token")},
+			args:     []string{"--target", "Prod", "--role", "Contributor"},
+			wantCode: codeNotAuthenticated,
+		},
+		{
+			name:     "unknown target",
+			auth:     auth,
+			args:     []string{"--target", "Nope", "--role", "Contributor"},
+			wantCode: codeTargetNotFound,
+		},
+		{
+			name:     "target without role",
+			auth:     auth,
+			args:     []string{"--target", "Prod"},
+			wantCode: codeUsage,
+		},
+		{
+			name: "refused elevation",
+			auth: auth,
+			elevate: &mockElevateService{response: &models.ElevateResponse{Response: models.ElevateAccessResult{
+				Results: []models.ElevateTargetResult{{ErrorInfo: &models.ErrorInfo{Code: "DENIED", Message: "no"}}},
+			}}},
+			args:     []string{"--target", "Prod", "--role", "Contributor"},
+			wantCode: codeElevationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elevate := tt.elevate
+			if elevate == nil {
+				elevate = &mockElevateService{}
+			}
+			cmd := NewRootCommandWithDeps(nil, tt.auth,
+				&mockEligibilityLister{response: &models.EligibilityResponse{Response: []models.EligibleTarget{target}}},
+				elevate, &mockUnifiedSelector{}, &mockGroupsEligibilityLister{}, &mockGroupsElevator{}, config.DefaultConfig())
+
+			_, err := executeCommand(cmd, append([]string{"--provider", "azure"}, tt.args...)...)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := classifyError(err, true).code; got != tt.wantCode {
+				t.Errorf("code = %s, want %s (error: %v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
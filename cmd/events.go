@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/aaearon/grant-cli/internal/sdkclient"
+)
+
+// Event types written by --output ndjson. Each is one line on stdout, emitted
+// as it happens; the command's document follows as a final result event, or
+// its failure as an error event.
+const (
+	eventEligibilityFetched  = "eligibility_fetched"
+	eventPageFetched         = "page_fetched"
+	eventCacheHit            = "cache_hit"
+	eventCacheMiss           = "cache_miss"
+	eventCacheStale          = "cache_stale"
+	eventElevationRequested  = "elevation_requested"
+	eventElevationSucceeded  = "elevation_succeeded"
+	eventElevationFailed     = "elevation_failed"
+	eventRevokeBatch         = "revoke_batch"
+	eventRevokePoll          = "revoke_poll"
+	eventRequestStateChanged = "request_state_changed"
+	eventResult              = "result"
+	eventError               = "error"
+)
+
+// eventNow is the event clock. Package-level var for test injection.
+var eventNow = time.Now
+
+// events is the ndjson event stream. Its writer is nil unless --output ndjson
+// is in effect, which makes emitEvent a no-op for every other format.
+var events struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// startEvents directs emitted events to w; nil stops the stream.
+func startEvents(w io.Writer) {
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	events.w = w
+}
+
+// eventsEnabled reports whether an event stream is open.
+func eventsEnabled() bool {
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	return events.w != nil
+}
+
+// emitEvent writes one event line. It is safe for concurrent use: eligibility
+// is fetched for every CSP at once. A write failure is logged and otherwise
+// ignored, so a closed pipe never fails the operation being reported on.
+func emitEvent(eventType string, data any) {
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	if events.w == nil {
+		return
+	}
+	line, err := json.Marshal(eventOutput{Time: eventNow().UTC(), Type: eventType, Data: data})
+	if err == nil {
+		_, err = events.w.Write(append(line, '\n'))
+	}
+	if err != nil {
+		log.Info("failed to write %s event: %v", eventType, err)
+	}
+}
+
+// writeNDJSON ends the stream with the command's document as a result event.
+func writeNDJSON(w io.Writer, data any) error {
+	if !eventsEnabled() {
+		startEvents(w)
+		defer startEvents(nil)
+	}
+	emitEvent(eventResult, data)
+	return nil
+}
+
+// emitCacheLookup reports an eligibility or role cache lookup.
+func emitCacheLookup(key string, hit bool) {
+	eventType := eventCacheMiss
+	if hit {
+		eventType = eventCacheHit
+	}
+	emitEvent(eventType, cacheEventData{Key: key})
+}
+
+// emitPageFetched reports one page of a paginated list call. It is the page
+// hook of the SCA and workflows services.
+func emitPageFetched(p sdkclient.Page) {
+	emitEvent(eventPageFetched, pageEventData{Operation: p.Operation, Route: p.Route, Page: p.Number, Count: p.Items})
+}
+
+// listEligibilityReported lists csp's eligible targets and reports the fetch.
+func listEligibilityReported(ctx context.Context, lister eligibilityLister, csp models.CSP) (*models.EligibilityResponse, error) {
+	resp, err := lister.ListEligibility(ctx, csp)
+	ev := eligibilityEventData{Kind: "cloud", Provider: strings.ToLower(string(csp))}
+	if err != nil {
+		ev.Error = err.Error()
+	} else {
+		ev.Count = len(resp.Response)
+	}
+	emitEvent(eventEligibilityFetched, ev)
+	return resp, err
+}
+
+// cloudElevationEvent describes a cloud elevation before it is requested.
+func cloudElevationEvent(target *models.EligibleTarget) elevationEventData {
+	return elevationEventData{
+		Type:     "cloud",
+		Provider: strings.ToLower(string(target.CSP)),
+		Target:   target.WorkspaceName,
+		Role:     target.RoleInfo.Name,
+	}
+}
+
+// groupElevationEvent describes a group elevation before it is requested.
+func groupElevationEvent(group *models.GroupsEligibleTarget) elevationEventData {
+	return elevationEventData{
+		Type:      "group",
+		Provider:  "azure",
+		GroupName: group.GroupName,
+		GroupID:   group.GroupID,
+	}
+}
+
+// emitElevationOutcome reports how the elevation announced with ev ended.
+func emitElevationOutcome(ev elevationEventData, err error) {
+	if err == nil {
+		emitEvent(eventElevationSucceeded, ev)
+		return
+	}
+	ev.Error = err.Error()
+	var ce *cliError
+	if errors.As(err, &ce) && ce.info != nil {
+		ev.ErrorInfo = toErrorInfoOutput(ce.info)
+	}
+	emitEvent(eventElevationFailed, ev)
+}
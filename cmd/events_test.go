@@ -0,0 +1,251 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/aaearon/grant-cli/internal/sdkclient"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+)
+
+// decodedEvent is an eventOutput with its data left raw for the test to decode.
+type decodedEvent struct {
+	Time time.Time       `json:"time"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// parseEvents decodes an ndjson stream, failing on any line that is not JSON.
+func parseEvents(t *testing.T, stream string) []decodedEvent {
+	t.Helper()
+	var events []decodedEvent
+	for _, line := range strings.Split(strings.TrimSuffix(stream, "\n"), "\n") {
+		var ev decodedEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line is not a JSON event: %v\n%q", err, line)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func eventTypes(events []decodedEvent) []string {
+	types := make([]string, len(events))
+	for i, ev := range events {
+		types[i] = ev.Type
+	}
+	return types
+}
+
+// withEventStream opens the event stream on a buffer for the test.
+func withEventStream(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	startEvents(&buf)
+	t.Cleanup(func() { startEvents(nil) })
+	return &buf
+}
+
+func TestEmitEvent(t *testing.T) {
+	origNow := eventNow
+	t.Cleanup(func() { eventNow = origNow })
+	eventNow = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600)) }
+
+	emitEvent(eventCacheHit, cacheEventData{Key: "ignored"}) // no stream: dropped
+
+	buf := withEventStream(t)
+	emitEvent(eventCacheHit, cacheEventData{Key: "eligibility_aws"})
+
+	want := `{"time":"2026-03-01T11:00:00Z","type":"cache_hit","data":{"key":"eligibility_aws"}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestElevate_NDJSONStream(t *testing.T) {
+	target := models.EligibleTarget{
+		OrganizationID: "org", WorkspaceID: "sub-1", WorkspaceName: "Prod", WorkspaceType: models.WorkspaceTypeSubscription,
+		RoleInfo: models.RoleInfo{ID: "role-1", Name: "Contributor"},
+	}
+	lister := &mockEligibilityLister{response: &models.EligibilityResponse{Response: []models.EligibleTarget{target}}}
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+
+	t.Run("success", func(t *testing.T) {
+		elevate := &mockElevateService{response: &models.ElevateResponse{Response: models.ElevateAccessResult{
+			Results: []models.ElevateTargetResult{{WorkspaceID: "sub-1", RoleID: "role-1", SessionID: "sess-1"}},
+		}}}
+		cmd := NewRootCommandWithDeps(nil, auth, lister, elevate, &mockUnifiedSelector{}, &mockGroupsEligibilityLister{}, &mockGroupsElevator{}, config.DefaultConfig())
+
+		stdout, _, err := executeCommandStreams(cmd, "--provider", "azure", "--target", "Prod", "--role", "Contributor", "--output", "ndjson")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		events := parseEvents(t, stdout)
+		want := []string{eventEligibilityFetched, eventElevationRequested, eventElevationSucceeded, eventResult}
+		if got := eventTypes(events); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("event types = %v, want %v\n%s", got, want, stdout)
+		}
+
+		var fetched eligibilityEventData
+		if err := json.Unmarshal(events[0].Data, &fetched); err != nil || fetched != (eligibilityEventData{Kind: "cloud", Provider: "azure", Count: 1}) {
+			t.Errorf("eligibility event = %+v (%v)", fetched, err)
+		}
+		var succeeded elevationEventData
+		if err := json.Unmarshal(events[2].Data, &succeeded); err != nil || succeeded.SessionID != "sess-1" || succeeded.Target != "Prod" {
+			t.Errorf("elevation_succeeded event = %+v (%v)", succeeded, err)
+		}
+		var result cloudElevationOutput
+		if err := json.Unmarshal(events[3].Data, &result); err != nil || result.SessionID != "sess-1" {
+			t.Errorf("result event = %+v (%v)", result, err)
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		elevate := &mockElevateService{response: &models.ElevateResponse{Response: models.ElevateAccessResult{
+			Results: []models.ElevateTargetResult{{ErrorInfo: &models.ErrorInfo{Code: "DENIED", Message: "no"}}},
+		}}}
+		cmd := NewRootCommandWithDeps(nil, auth, lister, elevate, &mockUnifiedSelector{}, &mockGroupsEligibilityLister{}, &mockGroupsElevator{}, config.DefaultConfig())
+
+		stdout, _, err := executeCommandStreams(cmd, "--provider", "azure", "--target", "Prod", "--role", "Contributor", "--output", "ndjson")
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		events := parseEvents(t, stdout)
+		last := events[len(events)-1]
+		var failed elevationEventData
+		if err := json.Unmarshal(last.Data, &failed); err != nil || last.Type != eventElevationFailed || failed.ErrorInfo == nil || failed.ErrorInfo.Code != "DENIED" {
+			t.Errorf("last event = %s %s, want elevation_failed carrying the ErrorInfo", last.Type, last.Data)
+		}
+	})
+}
+
+func TestReportError_NDJSON(t *testing.T) {
+	defer restoreCommandGlobals(outputFormat, verbose)
+	outputFormat = formatNDJSON
+
+	var buf bytes.Buffer
+	reportError(&buf, classifyError(notAuthenticatedError(errors.New("no token")), true), true)
+
+	events := parseEvents(t, buf.String())
+	var detail errorDetail
+	if err := json.Unmarshal(events[0].Data, &detail); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != eventError || detail.Code != string(codeNotAuthenticated) {
+		t.Errorf("got %s, want a single NOT_AUTHENTICATED error event", buf.String())
+	}
+}
+
+func TestRevokeInBatches_Events(t *testing.T) {
+	buf := withEventStream(t)
+
+	ids := make([]string, models.MaxRevokeBatchSize+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("s%d", i)
+	}
+	revoker := &mockSessionRevoker{revokeFunc: func(_ context.Context, req *models.RevokeRequest) (*models.RevokeResponse, error) {
+		if len(req.SessionIDs) == 1 {
+			return nil, errors.New("boom")
+		}
+		resp := &models.RevokeResponse{}
+		for _, id := range req.SessionIDs {
+			resp.Response = append(resp.Response, models.RevocationResult{SessionID: id, RevocationStatus: models.RevocationSuccessful})
+		}
+		return resp, nil
+	}}
+
+	if _, err := revokeInBatches(context.Background(), revoker, ids); err == nil {
+		t.Fatal("expected the second batch to fail")
+	}
+
+	events := parseEvents(t, buf.String())
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want one per batch:\n%s", len(events), buf.String())
+	}
+	var first, second revokeBatchEventData
+	_ = json.Unmarshal(events[0].Data, &first)
+	_ = json.Unmarshal(events[1].Data, &second)
+	if first.Batch != 1 || first.Batches != 2 || len(first.Results) != models.MaxRevokeBatchSize || first.Error != "" {
+		t.Errorf("first batch event = %+v", first)
+	}
+	if second.Batch != 2 || second.Error != "boom" || len(second.SessionIDs) != 1 {
+		t.Errorf("second batch event = %+v", second)
+	}
+}
+
+func TestEmitPageFetched(t *testing.T) {
+	buf := withEventStream(t)
+
+	emitPageFetched(sdkclient.Page{Operation: "sessions", Route: "/api/access/sessions", Number: 2, Items: 50})
+
+	events := parseEvents(t, buf.String())
+	var got pageEventData
+	_ = json.Unmarshal(events[0].Data, &got)
+	if events[0].Type != eventPageFetched || got != (pageEventData{Operation: "sessions", Route: "/api/access/sessions", Page: 2, Count: 50}) {
+		t.Errorf("event = %s %+v", events[0].Type, got)
+	}
+}
+
+// TestRevoke_NothingToRevokeIsStructured checks that a revoke with no sessions
+// to send keeps a structured stdout parseable instead of printing a sentence.
+func TestRevoke_NothingToRevokeIsStructured(t *testing.T) {
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+	noSessions := &mockSessionLister{sessions: &models.SessionsResponse{}}
+	someSessions := &mockSessionLister{sessions: &models.SessionsResponse{Response: []models.SessionInfo{
+		{SessionID: "s1", CSP: models.CSPAWS, WorkspaceID: "111"},
+	}}}
+
+	tests := []struct {
+		name   string
+		lister *mockSessionLister
+		args   []string
+		want   string
+	}{
+		{"no sessions, json", noSessions, []string{"--all", "--yes", "--output", "json"}, "[]\n"},
+		{"no match, json", someSessions, []string{"--all", "--yes", "--target", "Nope", "--output", "json"}, "[]\n"},
+		{"dry run, no sessions, json", noSessions, []string{"--dry-run", "--output", "json"}, "[]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewRevokeCommandWithDeps(auth, tt.lister, &mockEligibilityLister{}, &mockSessionRevoker{}, &mockSessionSelector{}, &mockConfirmPrompter{})
+			root := newTestRootCommand()
+			root.AddCommand(cmd)
+
+			stdout, _, err := executeCommandStreams(root, append([]string{"revoke"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stdout != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+
+	t.Run("ndjson ends with an empty result event", func(t *testing.T) {
+		cmd := NewRevokeCommandWithDeps(auth, noSessions, &mockEligibilityLister{}, &mockSessionRevoker{}, &mockSessionSelector{}, &mockConfirmPrompter{})
+		root := newTestRootCommand()
+		root.AddCommand(cmd)
+
+		stdout, _, err := executeCommandStreams(root, "revoke", "--all", "--yes", "--output", "ndjson")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events := parseEvents(t, stdout)
+		last := events[len(events)-1]
+		if last.Type != eventResult || string(last.Data) != "[]" {
+			t.Errorf("last event = %s %s, want an empty result", last.Type, last.Data)
+		}
+	})
+}
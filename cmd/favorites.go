@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"strings"
 
 	survey "github.com/Iilun/survey/v2"
+	"github.com/aaearon/grant-cli/internal/cache"
 	"github.com/aaearon/grant-cli/internal/config"
 	"github.com/aaearon/grant-cli/internal/ui"
 	"github.com/spf13/cobra"
@@ -17,7 +19,7 @@ func NewFavoritesCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "favorites",
 		Short: "Manage saved elevation favorites",
-		Long: `Add, list, and remove saved elevation target favorites for quick access.
+		Long: `Add, list, edit, rename, remove, check, import and export saved elevation target favorites for quick access.
 
 Favorites let you save frequently-used elevation targets so you can
 elevate with a single command: grant --favorite <name>
@@ -30,7 +32,12 @@ Workflow:
 
 	cmd.AddCommand(newFavoritesAddCommand())
 	cmd.AddCommand(newFavoritesListCommand())
+	cmd.AddCommand(newFavoritesEditCommand(runFavoritesEditProduction))
+	cmd.AddCommand(newFavoritesRenameCommand())
 	cmd.AddCommand(newFavoritesRemoveCommand())
+	cmd.AddCommand(newFavoritesCheckCommand(runFavoritesCheckProduction))
+	cmd.AddCommand(newFavoritesExportCommand())
+	cmd.AddCommand(newFavoritesImportCommand(runFavoritesImportProduction))
 
 	return cmd
 }
@@ -40,7 +47,7 @@ func NewFavoritesCommandWithAllDeps(eligLister eligibilityLister, sel unifiedSel
 	cmd := &cobra.Command{
 		Use:   "favorites",
 		Short: "Manage saved elevation favorites",
-		Long: `Add, list, and remove saved elevation target favorites for quick access.
+		Long: `Add, list, edit, rename, remove, check, import and export saved elevation target favorites for quick access.
 
 Favorites let you save frequently-used elevation targets so you can
 elevate with a single command: grant --favorite <name>
@@ -55,7 +62,18 @@ Workflow:
 		return runFavoritesAddWithDeps(c, args, eligLister, sel, prompter, nil, groupsElig)
 	}))
 	cmd.AddCommand(newFavoritesListCommand())
+	cmd.AddCommand(newFavoritesEditCommand(func(c *cobra.Command, args []string) error {
+		return runFavoritesEdit(c, args, eligLister, sel, groupsElig)
+	}))
+	cmd.AddCommand(newFavoritesRenameCommand())
 	cmd.AddCommand(newFavoritesRemoveCommand())
+	cmd.AddCommand(newFavoritesCheckCommand(func(c *cobra.Command, args []string) error {
+		return runFavoritesCheck(c, args, eligLister, groupsElig)
+	}))
+	cmd.AddCommand(newFavoritesExportCommand())
+	cmd.AddCommand(newFavoritesImportCommand(func(c *cobra.Command, args []string) error {
+		return runFavoritesImport(c, args, eligLister, groupsElig)
+	}))
 
 	return cmd
 }
@@ -66,7 +84,7 @@ func newFavoritesAddCommandWithRunner(runFn func(*cobra.Command, []string) error
 	cmd := &cobra.Command{
 		Use:   "add [name]",
 		Short: "Add a new favorite",
-		Long:  "Add a new elevation target as a favorite, either by selecting from eligible targets or via --target and --role flags.",
+		Long:  "Add a new elevation target as a favorite, either by selecting from eligible targets or via --target and --role flags, which must name a target and role you are eligible for.",
 		Example: `  # Interactive: select target, then name the favorite
   grant favorites add
 
@@ -74,7 +92,10 @@ func newFavoritesAddCommandWithRunner(runFn func(*cobra.Command, []string) error
   grant favorites add prod-admin
 
   # Non-interactive: specify target and role directly
-  grant favorites add prod-admin --target "Prod-EastUS" --role "Contributor"`,
+  grant favorites add prod-admin --target "Prod-EastUS" --role "Contributor"
+
+  # With a description and tags
+  grant favorites add prod-admin --target "Prod-EastUS" --role "Contributor" --description "Production admin" --tags prod,azure`,
 		Args: cobra.RangeArgs(0, 1),
 		RunE: runFn,
 	}
@@ -84,6 +105,7 @@ func newFavoritesAddCommandWithRunner(runFn func(*cobra.Command, []string) error
 	cmd.Flags().StringP("role", "r", "", "Role name")
 	cmd.Flags().String("type", "", "Favorite type: cloud, groups (default: cloud)")
 	cmd.Flags().StringP("group", "g", "", "Group name (for --type groups)")
+	addFavoriteAnnotationFlags(cmd)
 
 	return cmd
 }
@@ -110,7 +132,8 @@ func (s *surveyNamePrompter) PromptName() (string, error) {
 }
 
 // runFavoritesAddProduction is the production RunE for favorites add.
-// It handles auth bootstrapping for interactive mode and delegates to runFavoritesAddWithDeps.
+// It handles auth bootstrapping for the interactive and --target/--role modes
+// and delegates to runFavoritesAddWithDeps.
 func runFavoritesAddProduction(cmd *cobra.Command, args []string) error {
 	target, _ := cmd.Flags().GetString("target")
 	role, _ := cmd.Flags().GetString("role")
@@ -123,17 +146,16 @@ func runFavoritesAddProduction(cmd *cobra.Command, args []string) error {
 	favType, _ := cmd.Flags().GetString("type")
 	group, _ := cmd.Flags().GetString("group")
 
-	if favType == config.FavoriteTypeGroups {
-		if group != "" {
-			// Non-interactive groups mode: no auth needed
-			return runFavoritesAddWithDeps(cmd, args, nil, nil, nil, nil, nil)
-		}
-	} else if target != "" && role != "" {
-		// Non-interactive cloud mode: no auth needed
+	if favType == config.FavoriteTypeGroups && group != "" {
+		// Non-interactive groups mode: no auth needed
 		return runFavoritesAddWithDeps(cmd, args, nil, nil, nil, nil, nil)
 	}
+	flagMode := favType != config.FavoriteTypeGroups && target != "" && role != ""
+	if flagMode && len(args) == 0 {
+		return errFavoriteNameRequired(favType)
+	}
 
-	// Interactive path: load config early for fast-fail duplicate check
+	// Load config early for fast-fail duplicate check, before authenticating
 	cfg, _, err := config.LoadDefaultWithPath()
 	if err != nil {
 		return err
@@ -145,6 +167,16 @@ func runFavoritesAddProduction(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if flagMode {
+		// Non-interactive cloud mode: the target is resolved against
+		// eligibility, so it authenticates too, but never prompts.
+		cachedLister, err := newFavoritesLister(cfg)
+		if err != nil {
+			return err
+		}
+		return runFavoritesAddWithDeps(cmd, args, cachedLister, nil, nil, cfg, nil)
+	}
+
 	// Fail fast without a terminal. This must stay AFTER the duplicate-name
 	// check above (moving it earlier makes a duplicate report "not
 	// interactive" instead of "already exists") and BEFORE the bootstrap
@@ -158,18 +190,31 @@ func runFavoritesAddProduction(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%w; pass a favorite name plus --target and --role, or a name plus --type groups with --group", ui.ErrNotInteractive)
 	}
 
-	// Bootstrap auth and SCA service
-	_, scaService, _, err := bootstrapSCAService()
+	cachedLister, err := newFavoritesLister(cfg)
 	if err != nil {
 		return err
 	}
 
-	cachedLister, err := buildCachedLister(cfg, false, scaService, scaService)
+	return runFavoritesAddWithDeps(cmd, args, cachedLister, &uiUnifiedSelector{}, &surveyNamePrompter{}, cfg, cachedLister)
+}
+
+// newFavoritesLister bootstraps auth and the SCA service and returns the
+// cached lister of cloud and group eligibility.
+func newFavoritesLister(cfg *config.Config) (*cache.CachedEligibilityLister, error) {
+	_, scaService, _, err := bootstrapSCAService()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return buildCachedLister(cfg, false, scaService, scaService)
+}
 
-	return runFavoritesAddWithDeps(cmd, args, cachedLister, &uiUnifiedSelector{}, &surveyNamePrompter{}, cfg, cachedLister)
+// errFavoriteNameRequired is the error of a non-interactive add without a
+// favorite name.
+func errFavoriteNameRequired(favType string) error {
+	if favType == config.FavoriteTypeGroups {
+		return errors.New("name is required when using --group flag\n\nUsage:\n  grant favorites add <name> --type groups --group <group>")
+	}
+	return errors.New("name is required when using --target and --role flags\n\nUsage:\n  grant favorites add <name> --target <target> --role <role>")
 }
 
 // favoritesAddFlags holds the parsed flags for the favorites add command.
@@ -205,6 +250,9 @@ func parseFavoritesAddFlags(cmd *cobra.Command) (*favoritesAddFlags, error) {
 		if (f.target != "" && f.role == "") || (f.target == "" && f.role != "") {
 			return nil, errors.New("both --target and --role must be provided")
 		}
+		if f.target != "" && f.provider == providerAll {
+			return nil, errors.New("--provider all cannot be saved in a favorite; name the target's provider")
+		}
 	}
 
 	return f, nil
@@ -235,7 +283,7 @@ func selectFavoriteInteractive(provider string, eligLister eligibilityLister, gr
 	}
 
 	if len(items) == 0 {
-		return config.Favorite{}, "", errors.New("no eligible targets or groups found")
+		return config.Favorite{}, "", withCode(codeNotEligible, errors.New("no eligible targets or groups found"))
 	}
 
 	selected, err := sel.SelectItem(items)
@@ -254,11 +302,15 @@ func selectFavoriteInteractive(provider string, eligLister eligibilityLister, gr
 		}
 		fav.Target = selected.cloud.WorkspaceName
 		fav.Role = selected.cloud.RoleInfo.Name
+		fav.WorkspaceID = selected.cloud.WorkspaceID
+		fav.RoleID = selected.cloud.RoleInfo.ID
+		fav.OrganizationID = selected.cloud.OrganizationID
 	case selectionGroup:
 		fav.Type = config.FavoriteTypeGroups
 		fav.Provider = "azure"
 		fav.Group = selected.group.GroupName
 		fav.DirectoryID = selected.group.DirectoryID
+		fav.GroupID = selected.group.GroupID
 	}
 
 	if name == "" {
@@ -274,9 +326,10 @@ func selectFavoriteInteractive(provider string, eligLister eligibilityLister, gr
 	return fav, name, nil
 }
 
-// runFavoritesAddWithDeps contains the core logic for favorites add.
-// When eligLister and sel are nil, it uses the non-interactive flag path.
-// If preloadedCfg is non-nil, it is used instead of loading from disk.
+// runFavoritesAddWithDeps contains the core logic for favorites add. With
+// --target and --role it resolves them through eligLister without prompting,
+// so sel and prompter may be nil; a group added with --group needs neither
+// lister. If preloadedCfg is non-nil, it is used instead of loading from disk.
 func runFavoritesAddWithDeps(cmd *cobra.Command, args []string, eligLister eligibilityLister, sel unifiedSelector, prompter namePrompter, preloadedCfg *config.Config, groupsElig groupsEligibilityLister) error {
 	f, err := parseFavoritesAddFlags(cmd)
 	if err != nil {
@@ -295,20 +348,13 @@ func runFavoritesAddWithDeps(cmd *cobra.Command, args []string, eligLister eligi
 		log.Info("Non-interactive mode: target=%q role=%q provider=%q group=%q", f.target, f.role, f.provider, f.group)
 	}
 	if isNonInteractive && name == "" {
-		if f.favType == config.FavoriteTypeGroups {
-			return errors.New("name is required when using --group flag\n\nUsage:\n  grant favorites add <name> --type groups --group <group>")
-		}
-		return errors.New("name is required when using --target and --role flags\n\nUsage:\n  grant favorites add <name> --target <target> --role <role>")
+		return errFavoriteNameRequired(f.favType)
 	}
 
 	// Load config (skip if pre-loaded)
-	var cfg *config.Config
-	var cfgPath string
-	if preloadedCfg != nil {
-		cfg = preloadedCfg
-		cfgPath, _ = config.ConfigPath()
-	} else {
-		cfg, cfgPath, err = config.LoadDefaultWithPath()
+	cfg := preloadedCfg
+	if cfg == nil {
+		cfg, _, err = config.LoadDefaultWithPath()
 		if err != nil {
 			return err
 		}
@@ -323,7 +369,7 @@ func runFavoritesAddWithDeps(cmd *cobra.Command, args []string, eligLister eligi
 
 	// Groups flow
 	if f.favType == config.FavoriteTypeGroups {
-		return addGroupFavorite(cmd, name, f.group, cfg, cfgPath, groupsElig, eligLister, sel, prompter)
+		return addGroupFavorite(cmd, name, f.group, cfg, groupsElig, eligLister, sel, prompter)
 	}
 
 	// Cloud flow
@@ -331,9 +377,9 @@ func runFavoritesAddWithDeps(cmd *cobra.Command, args []string, eligLister eligi
 	if f.target != "" && f.role != "" {
 		fav.Target = f.target
 		fav.Role = f.role
-		fav.Provider = f.provider
-		if fav.Provider == "" {
-			fav.Provider = cfg.DefaultProvider
+		fav.Provider = cmp.Or(f.provider, cfg.DefaultProvider, config.DefaultFavoriteProvider)
+		if err := pinFavoriteTarget(&fav, eligLister); err != nil {
+			return err
 		}
 	} else {
 		fav, name, err = selectFavoriteInteractive(f.provider, eligLister, groupsElig, sel, prompter, name, cfg)
@@ -343,11 +389,12 @@ func runFavoritesAddWithDeps(cmd *cobra.Command, args []string, eligLister eligi
 	}
 
 	log.Info("Saving favorite %q...", name)
-	if err := config.AddFavorite(cfg, name, fav); err != nil {
-		return fmt.Errorf("failed to add favorite: %w", err)
+	fav.Profile = profileOf(cfg)
+	if err := applyFavoriteAnnotations(cmd, &fav); err != nil {
+		return err
 	}
-	if err := config.Save(cfg, cfgPath); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if err := addUserFavorite(name, fav); err != nil {
+		return err
 	}
 
 	if fav.ResolvedType() == config.FavoriteTypeGroups {
@@ -358,8 +405,44 @@ func runFavoritesAddWithDeps(cmd *cobra.Command, args []string, eligLister eligi
 	return nil
 }
 
+// pinFavoriteTarget resolves the target and role of a favorite given by flags
+// against the eligible targets of its provider, as selecting it would, and
+// saves their IDs and current names in fav. A target or role the user is not
+// eligible for, or a name several targets share, is an error instead of a
+// favorite that fails at elevation.
+func pinFavoriteTarget(fav *config.Favorite, eligLister eligibilityLister) error {
+	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+	defer cancel()
+
+	targets, err := fetchEligibility(ctx, eligLister, fav.Provider)
+	if err != nil {
+		return err
+	}
+	matches, _ := matchFavoriteTargets(*fav, targets)
+	switch len(matches) {
+	case 0:
+		return withCode(codeNotEligible, fmt.Errorf("not eligible for role %q on %s target %q, run 'grant list --provider %s' to see available options",
+			fav.Role, fav.Provider, fav.Target, fav.Provider))
+	case 1:
+	default:
+		candidates := make([]string, len(matches))
+		for i, t := range matches {
+			candidates[i] = describeTargetCandidate(t)
+		}
+		return withCode(codeTargetNotFound, fmt.Errorf("%d eligible targets match target %q and role %q: %s; run 'grant favorites add' without --target and --role to pick one",
+			len(matches), fav.Target, fav.Role, strings.Join(candidates, ", ")))
+	}
+
+	target := matches[0]
+	fav.Target, fav.Role = target.WorkspaceName, target.RoleInfo.Name
+	fav.WorkspaceID = target.WorkspaceID
+	fav.RoleID = target.RoleInfo.ID
+	fav.OrganizationID = target.OrganizationID
+	return nil
+}
+
 // addGroupFavorite handles the --type groups flow for favorites add.
-func addGroupFavorite(cmd *cobra.Command, name, group string, cfg *config.Config, cfgPath string, groupsElig groupsEligibilityLister, eligLister eligibilityLister, sel unifiedSelector, prompter namePrompter) error {
+func addGroupFavorite(cmd *cobra.Command, name, group string, cfg *config.Config, groupsElig groupsEligibilityLister, eligLister eligibilityLister, sel unifiedSelector, prompter namePrompter) error {
 	var fav config.Favorite
 	fav.Type = config.FavoriteTypeGroups
 	fav.Provider = "azure"
@@ -389,6 +472,7 @@ func addGroupFavorite(cmd *cobra.Command, name, group string, cfg *config.Config
 
 		fav.Group = selected.group.GroupName
 		fav.DirectoryID = selected.group.DirectoryID
+		fav.GroupID = selected.group.GroupID
 
 		if name == "" {
 			name, err = prompter.PromptName()
@@ -401,25 +485,44 @@ func addGroupFavorite(cmd *cobra.Command, name, group string, cfg *config.Config
 		}
 	}
 
+	fav.Profile = profileOf(cfg)
+	if err := applyFavoriteAnnotations(cmd, &fav); err != nil {
+		return err
+	}
+	if err := addUserFavorite(name, fav); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Added favorite %q: groups/%s\n", name, fav.Group)
+	return nil
+}
+
+// addUserFavorite saves fav in the user config, the only layer grant writes.
+func addUserFavorite(name string, fav config.Favorite) error {
+	cfg, cfgPath, err := config.LoadUserWithPath()
+	if err != nil {
+		return err
+	}
 	if err := config.AddFavorite(cfg, name, fav); err != nil {
 		return fmt.Errorf("failed to add favorite: %w", err)
 	}
 	if err := config.Save(cfg, cfgPath); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
-	fmt.Fprintf(cmd.OutOrStdout(), "Added favorite %q: groups/%s\n", name, fav.Group)
 	return nil
 }
 
 func newFavoritesListCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:     "list",
-		Short:   "List all favorites",
-		Long:    "Display all saved elevation target favorites.",
-		Example: "  grant favorites list",
-		Args:    cobra.NoArgs,
-		RunE:    runFavoritesList,
-	}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all favorites",
+		Long:  "Display all saved elevation target favorites, or with --tag only those with that tag.",
+		Example: `  grant favorites list
+  grant favorites list --tag prod`,
+		Args: cobra.NoArgs,
+		RunE: runFavoritesList,
+	}
+	cmd.Flags().String("tag", "", "Only list favorites with this tag")
+	return cmd
 }
 
 func newFavoritesRemoveCommand() *cobra.Command {
@@ -441,43 +544,87 @@ func newFavoritesRemoveCommand() *cobra.Command {
 	}
 }
 
+// checkFavoriteProfile refuses a favorite saved under a profile other than
+// the active one.
+func checkFavoriteProfile(cfg *config.Config, name string, fav config.Favorite) error {
+	if profile := profileOf(cfg); !fav.AvailableIn(profile) {
+		return fmt.Errorf("favorite %q belongs to profile %q, not %q; run it with --profile %s", name, fav.Profile, profile, fav.Profile)
+	}
+	return nil
+}
+
 func runFavoritesList(cmd *cobra.Command, args []string) error {
 	log.Info("Loading config...")
-	cfg, _, err := config.LoadDefaultWithPath()
+	layers, err := config.LoadLayers()
 	if err != nil {
 		return err
 	}
+	cfg := layers.Config
 
-	// List favorites
-	favorites := config.ListFavorites(cfg)
+	// List the favorites of the active profile
+	profile := profileOf(cfg)
+	tag, _ := cmd.Flags().GetString("tag")
+	var favorites []config.FavoriteEntry
+	for _, entry := range config.ListFavorites(cfg) {
+		if entry.AvailableIn(profile) && (tag == "" || entry.HasTag(tag)) {
+			favorites = append(favorites, entry)
+		}
+	}
 	log.Info("Found %d favorite(s)", len(favorites))
+	if len(favorites) == 0 && tag != "" && !isStructuredOutput() {
+		fmt.Fprintf(cmd.OutOrStdout(), "No favorites tagged %q.\n", tag)
+		return nil
+	}
 	if len(favorites) == 0 {
 		fmt.Fprintln(cmd.OutOrStdout(), "No favorites saved. Run 'grant favorites add' to create one.")
 		return nil
 	}
 
-	if isJSONOutput() {
+	// Favorites from a layer other than the user config are read-only.
+	sourceOf := func(name string) (string, bool) {
+		src, _ := layers.FavoriteSource(name)
+		return src.Name, src.Name != config.LayerUser
+	}
+
+	if isStructuredOutput() {
 		out := make([]favoriteOutput, len(favorites))
 		for i, entry := range favorites {
+			source, readOnly := sourceOf(entry.Name)
 			out[i] = favoriteOutput{
-				Name:        entry.Name,
-				Type:        entry.ResolvedType(),
-				Provider:    entry.Provider,
-				Target:      entry.Target,
-				Role:        entry.Role,
-				Group:       entry.Group,
-				DirectoryID: entry.DirectoryID,
+				Name:           entry.Name,
+				Type:           entry.ResolvedType(),
+				Provider:       entry.Provider,
+				Target:         entry.Target,
+				Role:           entry.Role,
+				Group:          entry.Group,
+				DirectoryID:    entry.DirectoryID,
+				GroupID:        entry.GroupID,
+				WorkspaceID:    entry.WorkspaceID,
+				RoleID:         entry.RoleID,
+				OrganizationID: entry.OrganizationID,
+				Profile:        entry.Profile,
+				Description:    entry.Description,
+				Tags:           entry.Tags,
+				DefaultReason:  entry.DefaultReason,
+				Source:         source,
+				ReadOnly:       readOnly,
 			}
 		}
-		return writeJSON(cmd.OutOrStdout(), out)
+		return writeOutput(cmd.OutOrStdout(), out)
 	}
 
 	for _, entry := range favorites {
-		if entry.ResolvedType() == config.FavoriteTypeGroups {
-			fmt.Fprintf(cmd.OutOrStdout(), "%s: groups/%s\n", entry.Name, entry.Group)
-		} else {
-			fmt.Fprintf(cmd.OutOrStdout(), "%s: %s/%s/%s\n", entry.Name, entry.Provider, entry.Target, entry.Role)
+		line := entry.Name + ": " + entry.Describe()
+		if entry.Description != "" {
+			line += " - " + entry.Description
+		}
+		if len(entry.Tags) > 0 {
+			line += " #" + strings.Join(entry.Tags, " #")
 		}
+		if source, readOnly := sourceOf(entry.Name); readOnly {
+			line += fmt.Sprintf(" [%s, read-only]", source)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), line)
 	}
 
 	return nil
@@ -487,7 +634,14 @@ func runFavoritesRemove(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
 	log.Info("Loading config...")
-	cfg, cfgPath, err := config.LoadDefaultWithPath()
+	layers, err := config.LoadLayers()
+	if err != nil {
+		return err
+	}
+	if err := checkUserFavorite(layers, name); err != nil {
+		return err
+	}
+	cfg, cfgPath, err := config.LoadUserWithPath()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,369 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/spf13/cobra"
+)
+
+// Statuses of grant favorites check.
+const (
+	favoriteCheckOK        = "ok"
+	favoriteCheckAmbiguous = "ambiguous"
+	favoriteCheckMissing   = "missing"
+)
+
+// maxFavoriteCandidates caps the closest eligible targets or groups reported
+// for a missing favorite.
+const maxFavoriteCandidates = 3
+
+// favoriteCheck is the outcome of resolving one favorite against live
+// eligibility.
+type favoriteCheck struct {
+	entry  config.FavoriteEntry
+	status string
+	reason string // why the favorite is not ok
+
+	// target or group is the single match of an ok favorite.
+	target *models.EligibleTarget
+	group  *models.GroupsEligibleTarget
+
+	// candidates are the matches of an ambiguous favorite, or the closest
+	// eligible targets or groups of a missing one.
+	candidates []string
+}
+
+func newFavoritesCheckCommand(runFn func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [names...]",
+		Short: "Check favorites against live eligibility",
+		Long: `Resolve each favorite of the active profile, or the named ones, against fresh
+eligibility and report it as ok, ambiguous (it matches several targets or
+groups) or missing (it matches none, with the closest eligible candidates).
+
+--fix pins each ok favorite of the user config to what it matched: a cloud
+favorite gets the workspace_id, role_id and organization_id of its target,
+a group favorite the directory_id and group_id of its group, and both their
+current names, so renaming them later does not break it.
+
+Exits with code 4 if any favorite is ambiguous or missing.`,
+		Example: `  # Check every favorite
+  grant favorites check
+
+  # Check two, and pin them to the IDs they resolve to
+  grant favorites check prod-admin ops --fix`,
+		RunE: runFn,
+	}
+	cmd.Flags().Bool("fix", false, "Pin ok favorites of the user config to the IDs they resolve to")
+	return cmd
+}
+
+func runFavoritesCheckProduction(cmd *cobra.Command, args []string) error {
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err != nil {
+		return err
+	}
+	_, scaService, _, err := bootstrapSCAService()
+	if err != nil {
+		return err
+	}
+	// Check against fresh eligibility, not a cache that may predate a rename.
+	cachedLister, err := buildCachedLister(cfg, true, scaService, scaService)
+	if err != nil {
+		return err
+	}
+	return runFavoritesCheck(cmd, args, cachedLister, cachedLister)
+}
+
+func runFavoritesCheck(cmd *cobra.Command, args []string, eligLister eligibilityLister, groupsElig groupsEligibilityLister) error {
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	log.Info("Loading config...")
+	layers, err := config.LoadLayers()
+	if err != nil {
+		return err
+	}
+	cfg := layers.Config
+
+	var entries []config.FavoriteEntry
+	if len(args) == 0 {
+		entries = profileFavorites(cfg)
+	}
+	for _, name := range args {
+		fav, err := config.GetFavorite(cfg, name)
+		if err != nil {
+			return withCode(codeTargetNotFound, fmt.Errorf("favorite %q not found, run 'grant favorites list'", name))
+		}
+		entries = append(entries, config.FavoriteEntry{Name: name, Favorite: fav})
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No favorites saved. Run 'grant favorites add' to create one.")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+	defer cancel()
+	checks, err := checkFavorites(ctx, entries, eligLister, groupsElig)
+	if err != nil {
+		return err
+	}
+
+	pinned := make(map[string]bool)
+	if fix {
+		if pinned, err = pinFavorites(layers, checks); err != nil {
+			return err
+		}
+	}
+
+	var broken []string
+	for _, c := range checks {
+		if c.status != favoriteCheckOK {
+			broken = append(broken, c.entry.Name)
+		}
+	}
+
+	if isStructuredOutput() {
+		out := make([]favoriteCheckOutput, len(checks))
+		for i, c := range checks {
+			out[i] = favoriteCheckOutput{
+				Name:       c.entry.Name,
+				Type:       c.entry.ResolvedType(),
+				Favorite:   c.entry.Describe(),
+				Status:     c.status,
+				Reason:     c.reason,
+				Candidates: c.candidates,
+				Pinned:     pinned[c.entry.Name],
+			}
+		}
+		if err := writeOutput(cmd.OutOrStdout(), out); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range checks {
+			line := fmt.Sprintf("%-9s  %s: %s", c.status, c.entry.Name, c.entry.Describe())
+			switch c.status {
+			case favoriteCheckAmbiguous:
+				line += fmt.Sprintf(" - %s; matches: %s", c.reason, strings.Join(c.candidates, ", "))
+			case favoriteCheckMissing:
+				line += " - " + c.reason
+				if len(c.candidates) > 0 {
+					line += "; closest: " + strings.Join(c.candidates, ", ")
+				}
+			}
+			if pinned[c.entry.Name] {
+				line += " (pinned)"
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), line)
+		}
+	}
+
+	if len(broken) > 0 {
+		return withCode(codeTargetNotFound, fmt.Errorf("%d of %d favorites are broken: %s", len(broken), len(checks), strings.Join(broken, ", ")))
+	}
+	return nil
+}
+
+// pinFavorites saves the IDs each ok favorite of the user config resolved to
+// and returns the names of those it changed. Favorites of other layers are
+// read-only and left as they are.
+func pinFavorites(layers *config.Layers, checks []favoriteCheck) (map[string]bool, error) {
+	cfg, cfgPath, err := config.LoadUserWithPath()
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := make(map[string]bool)
+	for _, c := range checks {
+		if c.status != favoriteCheckOK {
+			continue
+		}
+		if src, _ := layers.FavoriteSource(c.entry.Name); src.Name != config.LayerUser {
+			log.Info("Not pinning favorite %q: it comes from %s and is read-only", c.entry.Name, src.Origin("favorites."+c.entry.Name))
+			continue
+		}
+		fav := cfg.Favorites[c.entry.Name]
+		before := fav
+		if t := c.target; t != nil {
+			fav.WorkspaceID, fav.RoleID, fav.OrganizationID = t.WorkspaceID, t.RoleInfo.ID, t.OrganizationID
+			fav.Target, fav.Role = t.WorkspaceName, t.RoleInfo.Name
+		} else {
+			fav.DirectoryID, fav.GroupID, fav.Group = c.group.DirectoryID, c.group.GroupID, c.group.GroupName
+		}
+		if !reflect.DeepEqual(fav, before) {
+			cfg.Favorites[c.entry.Name] = fav
+			pinned[c.entry.Name] = true
+		}
+	}
+
+	if len(pinned) > 0 {
+		log.Info("Saving config...")
+		if err := config.Save(cfg, cfgPath); err != nil {
+			return nil, fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+	return pinned, nil
+}
+
+// checkFavorites resolves favorites against live eligibility, in order. Cloud
+// eligibility is fetched for every provider, and groups eligibility only when
+// a group favorite needs it.
+func checkFavorites(ctx context.Context, entries []config.FavoriteEntry, eligLister eligibilityLister, groupsElig groupsEligibilityLister) ([]favoriteCheck, error) {
+	var cloud []models.EligibleTarget
+	var groups []models.GroupsEligibleTarget
+	var fetchedCloud, fetchedGroups bool
+
+	checks := make([]favoriteCheck, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ResolvedType() == config.FavoriteTypeGroups {
+			if !fetchedGroups {
+				var err error
+				if groups, err = fetchGroupsEligibility(ctx, groupsElig, eligLister); err != nil && !isNotEligible(err) {
+					return nil, err
+				}
+				fetchedGroups = true
+			}
+			checks = append(checks, checkGroupFavorite(entry, groups))
+			continue
+		}
+
+		if !fetchedCloud {
+			var err error
+			// No eligible target at all only makes every cloud favorite
+			// missing.
+			if cloud, err = fetchEligibility(ctx, eligLister, providerAll); err != nil && !isNotEligible(err) {
+				return nil, err
+			}
+			fetchedCloud = true
+		}
+		checks = append(checks, checkCloudFavorite(entry, cloud))
+	}
+	return checks, nil
+}
+
+// isNotEligible reports whether err is the codeNotEligible error of an empty
+// eligibility list.
+func isNotEligible(err error) bool {
+	var ce *cliError
+	return errors.As(err, &ce) && ce.code == codeNotEligible
+}
+
+// checkCloudFavorite resolves a cloud favorite among the eligible targets of
+// its provider as elevation does: by its pinned IDs, else by name.
+func checkCloudFavorite(entry config.FavoriteEntry, targets []models.EligibleTarget) favoriteCheck {
+	c := favoriteCheck{entry: entry}
+
+	matches, _ := matchFavoriteTargets(entry.Favorite, targets)
+	switch len(matches) {
+	case 0:
+		c.status = favoriteCheckMissing
+		c.reason = fmt.Sprintf("no eligible %s target %q with role %q", entry.Provider, entry.Target, entry.Role)
+		var options []string
+		for _, t := range targets {
+			if strings.EqualFold(string(t.CSP), entry.Provider) {
+				options = append(options, describeTarget(t))
+			}
+		}
+		c.candidates = closestCandidates(entry.Describe(), options)
+	case 1:
+		c.status = favoriteCheckOK
+		c.target = matches[0]
+	default:
+		c.status = favoriteCheckAmbiguous
+		c.reason = fmt.Sprintf("%d eligible targets match", len(matches))
+		for _, t := range matches {
+			c.candidates = append(c.candidates, describeTargetCandidate(t))
+		}
+	}
+	return c
+}
+
+// checkGroupFavorite resolves a group favorite among the eligible groups as
+// elevation does: by its pinned group_id, else by name and, when it has one,
+// directory_id.
+func checkGroupFavorite(entry config.FavoriteEntry, groups []models.GroupsEligibleTarget) favoriteCheck {
+	c := favoriteCheck{entry: entry}
+
+	matches, _ := matchFavoriteGroups(entry.Favorite, groups)
+	switch len(matches) {
+	case 0:
+		c.status = favoriteCheckMissing
+		c.reason = fmt.Sprintf("no eligible group %q", entry.Group)
+		options := make([]string, len(groups))
+		for i, g := range groups {
+			options[i] = "groups/" + g.GroupName
+		}
+		c.candidates = closestCandidates(entry.Describe(), options)
+	case 1:
+		c.status = favoriteCheckOK
+		c.group = matches[0]
+	default:
+		c.status = favoriteCheckAmbiguous
+		c.reason = fmt.Sprintf("%d eligible groups match", len(matches))
+		for _, g := range matches {
+			c.candidates = append(c.candidates, fmt.Sprintf("groups/%s (directory %s)", g.GroupName, g.DirectoryID))
+		}
+	}
+	return c
+}
+
+// describeTarget renders an eligible target as config.Favorite.Describe does.
+func describeTarget(t models.EligibleTarget) string {
+	return strings.ToLower(string(t.CSP)) + "/" + t.WorkspaceName + "/" + t.RoleInfo.Name
+}
+
+// closestCandidates returns up to maxFavoriteCandidates distinct options,
+// closest to want first by case-insensitive edit distance.
+func closestCandidates(want string, options []string) []string {
+	type scored struct {
+		option   string
+		distance int
+	}
+	var ranked []scored
+	for _, option := range options {
+		if !slices.ContainsFunc(ranked, func(s scored) bool { return s.option == option }) {
+			ranked = append(ranked, scored{option, editDistance(strings.ToLower(want), strings.ToLower(option))})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].distance != ranked[j].distance {
+			return ranked[i].distance < ranked[j].distance
+		}
+		return ranked[i].option < ranked[j].option
+	})
+
+	var closest []string
+	for _, s := range ranked[:min(len(ranked), maxFavoriteCandidates)] {
+		closest = append(closest, s.option)
+	}
+	return closest
+}
+
+// editDistance is the Levenshtein distance between a and b, in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
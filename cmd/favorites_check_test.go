@@ -0,0 +1,180 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+const checkConfig = "favorites:\n" +
+	"  prod:\n    provider: aws\n    target: Prod\n    role: Admin\n" +
+	"  dup:\n    provider: azure\n    target: Shared\n    role: Reader\n" +
+	"  renamed:\n    provider: aws\n    target: Prod-Old\n    role: Admin\n" +
+	"  ops:\n    type: groups\n    group: Ops\n"
+
+// checkListers returns eligibility with one AWS target, two Azure targets of
+// the same name and the Ops group.
+func checkListers() (*mockEligibilityLister, *mockGroupsEligibilityLister) {
+	eligLister := &mockEligibilityLister{listFunc: func(_ context.Context, csp models.CSP) (*models.EligibilityResponse, error) {
+		switch csp {
+		case models.CSPAWS:
+			return &models.EligibilityResponse{Response: []models.EligibleTarget{
+				{WorkspaceID: "111", WorkspaceName: "Prod", RoleInfo: models.RoleInfo{ID: "r-admin", Name: "Admin"}},
+				{WorkspaceID: "222", WorkspaceName: "Sandbox", RoleInfo: models.RoleInfo{ID: "r-admin", Name: "Admin"}},
+			}}, nil
+		case models.CSPAzure:
+			return &models.EligibilityResponse{Response: []models.EligibleTarget{
+				{WorkspaceID: "sub-1", WorkspaceName: "Shared", RoleInfo: models.RoleInfo{ID: "reader", Name: "Reader"}},
+				{WorkspaceID: "sub-2", WorkspaceName: "Shared", RoleInfo: models.RoleInfo{ID: "reader", Name: "Reader"}},
+			}}, nil
+		}
+		return &models.EligibilityResponse{}, nil
+	}}
+	groupsElig := &mockGroupsEligibilityLister{response: &models.GroupsEligibilityResponse{
+		Response: []models.GroupsEligibleTarget{{GroupName: "Ops", GroupID: "grp-ops", DirectoryID: "dir-1"}},
+	}}
+	return eligLister, groupsElig
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesCheck(t *testing.T) {
+	writeConfigFile(t, checkConfig)
+	eligLister, groupsElig := checkListers()
+
+	output, err := executeCommand(NewFavoritesCommandWithAllDeps(eligLister, nil, nil, groupsElig), "check")
+	if err == nil || classifyError(err, true).code != codeTargetNotFound {
+		t.Fatalf("check = %v, want a %s error", err, codeTargetNotFound)
+	}
+	if !strings.Contains(err.Error(), "2 of 4 favorites are broken: dup, renamed") {
+		t.Errorf("error = %v", err)
+	}
+	for _, want := range []string{
+		"ok         prod: aws/Prod/Admin\n",
+		"ok         ops: groups/Ops\n",
+		"ambiguous  dup: azure/Shared/Reader - 2 eligible targets match; matches: azure/Shared/Reader (workspace sub-1), azure/Shared/Reader (workspace sub-2)\n",
+		`missing    renamed: aws/Prod-Old/Admin - no eligible aws target "Prod-Old" with role "Admin"; closest: aws/Prod/Admin, aws/Sandbox/Admin` + "\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	if _, err := executeCommand(NewFavoritesCommandWithAllDeps(eligLister, nil, nil, groupsElig), "check", "prod", "ops"); err != nil {
+		t.Errorf("check of ok favorites = %v", err)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesCheck_Fix(t *testing.T) {
+	cfgPath := writeConfigFile(t, checkConfig)
+	eligLister, groupsElig := checkListers()
+
+	output, err := executeCommand(NewFavoritesCommandWithAllDeps(eligLister, nil, nil, groupsElig), "check", "prod", "ops", "--fix")
+	if err != nil {
+		t.Fatalf("check --fix: %v", err)
+	}
+	if !strings.Contains(output, "prod: aws/Prod/Admin (pinned)") {
+		t.Errorf("output = %q", output)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fav := cfg.Favorites["prod"]; fav.WorkspaceID != "111" || fav.RoleID != "r-admin" {
+		t.Errorf("prod = %+v, want it pinned to workspace 111 and role r-admin", fav)
+	}
+	if fav := cfg.Favorites["ops"]; fav.DirectoryID != "dir-1" || fav.GroupID != "grp-ops" {
+		t.Errorf("ops = %+v, want it pinned to dir-1 and grp-ops", fav)
+	}
+
+	// A pinned favorite resolves by ID after its target is renamed, and --fix
+	// saves the new names.
+	eligLister.listFunc = func(_ context.Context, csp models.CSP) (*models.EligibilityResponse, error) {
+		if csp != models.CSPAWS {
+			return &models.EligibilityResponse{}, nil
+		}
+		return &models.EligibilityResponse{Response: []models.EligibleTarget{
+			{WorkspaceID: "111", WorkspaceName: "Production", RoleInfo: models.RoleInfo{ID: "r-admin", Name: "Administrator"}},
+		}}, nil
+	}
+	output, err = executeCommand(NewFavoritesCommandWithAllDeps(eligLister, nil, nil, groupsElig), "check", "prod", "--fix")
+	if err != nil {
+		t.Fatalf("check of a pinned favorite: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "prod: aws/Prod/Admin (pinned)") {
+		t.Errorf("output = %q", output)
+	}
+	if cfg, err = config.Load(cfgPath); err != nil {
+		t.Fatal(err)
+	}
+	if fav := cfg.Favorites["prod"]; fav.Target != "Production" || fav.Role != "Administrator" {
+		t.Errorf("prod = %+v, want its names refreshed", fav)
+	}
+
+	output, err = executeCommand(NewFavoritesCommandWithAllDeps(eligLister, nil, nil, groupsElig), "check", "prod", "--fix")
+	if err != nil || strings.Contains(output, "(pinned)") {
+		t.Errorf("second check --fix = %q, %v; want nothing left to pin", output, err)
+	}
+
+	// A pinned group favorite resolves by group_id after the group is renamed,
+	// even beside a new group of its old name, and --fix saves the new name.
+	groupsElig.response = &models.GroupsEligibilityResponse{Response: []models.GroupsEligibleTarget{
+		{GroupName: "Ops", GroupID: "grp-new", DirectoryID: "dir-1"},
+		{GroupName: "Operations", GroupID: "grp-ops", DirectoryID: "dir-1"},
+	}}
+	output, err = executeCommand(NewFavoritesCommandWithAllDeps(eligLister, nil, nil, groupsElig), "check", "ops", "--fix")
+	if err != nil || !strings.Contains(output, "ok         ops: groups/Ops (pinned)") {
+		t.Fatalf("check of a renamed pinned group = %q, %v", output, err)
+	}
+	if cfg, err = config.Load(cfgPath); err != nil {
+		t.Fatal(err)
+	}
+	if fav := cfg.Favorites["ops"]; fav.Group != "Operations" || fav.GroupID != "grp-ops" {
+		t.Errorf("ops = %+v, want its name refreshed", fav)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG and mutates the package-level output format.
+func TestFavoritesCheck_JSONOutput(t *testing.T) {
+	writeConfigFile(t, checkConfig)
+	eligLister, groupsElig := checkListers()
+
+	root := newTestRootCommand()
+	root.AddCommand(NewFavoritesCommandWithAllDeps(eligLister, nil, nil, groupsElig))
+	stdout, _, err := executeCommandStreams(root, "favorites", "check", "renamed", "--output", "json")
+	if err == nil {
+		t.Fatal("check of a missing favorite succeeded")
+	}
+	var got []favoriteCheckOutput
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	want := []favoriteCheckOutput{{
+		Name: "renamed", Type: "cloud", Favorite: "aws/Prod-Old/Admin", Status: favoriteCheckMissing,
+		Reason:     `no eligible aws target "Prod-Old" with role "Admin"`,
+		Candidates: []string{"aws/Prod/Admin", "aws/Sandbox/Admin"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("check -o json = %+v, want %+v", got, want)
+	}
+}
+
+func TestClosestCandidates(t *testing.T) {
+	got := closestCandidates("aws/Prod-EastUS/Admin", []string{
+		"aws/Dev/Reader", "aws/Prod-EastUS-2/Admin", "aws/prod-eastus/Owner", "aws/Prod-EastUS-2/Admin", "aws/Sandbox/Admin",
+	})
+	want := []string{"aws/Prod-EastUS-2/Admin", "aws/prod-eastus/Owner", "aws/Sandbox/Admin"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("closestCandidates() = %q, want %q", got, want)
+	}
+	if got := closestCandidates("x", nil); got != nil {
+		t.Errorf("closestCandidates(nil) = %q, want nil", got)
+	}
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// favoriteEditFlags are the flags of favorites edit: the first four change
+// what a favorite elevates to, the others only describe it.
+var favoriteEditFlags = []string{"provider", "target", "role", "group", "description", "tags", "default-reason"}
+
+// addFavoriteAnnotationFlags registers the flags that set a favorite's
+// description, tags and default reason.
+func addFavoriteAnnotationFlags(cmd *cobra.Command) {
+	cmd.Flags().String("description", "", "Description shown in the selector and favorites list")
+	cmd.Flags().String("tags", "", "Comma-separated tags for favorites list --tag")
+	cmd.Flags().String("default-reason", "", "Reason request submit --favorite uses when --reason is not given")
+}
+
+// applyFavoriteAnnotations copies the annotation flags that were set onto
+// fav; an empty value clears the field.
+func applyFavoriteAnnotations(cmd *cobra.Command, fav *config.Favorite) error {
+	if cmd.Flags().Changed("description") {
+		fav.Description, _ = cmd.Flags().GetString("description")
+	}
+	if cmd.Flags().Changed("tags") {
+		tags, _ := cmd.Flags().GetString("tags")
+		fav.Tags = config.ParseTags(tags)
+		if err := config.ValidateTags(fav.Tags); err != nil {
+			return withCode(codeUsage, err)
+		}
+	}
+	if cmd.Flags().Changed("default-reason") {
+		fav.DefaultReason, _ = cmd.Flags().GetString("default-reason")
+	}
+	return nil
+}
+
+// checkUserFavorite refuses to change a favorite that comes from a layer
+// other than the user config.
+func checkUserFavorite(layers *config.Layers, name string) error {
+	src, ok := layers.FavoriteSource(name)
+	switch {
+	case ok && src.Name == config.LayerEnv:
+		return fmt.Errorf("favorite %q comes from %s and is read-only", name, src.Origin("favorites."+name))
+	case ok && src.Name != config.LayerUser:
+		return fmt.Errorf("favorite %q comes from the %s config %s and is read-only", name, src.Name, src.Path)
+	}
+	return nil
+}
+
+func newFavoritesRenameCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rename <old> <new>",
+		Short:   "Rename a favorite",
+		Long:    "Rename a saved favorite, keeping its target, profile and annotations.",
+		Example: "  grant favorites rename prod prod-admin",
+		Args:    cobra.ExactArgs(2),
+		RunE:    runFavoritesRename,
+	}
+}
+
+func runFavoritesRename(cmd *cobra.Command, args []string) error {
+	oldName, newName := args[0], args[1]
+
+	log.Info("Loading config...")
+	layers, err := config.LoadLayers()
+	if err != nil {
+		return err
+	}
+	if err := checkUserFavorite(layers, oldName); err != nil {
+		return err
+	}
+	// A name another layer uses would be shadowed by, or shadow, the renamed
+	// favorite.
+	if _, exists := layers.Config.Favorites[newName]; exists {
+		return fmt.Errorf("favorite %q already exists", newName)
+	}
+
+	cfg, cfgPath, err := config.LoadUserWithPath()
+	if err != nil {
+		return err
+	}
+	if err := config.RenameFavorite(cfg, oldName, newName); err != nil {
+		return withCode(codeTargetNotFound, err)
+	}
+
+	log.Info("Saving config...")
+	if err := config.Save(cfg, cfgPath); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Renamed favorite %q to %q\n", oldName, newName)
+	return nil
+}
+
+// newFavoritesEditCommand creates the edit command with a custom RunE
+// function, so tests can inject the selector and eligibility listers.
+func newFavoritesEditCommand(runFn func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Change a favorite",
+		Long: `Change a saved favorite. Flags override single fields: --target and --role
+(with --provider) make it a cloud favorite, --group a group favorite, and
+--description, --tags and --default-reason annotate it; an empty value clears
+an annotation. Without flags, pick the favorite's new target from your
+eligible targets and groups; its annotations are kept.`,
+		Example: `  # Re-pick the target interactively
+  grant favorites edit prod-admin
+
+  # Switch the role
+  grant favorites edit prod-admin --role Owner
+
+  # Annotate it
+  grant favorites edit prod-admin --description "Production admin" --tags prod,aws`,
+		Args: cobra.ExactArgs(1),
+		RunE: runFn,
+	}
+
+	cmd.Flags().StringP("provider", "p", "", "Cloud provider: azure, aws, gcp")
+	cmd.Flags().StringP("target", "t", "", "Target name (subscription, resource group, etc.)")
+	cmd.Flags().StringP("role", "r", "", "Role name")
+	cmd.Flags().StringP("group", "g", "", "Group name; makes it a group favorite")
+	addFavoriteAnnotationFlags(cmd)
+	cmd.MarkFlagsMutuallyExclusive("group", "target")
+	cmd.MarkFlagsMutuallyExclusive("group", "role")
+
+	return cmd
+}
+
+// hasFavoriteEditFlags reports whether edit was given any field to change,
+// and so needs no interactive re-pick.
+func hasFavoriteEditFlags(cmd *cobra.Command) bool {
+	return slices.ContainsFunc(favoriteEditFlags, cmd.Flags().Changed)
+}
+
+// runFavoritesEditProduction authenticates only for the interactive re-pick.
+func runFavoritesEditProduction(cmd *cobra.Command, args []string) error {
+	if hasFavoriteEditFlags(cmd) {
+		return runFavoritesEdit(cmd, args, nil, nil, nil)
+	}
+	if !ui.IsInteractive() {
+		return fmt.Errorf("%w; pass --target and --role, --group or an annotation flag", ui.ErrNotInteractive)
+	}
+
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err != nil {
+		return err
+	}
+	_, scaService, _, err := bootstrapSCAService()
+	if err != nil {
+		return err
+	}
+	cachedLister, err := buildCachedLister(cfg, false, scaService, scaService)
+	if err != nil {
+		return err
+	}
+	return runFavoritesEdit(cmd, args, cachedLister, &uiUnifiedSelector{}, cachedLister)
+}
+
+func runFavoritesEdit(cmd *cobra.Command, args []string, eligLister eligibilityLister, sel unifiedSelector, groupsElig groupsEligibilityLister) error {
+	name := args[0]
+
+	log.Info("Loading config...")
+	layers, err := config.LoadLayers()
+	if err != nil {
+		return err
+	}
+	if err := checkUserFavorite(layers, name); err != nil {
+		return err
+	}
+	cfg, cfgPath, err := config.LoadUserWithPath()
+	if err != nil {
+		return err
+	}
+	fav, err := config.GetFavorite(cfg, name)
+	if err != nil {
+		return withCode(codeTargetNotFound, fmt.Errorf("favorite %q not found, run 'grant favorites list'", name))
+	}
+
+	if hasFavoriteEditFlags(cmd) {
+		if err := applyFavoriteTargetFlags(cmd, &fav, layers.Config.DefaultProvider); err != nil {
+			return err
+		}
+	} else {
+		picked, _, err := selectFavoriteInteractive("", eligLister, groupsElig, sel, nil, name, cfg)
+		if err != nil {
+			return err
+		}
+		picked.Profile = fav.Profile
+		picked.Description, picked.Tags, picked.DefaultReason = fav.Description, fav.Tags, fav.DefaultReason
+		fav = picked
+	}
+	if err := applyFavoriteAnnotations(cmd, &fav); err != nil {
+		return err
+	}
+
+	if err := config.Validate(&config.Config{Favorites: map[string]config.Favorite{name: fav}}); err != nil {
+		return withCode(codeUsage, err)
+	}
+	cfg.Favorites[name] = fav
+
+	log.Info("Saving config...")
+	if err := config.Save(cfg, cfgPath); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Updated favorite %q: %s\n", name, fav.Describe())
+	return nil
+}
+
+// applyFavoriteTargetFlags applies the target flags of edit to fav. --group
+// turns it into a group favorite; --target or --role on a group favorite
+// turn it into a cloud favorite, which then needs both.
+func applyFavoriteTargetFlags(cmd *cobra.Command, fav *config.Favorite, defaultProvider string) error {
+	flags := cmd.Flags()
+	if flags.Changed("group") {
+		if flags.Changed("provider") {
+			return usageErrorf("--provider cannot be used with --group")
+		}
+		group, _ := flags.GetString("group")
+		*fav = config.Favorite{
+			Type: config.FavoriteTypeGroups, Provider: "azure", Group: group,
+			Profile: fav.Profile, Description: fav.Description, Tags: fav.Tags, DefaultReason: fav.DefaultReason,
+		}
+		return nil
+	}
+
+	if !flags.Changed("provider") && !flags.Changed("target") && !flags.Changed("role") {
+		return nil
+	}
+	if fav.ResolvedType() == config.FavoriteTypeGroups {
+		if !flags.Changed("target") || !flags.Changed("role") {
+			return usageErrorf("both --target and --role are required to turn a group favorite into a cloud favorite")
+		}
+		fav.Type, fav.Group, fav.DirectoryID, fav.GroupID = config.FavoriteTypeCloud, "", "", ""
+		fav.Provider = cmp.Or(defaultProvider, config.DefaultFavoriteProvider)
+	}
+	if flags.Changed("provider") {
+		provider, _ := flags.GetString("provider")
+		if provider == providerAll {
+			return usageErrorf("--provider all cannot be saved in a favorite; name the target's provider")
+		}
+		fav.Provider = provider
+	}
+	// The pinned IDs are of the old target.
+	fav.WorkspaceID, fav.RoleID, fav.OrganizationID = "", "", ""
+	if flags.Changed("target") {
+		fav.Target, _ = flags.GetString("target")
+	}
+	if flags.Changed("role") {
+		fav.Role, _ = flags.GetString("role")
+	}
+	if fav.Target == "" || fav.Role == "" {
+		return errors.New("target and role cannot be empty")
+	}
+	return nil
+}
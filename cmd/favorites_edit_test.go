@@ -0,0 +1,211 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+const editConfig = "favorites:\n" +
+	"  prod:\n    type: cloud\n    provider: aws\n    target: Prod\n    role: Admin\n    profile: grant\n" +
+	"    description: Production admin\n    tags: [prod, aws]\n    default_reason: on-call\n" +
+	"  ops:\n    type: groups\n    provider: azure\n    group: Ops\n    tags: [azure]\n"
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesRename(t *testing.T) {
+	cfgPath := writeConfigFile(t, editConfig)
+
+	output, err := executeCommand(NewFavoritesCommand(), "rename", "prod", "prod-admin")
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if output != "Renamed favorite \"prod\" to \"prod-admin\"\n" {
+		t.Errorf("output = %q", output)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Favorites["prod"]; ok {
+		t.Error("old name still saved")
+	}
+	if fav := cfg.Favorites["prod-admin"]; fav.Role != "Admin" || fav.Description != "Production admin" || fav.Profile != "grant" {
+		t.Errorf("renamed favorite = %+v, want its fields kept", fav)
+	}
+
+	if _, err := executeCommand(NewFavoritesCommand(), "rename", "ops", "prod-admin"); err == nil || !strings.Contains(err.Error(), `favorite "prod-admin" already exists`) {
+		t.Errorf("rename onto an existing name = %v", err)
+	}
+	_, err = executeCommand(NewFavoritesCommand(), "rename", "missing", "x")
+	if err == nil || classifyError(err, true).code != codeTargetNotFound {
+		t.Errorf("rename of an unknown favorite = %v, want %s", err, codeTargetNotFound)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesEdit_Flags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+		want    config.Favorite // of prod, or of ops when args edit it
+	}{
+		{
+			name: "role override keeps the rest",
+			args: []string{"prod", "--role", "Owner"},
+			want: config.Favorite{Type: "cloud", Provider: "aws", Target: "Prod", Role: "Owner", Profile: "grant",
+				Description: "Production admin", Tags: []string{"prod", "aws"}, DefaultReason: "on-call"},
+		},
+		{
+			name: "annotations set and cleared",
+			args: []string{"prod", "--description", "", "--tags", "prod, critical,prod", "--default-reason", "incident"},
+			want: config.Favorite{Type: "cloud", Provider: "aws", Target: "Prod", Role: "Admin", Profile: "grant",
+				Tags: []string{"prod", "critical"}, DefaultReason: "incident"},
+		},
+		{
+			name: "group turns it into a group favorite",
+			args: []string{"prod", "--group", "Admins"},
+			want: config.Favorite{Type: "groups", Provider: "azure", Group: "Admins", Profile: "grant",
+				Description: "Production admin", Tags: []string{"prod", "aws"}, DefaultReason: "on-call"},
+		},
+		{
+			name: "target and role turn a group favorite into a cloud favorite",
+			args: []string{"ops", "--provider", "gcp", "--target", "proj", "--role", "Viewer"},
+			want: config.Favorite{Type: "cloud", Provider: "gcp", Target: "proj", Role: "Viewer", Tags: []string{"azure"}},
+		},
+		{name: "group favorite needs target and role", args: []string{"ops", "--role", "Viewer"}, wantErr: "both --target and --role"},
+		{name: "invalid provider", args: []string{"prod", "--provider", "oci"}, wantErr: `invalid provider "oci"`},
+		{name: "provider all", args: []string{"prod", "--provider", "all"}, wantErr: "--provider all cannot be saved"},
+		{name: "unknown favorite", args: []string{"missing", "--role", "R"}, wantErr: `favorite "missing" not found`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgPath := writeConfigFile(t, editConfig)
+
+			_, err := executeCommand(NewFavoritesCommand(), append([]string{"edit"}, tt.args...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("edit = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("edit: %v", err)
+			}
+			cfg, err := config.Load(cfgPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cfg.Favorites[tt.args[0]]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("favorite = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesEdit_InteractiveRepick(t *testing.T) {
+	cfgPath := writeConfigFile(t, editConfig)
+
+	eligLister := &mockEligibilityLister{listFunc: func(_ context.Context, csp models.CSP) (*models.EligibilityResponse, error) {
+		if csp != models.CSPAWS {
+			return &models.EligibilityResponse{}, nil
+		}
+		return &models.EligibilityResponse{Response: []models.EligibleTarget{
+			{WorkspaceName: "Staging", RoleInfo: models.RoleInfo{Name: "Admin"}, CSP: models.CSPAWS},
+		}}, nil
+	}}
+	sel := &mockUnifiedSelector{selectFunc: func(items []selectionItem) (*selectionItem, error) {
+		return &items[0], nil
+	}}
+
+	output, err := executeCommand(NewFavoritesCommandWithAllDeps(eligLister, sel, nil, nil), "edit", "prod")
+	if err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if !strings.Contains(output, `Updated favorite "prod": aws/Staging/Admin`) {
+		t.Errorf("output = %q", output)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := config.Favorite{Provider: "aws", Target: "Staging", Role: "Admin", Profile: "grant",
+		Description: "Production admin", Tags: []string{"prod", "aws"}, DefaultReason: "on-call"}
+	if got := cfg.Favorites["prod"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("favorite = %+v, want %+v", got, want)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG and GRANT_FAVORITE_* for the process.
+func TestFavoritesEditRename_EnvFavoriteIsReadOnly(t *testing.T) {
+	writeConfigFile(t, "favorites: {}\n")
+	t.Setenv("GRANT_FAVORITE_CI_DEPLOY", "aws/Prod/Deployer")
+
+	for _, args := range [][]string{{"edit", "ci-deploy", "--role", "R"}, {"rename", "ci-deploy", "deploy"}} {
+		_, err := executeCommand(NewFavoritesCommand(), args...)
+		if err == nil || !strings.Contains(err.Error(), "is read-only") {
+			t.Errorf("%s = %v, want a read-only error", args[0], err)
+		}
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesList_Tag(t *testing.T) {
+	writeConfigFile(t, editConfig)
+
+	output, err := executeCommand(NewFavoritesCommand(), "list")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	want := "ops: groups/Ops #azure\nprod: aws/Prod/Admin - Production admin #prod #aws\n"
+	if output != want {
+		t.Errorf("list output = %q, want %q", output, want)
+	}
+
+	output, err = executeCommand(NewFavoritesCommand(), "list", "--tag", "PROD")
+	if err != nil {
+		t.Fatalf("list --tag: %v", err)
+	}
+	if !strings.HasPrefix(output, "prod: ") || strings.Contains(output, "ops:") {
+		t.Errorf("list --tag PROD = %q, want only prod", output)
+	}
+
+	output, err = executeCommand(NewFavoritesCommand(), "list", "--tag", "none")
+	if err != nil {
+		t.Fatalf("list --tag: %v", err)
+	}
+	if output != "No favorites tagged \"none\".\n" {
+		t.Errorf("list --tag none = %q", output)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesAdd_Annotations(t *testing.T) {
+	cfgPath := writeConfigFile(t, "favorites: {}\n")
+
+	lister := targetsLister(eligibleTarget(models.CSPAWS, "Dev", "Admin"), eligibleTarget(models.CSPAzure, "T", "R"))
+	if _, err := executeCommand(NewFavoritesCommandWithAllDeps(lister, nil, nil, nil), "add", "dev", "--provider", "aws", "--target", "Dev", "--role", "Admin",
+		"--description", "Dev admin", "--tags", "dev,aws", "--default-reason", "testing"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fav := cfg.Favorites["dev"]
+	if fav.Description != "Dev admin" || !reflect.DeepEqual(fav.Tags, []string{"dev", "aws"}) || fav.DefaultReason != "testing" {
+		t.Errorf("favorite = %+v, want its annotations saved", fav)
+	}
+
+	_, err = executeCommand(NewFavoritesCommandWithAllDeps(lister, nil, nil, nil), "add", "bad", "--target", "T", "--role", "R", "--tags", "a b")
+	if err == nil || !strings.Contains(err.Error(), "tag") {
+		t.Errorf("add with an invalid tag = %v, want a tag error", err)
+	}
+}
@@ -27,9 +27,10 @@ func twoGroupsSameName() []models.GroupsEligibleTarget {
 
 // assertFavoriteResolvesToGroup reloads the saved favorite and pushes it back
 // through findMatchingGroup — the production lookup behind `grant --favorite`.
-// The round trip is what makes dropping `fav.DirectoryID = ...` fail: without
-// it the favorite still names the right group, but resolution silently picks
-// the first same-named group, in the wrong directory.
+// The round trip is what makes dropping both `fav.DirectoryID = ...` and
+// `fav.GroupID = ...` fail: without them the favorite still names the right
+// group, but resolution silently picks the first same-named group, in the
+// wrong directory.
 func assertFavoriteResolvesToGroup(t *testing.T, configPath, favName string, groups []models.GroupsEligibleTarget, wantDirectoryID, wantGroupID string) {
 	t.Helper()
 
@@ -44,10 +45,13 @@ func assertFavoriteResolvesToGroup(t *testing.T, configPath, favName string, gro
 	if fav.DirectoryID != wantDirectoryID {
 		t.Errorf("persisted DirectoryID = %q, want %q", fav.DirectoryID, wantDirectoryID)
 	}
+	if fav.GroupID != wantGroupID {
+		t.Errorf("persisted GroupID = %q, want %q", fav.GroupID, wantGroupID)
+	}
 
-	match := findMatchingGroup(groups, fav.Group, fav.DirectoryID)
+	match := findMatchingGroup(groups, fav.Group, fav.DirectoryID, fav.GroupID)
 	if match == nil {
-		t.Fatalf("findMatchingGroup(%q, %q) = nil", fav.Group, fav.DirectoryID)
+		t.Fatalf("findMatchingGroup(%q, %q, %q) = nil", fav.Group, fav.DirectoryID, fav.GroupID)
 	}
 	if match.GroupID != wantGroupID {
 		t.Errorf("favorite resolved to group %q, want %q", match.GroupID, wantGroupID)
@@ -129,7 +133,7 @@ func TestFavoritesAdd_HonorsNonDefaultProvider(t *testing.T) {
 	}
 
 	rootCmd := newTestRootCommand()
-	rootCmd.AddCommand(NewFavoritesCommand())
+	rootCmd.AddCommand(NewFavoritesCommandWithAllDeps(targetsLister(eligibleTarget(models.CSPAWS, "ws-name", "role-name")), nil, nil, nil))
 
 	out, err := executeCommand(rootCmd, "favorites", "add", "fav-cloud", "--target", "ws-name", "--role", "role-name")
 	if err != nil {
@@ -282,3 +286,32 @@ func TestFavoritesRemove_RejectsExtraArgs(t *testing.T) {
 		}
 	}
 }
+
+func TestFindMatchingGroup_GroupIDFirst(t *testing.T) {
+	groups := []models.GroupsEligibleTarget{
+		{GroupName: "grp-name", GroupID: "grp-id-a", DirectoryID: "dir-id-a"},
+		{GroupName: "grp-renamed", GroupID: "grp-id-b", DirectoryID: "dir-id-a"},
+	}
+
+	tests := []struct {
+		name, group, directoryID, groupID string
+		wantGroupID                       string
+	}{
+		{name: "name only", group: "grp-name", wantGroupID: "grp-id-a"},
+		{name: "group ID wins over the name", group: "grp-name", groupID: "grp-id-b", wantGroupID: "grp-id-b"},
+		{name: "gone group ID falls back to the name", group: "grp-name", groupID: "grp-id-gone", wantGroupID: "grp-id-a"},
+		{name: "group ID outside the directory", group: "grp-gone", directoryID: "dir-id-b", groupID: "grp-id-b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := findMatchingGroup(groups, tt.group, tt.directoryID, tt.groupID)
+			var got string
+			if match != nil {
+				got = match.GroupID
+			}
+			if got != tt.wantGroupID {
+				t.Errorf("findMatchingGroup() = %q, want %q", got, tt.wantGroupID)
+			}
+		})
+	}
+}
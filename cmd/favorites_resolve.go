@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+// favoriteNotices receives the note that a pinned favorite's names were
+// refreshed; stdout stays the command's output. Replaced in tests.
+var favoriteNotices io.Writer = os.Stderr
+
+// matchFavoriteTargets returns the eligible targets of fav's provider that it
+// points at. A favorite pinned by workspace_id and role_id (and
+// organization_id) matches by them first, and byID reports that it did;
+// otherwise, or when the pinned target is gone, it matches by target and role
+// name, which may find several.
+func matchFavoriteTargets(fav config.Favorite, targets []models.EligibleTarget) (matches []*models.EligibleTarget, byID bool) {
+	var eligible []*models.EligibleTarget
+	for i := range targets {
+		if targets[i].CSP == "" || strings.EqualFold(string(targets[i].CSP), fav.Provider) {
+			eligible = append(eligible, &targets[i])
+		}
+	}
+
+	if fav.WorkspaceID != "" {
+		for _, t := range eligible {
+			if t.WorkspaceID == fav.WorkspaceID &&
+				(fav.RoleID == "" || t.RoleInfo.ID == fav.RoleID) &&
+				(fav.OrganizationID == "" || t.OrganizationID == fav.OrganizationID) {
+				matches = append(matches, t)
+			}
+		}
+		if len(matches) > 0 {
+			return matches, true
+		}
+	}
+
+	for _, t := range eligible {
+		if strings.EqualFold(t.WorkspaceName, fav.Target) && strings.EqualFold(t.RoleInfo.Name, fav.Role) {
+			matches = append(matches, t)
+		}
+	}
+	return matches, false
+}
+
+// matchFavoriteGroups returns the eligible groups a group favorite points
+// at. A favorite pinned by group_id matches by it first, and byID reports
+// that it did; otherwise, or when the pinned group is gone, it matches by
+// name, which may find several. Either way a directory_id must match too.
+func matchFavoriteGroups(fav config.Favorite, groups []models.GroupsEligibleTarget) (matches []*models.GroupsEligibleTarget, byID bool) {
+	var eligible []*models.GroupsEligibleTarget
+	for i := range groups {
+		if fav.DirectoryID == "" || groups[i].DirectoryID == fav.DirectoryID {
+			eligible = append(eligible, &groups[i])
+		}
+	}
+
+	if fav.GroupID != "" {
+		for _, g := range eligible {
+			if g.GroupID == fav.GroupID {
+				matches = append(matches, g)
+			}
+		}
+		if len(matches) > 0 {
+			return matches, true
+		}
+	}
+
+	for _, g := range eligible {
+		if strings.EqualFold(g.GroupName, fav.Group) {
+			matches = append(matches, g)
+		}
+	}
+	return matches, false
+}
+
+// describeTargetCandidate renders an eligible target with its workspace ID,
+// which tells targets of the same name apart.
+func describeTargetCandidate(t *models.EligibleTarget) string {
+	return fmt.Sprintf("%s (workspace %s)", describeTarget(*t), t.WorkspaceID)
+}
+
+// resolveFavoriteTarget finds the eligible target a cloud favorite elevates
+// to. Several targets matching by name is an error listing them, rather than
+// a guess; a target found by its pinned IDs under new names refreshes the
+// names saved in the favorite.
+func resolveFavoriteTarget(name string, fav config.Favorite, targets []models.EligibleTarget) (*models.EligibleTarget, error) {
+	matches, byID := matchFavoriteTargets(fav, targets)
+	switch len(matches) {
+	case 0:
+		return nil, withCode(codeTargetNotFound, fmt.Errorf("target %q or role %q not found, run 'grant' to see available options", fav.Target, fav.Role))
+	case 1:
+	default:
+		candidates := make([]string, len(matches))
+		for i, t := range matches {
+			candidates[i] = describeTargetCandidate(t)
+		}
+		return nil, withCode(codeTargetNotFound, fmt.Errorf("favorite %q matches %d eligible targets: %s; pick one with 'grant favorites edit %s'",
+			name, len(matches), strings.Join(candidates, ", "), name))
+	}
+
+	target := matches[0]
+	if byID && (target.WorkspaceName != fav.Target || target.RoleInfo.Name != fav.Role) {
+		refreshFavoriteNames(name, target)
+	}
+	return target, nil
+}
+
+// refreshFavoriteNames saves the current workspace and role names of a
+// pinned favorite's target in the user config. It is best effort: a failure,
+// or a favorite from a read-only layer, leaves the old names, which the
+// pinned IDs make harmless.
+func refreshFavoriteNames(name string, target *models.EligibleTarget) {
+	layers, err := config.LoadLayers()
+	if err != nil {
+		log.Info("Not refreshing favorite %q: %v", name, err)
+		return
+	}
+	if src, _ := layers.FavoriteSource(name); src.Name != config.LayerUser {
+		log.Info("Not refreshing favorite %q: it comes from %s and is read-only", name, src.Origin("favorites."+name))
+		return
+	}
+	cfg, cfgPath, err := config.LoadUserWithPath()
+	if err != nil {
+		log.Info("Not refreshing favorite %q: %v", name, err)
+		return
+	}
+	fav, ok := cfg.Favorites[name]
+	if !ok {
+		return
+	}
+	old := fav.Describe()
+	fav.Target, fav.Role = target.WorkspaceName, target.RoleInfo.Name
+	cfg.Favorites[name] = fav
+	if err := config.Save(cfg, cfgPath); err != nil {
+		log.Info("Not refreshing favorite %q: failed to save config: %v", name, err)
+		return
+	}
+	fmt.Fprintf(favoriteNotices, "Favorite %q was renamed upstream; updated %s to %s\n", name, old, fav.Describe())
+}
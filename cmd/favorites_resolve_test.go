@@ -0,0 +1,127 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+// Not parallel: sets GRANT_CONFIG and replaces favoriteNotices.
+func TestResolveFavoriteTarget(t *testing.T) {
+	targets := []models.EligibleTarget{
+		{WorkspaceID: "111", OrganizationID: "o-1", WorkspaceName: "Prod-EastUS", RoleInfo: models.RoleInfo{ID: "r-1", Name: "Admin"}, CSP: models.CSPAzure},
+		{WorkspaceID: "222", OrganizationID: "o-1", WorkspaceName: "Prod-EastUS", RoleInfo: models.RoleInfo{ID: "r-1", Name: "Admin"}, CSP: models.CSPAzure},
+		{WorkspaceID: "333", OrganizationID: "o-1", WorkspaceName: "Prod-EastUS-2", RoleInfo: models.RoleInfo{ID: "r-1", Name: "Admin"}, CSP: models.CSPAzure},
+		{WorkspaceID: "111", OrganizationID: "o-1", WorkspaceName: "Prod-EastUS", RoleInfo: models.RoleInfo{ID: "r-1", Name: "Admin"}, CSP: models.CSPAWS},
+	}
+	byName := config.Favorite{Provider: "azure", Target: "prod-eastus", Role: "admin"}
+	pinned := config.Favorite{Provider: "azure", Target: "Prod-EastUS", Role: "Admin", WorkspaceID: "222", RoleID: "r-1", OrganizationID: "o-1"}
+
+	tests := []struct {
+		name       string
+		fav        config.Favorite
+		wantID     string
+		wantErr    string
+		wantNotice bool
+	}{
+		{name: "pinned matches by ID", fav: pinned, wantID: "222"},
+		{name: "unique name", fav: config.Favorite{Provider: "azure", Target: "Prod-EastUS-2", Role: "Admin"}, wantID: "333"},
+		{
+			name:    "shared name is ambiguous",
+			fav:     byName,
+			wantErr: `favorite "fav" matches 2 eligible targets: azure/Prod-EastUS/Admin (workspace 111), azure/Prod-EastUS/Admin (workspace 222)`,
+		},
+		{
+			name: "gone pinned target falls back to the name",
+			fav: config.Favorite{Provider: "azure", Target: "Prod-EastUS-2", Role: "Admin",
+				WorkspaceID: "999", RoleID: "r-1"},
+			wantID: "333",
+		},
+		{
+			name: "other organization does not match by ID",
+			fav: config.Favorite{Provider: "azure", Target: "Prod-EastUS-2", Role: "Admin",
+				WorkspaceID: "111", RoleID: "r-1", OrganizationID: "o-2"},
+			wantID: "333",
+		},
+		{
+			name: "drifted names are refreshed",
+			fav: config.Favorite{Provider: "azure", Target: "Prod-Old", Role: "Owner",
+				WorkspaceID: "333", RoleID: "r-1"},
+			wantID:     "333",
+			wantNotice: true,
+		},
+		{name: "missing", fav: config.Favorite{Provider: "azure", Target: "Dev", Role: "Admin"}, wantErr: `target "Dev" or role "Admin" not found`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgPath := writeConfigFile(t, "favorites: {}\n")
+			cfg := config.DefaultConfig()
+			cfg.Favorites["fav"] = tt.fav
+			if err := config.Save(cfg, cfgPath); err != nil {
+				t.Fatal(err)
+			}
+			var notices bytes.Buffer
+			saved := favoriteNotices
+			favoriteNotices = &notices
+			defer func() { favoriteNotices = saved }()
+
+			got, err := resolveFavoriteTarget("fav", tt.fav, targets)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveFavoriteTarget() = %v, want error containing %q", err, tt.wantErr)
+				}
+				if classifyError(err, true).code != codeTargetNotFound {
+					t.Errorf("error code = %s, want %s", classifyError(err, true).code, codeTargetNotFound)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveFavoriteTarget() error = %v", err)
+			}
+			if got.WorkspaceID != tt.wantID || got.CSP != models.CSPAzure {
+				t.Errorf("resolved %s/%s, want azure workspace %s", got.CSP, got.WorkspaceID, tt.wantID)
+			}
+
+			saved2, err := config.Load(cfgPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fav := saved2.Favorites["fav"]
+			if tt.wantNotice {
+				if fav.Target != "Prod-EastUS-2" || fav.Role != "Admin" {
+					t.Errorf("saved favorite = %+v, want its names refreshed", fav)
+				}
+				if !strings.Contains(notices.String(), `updated azure/Prod-Old/Owner to azure/Prod-EastUS-2/Admin`) {
+					t.Errorf("notice = %q", notices.String())
+				}
+			} else if notices.Len() > 0 || fav.Target != tt.fav.Target {
+				t.Errorf("favorite refreshed without drift: %+v, notice %q", fav, notices.String())
+			}
+		})
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesAdd_InteractivePinsIDs(t *testing.T) {
+	cfgPath := writeConfigFile(t, "favorites: {}\n")
+	target := models.EligibleTarget{WorkspaceID: "ws-1", OrganizationID: "org-1", WorkspaceName: "Prod",
+		RoleInfo: models.RoleInfo{ID: "role-1", Name: "Admin"}, CSP: models.CSPAWS}
+	eligLister := &mockEligibilityLister{response: &models.EligibilityResponse{Response: []models.EligibleTarget{target}}}
+	sel := &mockUnifiedSelector{item: &selectionItem{kind: selectionCloud, cloud: &target}}
+
+	if _, err := executeCommand(NewFavoritesCommandWithAllDeps(eligLister, sel, &mockNamePrompter{}, nil), "add", "prod", "--provider", "aws"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fav := cfg.Favorites["prod"]; fav.WorkspaceID != "ws-1" || fav.RoleID != "role-1" || fav.OrganizationID != "org-1" {
+		t.Errorf("favorite = %+v, want the selected target's IDs", fav)
+	}
+}
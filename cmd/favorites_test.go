@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -189,7 +190,7 @@ func TestFavoritesAddNonInteractive_VerboseLogs(t *testing.T) {
 	_ = config.Save(cfg, configPath)
 
 	rootCmd := newTestRootCommand()
-	rootCmd.AddCommand(NewFavoritesCommand())
+	rootCmd.AddCommand(NewFavoritesCommandWithAllDeps(targetsLister(eligibleTarget(models.CSPAzure, "sub-123", "Contributor")), nil, nil, nil))
 	_, err := executeCommand(rootCmd, "favorites", "add", "myfav", "--target", "sub-123", "--role", "Contributor")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -399,7 +400,11 @@ func TestFavoritesAddCommand(t *testing.T) {
 
 			// Execute command
 			rootCmd := newTestRootCommand()
-			favCmd := NewFavoritesCommand()
+			favCmd := NewFavoritesCommandWithAllDeps(targetsLister(
+				eligibleTarget(models.CSPAzure, "sub-123", "Contributor"),
+				eligibleTarget(models.CSPAzure, "sub-456", "Reader"),
+				eligibleTarget(models.CSPAWS, "account-789", "Admin"),
+			), nil, nil, nil)
 			rootCmd.AddCommand(favCmd)
 
 			cmdArgs := append([]string{"favorites", "add"}, tt.args...)
@@ -429,7 +434,7 @@ func TestFavoritesAddWithFlagsPersistence(t *testing.T) {
 
 	// Add favorite via flags
 	rootCmd := newTestRootCommand()
-	favCmd := NewFavoritesCommand()
+	favCmd := NewFavoritesCommandWithAllDeps(targetsLister(eligibleTarget(models.CSPAzure, "sub-999", "Owner")), nil, nil, nil)
 	rootCmd.AddCommand(favCmd)
 
 	_, err := executeCommand(rootCmd, "favorites", "add", "persist-test", "--target", "sub-999", "--role", "Owner")
@@ -457,6 +462,9 @@ func TestFavoritesAddWithFlagsPersistence(t *testing.T) {
 	if fav.Role != "Owner" {
 		t.Errorf("role = %q, want %q", fav.Role, "Owner")
 	}
+	if fav.WorkspaceID != "ws-sub-999" || fav.RoleID != "role-Owner" {
+		t.Errorf("pinned IDs = %q, %q; want the eligible target's", fav.WorkspaceID, fav.RoleID)
+	}
 }
 
 func TestFavoritesCommandIntegration(t *testing.T) {
@@ -495,7 +503,7 @@ func TestFavoritesParentCommand(t *testing.T) {
 	}
 
 	// Check subcommands exist
-	expectedSubcommands := []string{"add", "list", "remove"}
+	expectedSubcommands := []string{"add", "list", "edit", "rename", "remove", "check", "export", "import"}
 	commands := cmd.Commands()
 
 	if len(commands) != len(expectedSubcommands) {
@@ -729,17 +737,44 @@ func TestFavoritesAddInteractiveMode(t *testing.T) {
 			wantErr:     true,
 		},
 		{
-			name: "flags bypass eligibility",
+			name: "flags resolve against eligibility without selecting",
 			setupConfig: func(path string) {
 				cfg := config.DefaultConfig()
 				_ = config.Save(cfg, path)
 			},
-			eligLister:  nil, // nil — should not be called
+			eligLister:  targetsLister(eligibleTarget(models.CSPAzure, "sub-123", "Contributor")),
 			selector:    nil, // nil — should not be called
 			args:        []string{"myfav", "--target", "sub-123", "--role", "Contributor"},
 			wantContain: []string{"Added favorite", "myfav", "azure/sub-123/Contributor"},
 			wantErr:     false,
 		},
+		{
+			name: "flags naming several eligible targets",
+			setupConfig: func(path string) {
+				cfg := config.DefaultConfig()
+				_ = config.Save(cfg, path)
+			},
+			eligLister: targetsLister(
+				eligibleTarget(models.CSPAzure, "sub-123", "Contributor"),
+				models.EligibleTarget{CSP: models.CSPAzure, WorkspaceID: "ws-other", WorkspaceName: "sub-123", RoleInfo: models.RoleInfo{ID: "role-other", Name: "Contributor"}},
+			),
+			selector:    nil, // nil — should not be called
+			args:        []string{"myfav", "--target", "sub-123", "--role", "Contributor"},
+			wantContain: []string{"2 eligible targets match", "ws-other"},
+			wantErr:     true,
+		},
+		{
+			name: "flags naming a target that is not eligible",
+			setupConfig: func(path string) {
+				cfg := config.DefaultConfig()
+				_ = config.Save(cfg, path)
+			},
+			eligLister:  targetsLister(eligibleTarget(models.CSPAzure, "sub-123", "Reader")),
+			selector:    nil, // nil — should not be called
+			args:        []string{"myfav", "--target", "sub-123", "--role", "Contributor"},
+			wantContain: []string{`not eligible for role "Contributor" on azure target "sub-123"`},
+			wantErr:     true,
+		},
 		{
 			name: "no name - interactive prompts for name after selection",
 			setupConfig: func(path string) {
@@ -1352,3 +1387,66 @@ func TestFavoritesAdd_NonInteractiveGuard(t *testing.T) {
 		})
 	}
 }
+
+// Not parallel: sets GRANT_CONFIG and GRANT_TEAM_CONFIG for the process.
+func TestFavorites_TeamLayerIsReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := writeConfigFile(t, "favorites:\n  mine:\n    provider: aws\n    target: Dev\n    role: Admin\n")
+	teamPath := filepath.Join(dir, "team.yaml")
+	team := "favorites:\n  team-prod:\n    provider: aws\n    target: Prod\n    role: ReadOnly\n"
+	if err := os.WriteFile(teamPath, []byte(team), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GRANT_TEAM_CONFIG", teamPath)
+
+	output, err := executeCommand(NewFavoritesCommand(), "list")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(output, "team-prod: aws/Prod/ReadOnly [team, read-only]") || !strings.Contains(output, "mine: aws/Dev/Admin\n") {
+		t.Errorf("list output:\n%s", output)
+	}
+
+	_, err = executeCommand(NewFavoritesCommand(), "remove", "team-prod")
+	if err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("remove team favorite = %v, want a read-only error", err)
+	}
+
+	if _, err := executeCommand(NewFavoritesCommandWithAllDeps(targetsLister(eligibleTarget(models.CSPAzure, "T", "R")), nil, nil, nil),
+		"add", "new", "--target", "T", "--role", "R"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	user, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := user.Favorites["team-prod"]; ok {
+		t.Error("team favorite copied into the user config")
+	}
+	if _, ok := user.Favorites["new"]; !ok {
+		t.Errorf("user favorites = %v, want the new favorite", user.Favorites)
+	}
+	data, err := os.ReadFile(teamPath)
+	if err != nil || string(data) != team {
+		t.Errorf("team config changed: %q, %v", data, err)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG and GRANT_FAVORITE_* for the process.
+func TestFavorites_EnvFavoriteIsReadOnly(t *testing.T) {
+	writeConfigFile(t, "favorites: {}\n")
+	t.Setenv("GRANT_FAVORITE_CI_DEPLOY", "aws/Prod/Deployer")
+
+	output, err := executeCommand(NewFavoritesCommand(), "list")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(output, "ci-deploy: aws/Prod/Deployer [env, read-only]") {
+		t.Errorf("list output:\n%s", output)
+	}
+
+	_, err = executeCommand(NewFavoritesCommand(), "remove", "ci-deploy")
+	if err == nil || !strings.Contains(err.Error(), "comes from env GRANT_FAVORITE_CI_DEPLOY") {
+		t.Errorf("remove env favorite = %v, want an error naming the variable", err)
+	}
+}
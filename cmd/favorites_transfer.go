@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Export formats of grant favorites export.
+const (
+	exportFormatYAML = "yaml"
+	exportFormatJSON = "json"
+)
+
+func newFavoritesExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [names...]",
+		Short: "Export favorites for sharing",
+		Long: `Print favorites as a file that 'grant favorites import' reads: the version
+and favorites: block of a config file. Without names, every favorite of the
+active profile is exported. The profile a favorite belongs to is left out, so
+the importer's active profile applies.`,
+		Example: `  # Share every favorite
+  grant favorites export > team-favorites.yaml
+
+  # Share two, as JSON
+  grant favorites export prod-admin dev-reader --format json`,
+		RunE: runFavoritesExport,
+	}
+	cmd.Flags().String("format", exportFormatYAML, "Export format: yaml, json")
+	return cmd
+}
+
+func runFavoritesExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if format != exportFormatYAML && format != exportFormatJSON {
+		return withCode(codeUsage, fmt.Errorf("invalid --format %q: must be one of: yaml, json", format))
+	}
+
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err != nil {
+		return err
+	}
+	profile := profileOf(cfg)
+	favorites := make(map[string]config.Favorite)
+	if len(args) == 0 {
+		for _, entry := range config.ListFavorites(cfg) {
+			if entry.AvailableIn(profile) {
+				favorites[entry.Name] = entry.Favorite
+			}
+		}
+	}
+	for _, name := range args {
+		fav, err := config.GetFavorite(cfg, name)
+		if err != nil {
+			return withCode(codeTargetNotFound, fmt.Errorf("favorite %q not found, run 'grant favorites list'", name))
+		}
+		favorites[name] = fav
+	}
+	for name, fav := range favorites {
+		fav.Profile = ""
+		favorites[name] = fav
+	}
+
+	data, err := config.MarshalFavorites(favorites)
+	if err != nil {
+		return fmt.Errorf("failed to export favorites: %w", err)
+	}
+	if format == exportFormatJSON {
+		// Re-encode the YAML document so both formats use the config file's
+		// field names, and either imports the same way.
+		var doc map[string]any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to export favorites: %w", err)
+		}
+		if data, err = json.MarshalIndent(doc, "", "  "); err != nil {
+			return fmt.Errorf("failed to export favorites: %w", err)
+		}
+		data = append(data, '\n')
+	}
+	_, err = cmd.OutOrStdout().Write(data)
+	return err
+}
+
+// newFavoritesImportCommand creates the import command with a custom RunE
+// function, so tests can inject the eligibility listers --verify uses.
+func newFavoritesImportCommand(runFn func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file|->",
+		Short: "Import favorites from a file",
+		Long: `Add the favorites of an export or config file, YAML or JSON, to the user
+config. "-" reads standard input.
+
+A favorite whose name already exists is an error, and nothing is imported,
+unless --merge keeps the existing favorite or --overwrite replaces it.
+--prefix adds a prefix to every imported name, e.g. to keep a team's
+favorites apart from your own. --verify first checks each favorite against
+your live eligibility and imports nothing if one is not eligible.`,
+		Example: `  # Import the team's favorites, keeping any you already have
+  grant favorites import ~/src/team/favorites.yaml --merge
+
+  # Import them under a team- prefix, checking eligibility first
+  grant favorites import favorites.yaml --prefix team- --verify
+
+  # From a colleague's export
+  ssh host grant favorites export | grant favorites import -`,
+		Args: cobra.ExactArgs(1),
+		RunE: runFn,
+	}
+	cmd.Flags().Bool("merge", false, "Keep existing favorites with the same name")
+	cmd.Flags().Bool("overwrite", false, "Replace existing favorites with the same name")
+	cmd.Flags().String("prefix", "", "Prefix added to every imported favorite name")
+	cmd.Flags().Bool("verify", false, "Check each favorite against live eligibility before saving")
+	cmd.MarkFlagsMutuallyExclusive("merge", "overwrite")
+	return cmd
+}
+
+// runFavoritesImportProduction authenticates only for --verify.
+func runFavoritesImportProduction(cmd *cobra.Command, args []string) error {
+	if verify, _ := cmd.Flags().GetBool("verify"); !verify {
+		return runFavoritesImport(cmd, args, nil, nil)
+	}
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err != nil {
+		return err
+	}
+	_, scaService, _, err := bootstrapSCAService()
+	if err != nil {
+		return err
+	}
+	cachedLister, err := buildCachedLister(cfg, false, scaService, scaService)
+	if err != nil {
+		return err
+	}
+	return runFavoritesImport(cmd, args, cachedLister, cachedLister)
+}
+
+func runFavoritesImport(cmd *cobra.Command, args []string, eligLister eligibilityLister, groupsElig groupsEligibilityLister) error {
+	merge, _ := cmd.Flags().GetBool("merge")
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+	prefix, _ := cmd.Flags().GetString("prefix")
+	verify, _ := cmd.Flags().GetBool("verify")
+
+	source := args[0]
+	var data []byte
+	var err error
+	if source == "-" {
+		source = "standard input"
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read favorites: %w", err)
+	}
+	imported, err := config.ParseFavorites(data)
+	if err != nil {
+		return fmt.Errorf("failed to read favorites from %s: %w", source, err)
+	}
+	if len(imported) == 0 {
+		return fmt.Errorf("no favorites in %s", source)
+	}
+
+	incoming := make(map[string]config.Favorite, len(imported))
+	for name, fav := range imported {
+		fav.Profile = ""
+		incoming[prefix+name] = fav
+	}
+	if err := config.Validate(&config.Config{Favorites: incoming}); err != nil {
+		return fmt.Errorf("invalid favorites in %s:\n%w", source, err)
+	}
+
+	layers, err := config.LoadLayers()
+	if err != nil {
+		return err
+	}
+	var add, replace, skip, conflicts []string
+	for _, entry := range config.ListFavorites(&config.Config{Favorites: incoming}) {
+		name := entry.Name
+		if _, exists := layers.Config.Favorites[name]; !exists {
+			add = append(add, name)
+			continue
+		}
+		switch {
+		case merge:
+			skip = append(skip, name)
+		case overwrite:
+			if src, _ := layers.FavoriteSource(name); src.Name != config.LayerUser {
+				return fmt.Errorf("favorite %q comes from %s and is read-only; import it with --prefix instead", name, src.Origin("favorites."+name))
+			}
+			replace = append(replace, name)
+		default:
+			conflicts = append(conflicts, name)
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("favorites already exist: %s; use --merge to keep them, --overwrite to replace them or --prefix to rename the imported ones", strings.Join(conflicts, ", "))
+	}
+
+	if verify {
+		var entries []config.FavoriteEntry
+		for _, name := range slices.Concat(add, replace) {
+			entries = append(entries, config.FavoriteEntry{Name: name, Favorite: incoming[name]})
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
+		defer cancel()
+		ineligible, err := findIneligibleFavorites(ctx, entries, eligLister, groupsElig)
+		if err != nil {
+			return err
+		}
+		if len(ineligible) > 0 {
+			var errs []error
+			for _, entry := range entries {
+				if reason, ok := ineligible[entry.Name]; ok {
+					errs = append(errs, fmt.Errorf("favorite %q: %s", entry.Name, reason))
+				}
+			}
+			return withCode(codeNotEligible, fmt.Errorf("nothing imported, not eligible:\n%w", errors.Join(errs...)))
+		}
+	}
+
+	cfg, cfgPath, err := config.LoadUserWithPath()
+	if err != nil {
+		return err
+	}
+	profile := profileOf(layers.Config)
+	for _, name := range slices.Concat(add, replace) {
+		fav := incoming[name]
+		fav.Profile = profile
+		cfg.Favorites[name] = fav
+	}
+	if len(add)+len(replace) > 0 {
+		log.Info("Saving config...")
+		if err := config.Save(cfg, cfgPath); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	for _, name := range add {
+		fmt.Fprintf(out, "Imported %q: %s\n", name, incoming[name].Describe())
+	}
+	for _, name := range replace {
+		fmt.Fprintf(out, "Replaced %q: %s\n", name, incoming[name].Describe())
+	}
+	for _, name := range skip {
+		fmt.Fprintf(out, "Skipped %q: already exists\n", name)
+	}
+	return nil
+}
+
+// findIneligibleFavorites checks favorites against live eligibility and
+// returns, by name, why each one that matches no eligible target or group
+// does not. An ambiguous favorite is still eligible.
+func findIneligibleFavorites(ctx context.Context, entries []config.FavoriteEntry, eligLister eligibilityLister, groupsElig groupsEligibilityLister) (map[string]string, error) {
+	checks, err := checkFavorites(ctx, entries, eligLister, groupsElig)
+	if err != nil {
+		return nil, err
+	}
+	ineligible := make(map[string]string)
+	for _, c := range checks {
+		if c.status == favoriteCheckMissing {
+			ineligible[c.entry.Name] = c.reason
+		}
+	}
+	return ineligible, nil
+}
@@ -0,0 +1,185 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+const transferConfig = "favorites:\n" +
+	"  prod:\n    type: cloud\n    provider: aws\n    target: Prod\n    role: Admin\n    profile: grant\n" +
+	"  ops:\n    type: groups\n    provider: azure\n    group: Ops\n"
+
+// writeImportFile writes content to a file for grant favorites import.
+func writeImportFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "favorites.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesExport(t *testing.T) {
+	writeConfigFile(t, transferConfig)
+
+	output, err := executeCommand(NewFavoritesCommand(), "export")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	favs, err := config.ParseFavorites([]byte(output))
+	if err != nil {
+		t.Fatalf("export is not importable: %v\n%s", err, output)
+	}
+	if len(favs) != 2 || favs["prod"].Role != "Admin" || favs["ops"].Group != "Ops" {
+		t.Errorf("exported favorites = %+v", favs)
+	}
+	if favs["prod"].Profile != "" {
+		t.Errorf("exported profile = %q, want it left out", favs["prod"].Profile)
+	}
+
+	output, err = executeCommand(NewFavoritesCommand(), "export", "ops", "--format", "json")
+	if err != nil {
+		t.Fatalf("export --format json: %v", err)
+	}
+	var doc struct {
+		Version   int                        `json:"version"`
+		Favorites map[string]json.RawMessage `json:"favorites"`
+	}
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+	if doc.Version != config.CurrentVersion || len(doc.Favorites) != 1 {
+		t.Errorf("json export = %s", output)
+	}
+
+	if _, err := executeCommand(NewFavoritesCommand(), "export", "missing"); err == nil {
+		t.Error("export of an unknown favorite succeeded")
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesImport_ConflictStrategies(t *testing.T) {
+	incoming := writeImportFile(t, "favorites:\n"+
+		"  prod:\n    provider: aws\n    target: Prod\n    role: ReadOnly\n"+
+		"  dev:\n    provider: gcp\n    target: dev-project\n    role: Viewer\n")
+
+	tests := []struct {
+		name      string
+		args      []string
+		wantErr   string
+		wantNames []string
+		wantRole  string // role of prod after the import
+	}{
+		{name: "conflict is an error", wantErr: "favorites already exist: prod", wantNames: []string{"ops", "prod"}, wantRole: "Admin"},
+		{name: "merge keeps existing", args: []string{"--merge"}, wantNames: []string{"dev", "ops", "prod"}, wantRole: "Admin"},
+		{name: "overwrite replaces", args: []string{"--overwrite"}, wantNames: []string{"dev", "ops", "prod"}, wantRole: "ReadOnly"},
+		{name: "prefix renames", args: []string{"--prefix", "team-"}, wantNames: []string{"ops", "prod", "team-dev", "team-prod"}, wantRole: "Admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgPath := writeConfigFile(t, transferConfig)
+
+			_, err := executeCommand(NewFavoritesCommand(), append([]string{"import", incoming}, tt.args...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("import = %v, want error containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("import: %v", err)
+			}
+
+			cfg, err := config.Load(cfgPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, entry := range config.ListFavorites(cfg) {
+				names = append(names, entry.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantNames, ",") {
+				t.Errorf("favorites = %v, want %v", names, tt.wantNames)
+			}
+			if got := cfg.Favorites["prod"].Role; got != tt.wantRole {
+				t.Errorf("prod role = %q, want %q", got, tt.wantRole)
+			}
+		})
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesImport_StdinAndValidation(t *testing.T) {
+	cfgPath := writeConfigFile(t, "favorites: {}\n")
+
+	cmd := NewFavoritesCommand()
+	cmd.SetIn(strings.NewReader(`{"favorites": {"dev": {"provider": "gcp", "target": "dev-project", "role": "Viewer"}}}`))
+	output, err := executeCommand(cmd, "import", "-")
+	if err != nil {
+		t.Fatalf("import -: %v", err)
+	}
+	if !strings.Contains(output, `Imported "dev": gcp/dev-project/Viewer`) {
+		t.Errorf("output = %q", output)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fav := cfg.Favorites["dev"]; fav.Profile != config.DefaultProfile || fav.Type != config.FavoriteTypeCloud {
+		t.Errorf("dev = %+v, want it tagged with the active profile", fav)
+	}
+
+	bad := writeImportFile(t, "favorites:\n  x:\n    provider: oci\n    target: T\n    role: R\n")
+	_, err = executeCommand(NewFavoritesCommand(), "import", bad)
+	if err == nil || !strings.Contains(err.Error(), `favorite "x": invalid provider "oci"`) {
+		t.Errorf("import of an invalid favorite = %v", err)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFavoritesImport_Verify(t *testing.T) {
+	cfgPath := writeConfigFile(t, "favorites: {}\n")
+	incoming := writeImportFile(t, "favorites:\n"+
+		"  prod:\n    provider: aws\n    target: Prod\n    role: Admin\n"+
+		"  ops:\n    type: groups\n    provider: azure\n    group: Ops\n")
+
+	eligLister := &mockEligibilityLister{listFunc: func(_ context.Context, csp models.CSP) (*models.EligibilityResponse, error) {
+		if csp != models.CSPAWS {
+			return &models.EligibilityResponse{}, nil
+		}
+		return &models.EligibilityResponse{Response: []models.EligibleTarget{
+			{WorkspaceName: "Prod", RoleInfo: models.RoleInfo{Name: "Admin"}},
+		}}, nil
+	}}
+	groupsElig := &mockGroupsEligibilityLister{response: &models.GroupsEligibilityResponse{
+		Response: []models.GroupsEligibleTarget{{GroupName: "Dev"}},
+	}}
+
+	_, err := executeCommand(NewFavoritesCommandWithAllDeps(eligLister, nil, nil, groupsElig), "import", incoming, "--verify")
+	if err == nil || !strings.Contains(err.Error(), `favorite "ops": no eligible group "Ops"`) {
+		t.Fatalf("import --verify = %v, want ops reported ineligible", err)
+	}
+	if strings.Contains(err.Error(), `"prod"`) {
+		t.Errorf("prod reported ineligible: %v", err)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Favorites) != 0 {
+		t.Errorf("favorites = %v, want nothing imported", cfg.Favorites)
+	}
+
+	groupsElig.response.Response = append(groupsElig.response.Response, models.GroupsEligibleTarget{GroupName: "Ops"})
+	if _, err := executeCommand(NewFavoritesCommandWithAllDeps(eligLister, nil, nil, groupsElig), "import", incoming, "--verify"); err != nil {
+		t.Fatalf("import --verify: %v", err)
+	}
+}
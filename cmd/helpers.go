@@ -16,16 +16,18 @@ type statusData struct {
 	nameMap      map[string]string
 	groupNameMap map[string]string        // groupID -> groupName
 	remainingMap map[string]time.Duration // sessionID -> remaining time
+	upperBounds  map[string]bool          // sessionID -> remaining time is an upper bound
 }
 
-// fetchStatusData fires sessions and all-CSP eligibility calls concurrently,
-// then joins results. A sessions error is fatal; eligibility errors are
+// fetchStatusData fires sessions and eligibility calls for each CSP in scope
+// (or only cspFilter) concurrently, then joins results. A sessions error is fatal; eligibility errors are
 // gracefully degraded (empty nameMap entry, verbose warning via SDK logger).
 func fetchStatusData(
 	ctx context.Context,
 	sessionLister sessionLister,
 	eligLister eligibilityLister,
 	cspFilter *scamodels.CSP,
+	scope []scamodels.CSP,
 ) (*statusData, error) {
 	type eligResult struct {
 		csp     scamodels.CSP
@@ -47,7 +49,7 @@ func fetchStatusData(
 	}()
 
 	// Determine which CSPs to query for eligibility
-	cspsToQuery := supportedCSPs
+	cspsToQuery := scope
 	if cspFilter != nil {
 		cspsToQuery = []scamodels.CSP{*cspFilter}
 	}
@@ -210,9 +212,17 @@ func buildGroupNameMap(ctx context.Context, gl groupsEligibilityLister) map[stri
 	return nameMap
 }
 
-// findMatchingGroup finds a group by name (case-insensitive).
-// If directoryID is non-empty, only matches groups in that directory.
-func findMatchingGroup(groups []scamodels.GroupsEligibleTarget, name, directoryID string) *scamodels.GroupsEligibleTarget {
+// findMatchingGroup finds a group by its groupID when one is given and
+// eligible, else by name (case-insensitive); either way within directoryID
+// when one is given.
+func findMatchingGroup(groups []scamodels.GroupsEligibleTarget, name, directoryID, groupID string) *scamodels.GroupsEligibleTarget {
+	if groupID != "" {
+		for i := range groups {
+			if groups[i].GroupID == groupID && (directoryID == "" || groups[i].DirectoryID == directoryID) {
+				return &groups[i]
+			}
+		}
+	}
 	for i := range groups {
 		if strings.EqualFold(groups[i].GroupName, name) {
 			if directoryID != "" && groups[i].DirectoryID != directoryID {
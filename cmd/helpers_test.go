@@ -204,7 +204,7 @@ func TestFetchStatusData(t *testing.T) {
 			sessionLister := tt.setupSessions()
 			eligLister := tt.setupEligibility()
 
-			data, err := fetchStatusData(ctx, sessionLister, eligLister, tt.cspFilter)
+			data, err := fetchStatusData(ctx, sessionLister, eligLister, tt.cspFilter, supportedCSPs)
 
 			if tt.wantErr {
 				if err == nil {
@@ -251,7 +251,7 @@ func TestFetchStatusData_VerboseWarning(t *testing.T) {
 		listErr: errors.New("eligibility API unavailable"),
 	}
 
-	data, err := fetchStatusData(ctx, sessionLister, eligLister, nil)
+	data, err := fetchStatusData(ctx, sessionLister, eligLister, nil, supportedCSPs)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -3,6 +3,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -218,8 +219,8 @@ func TestIntegration_Version(t *testing.T) {
 func TestIntegration_ElevateWithoutLogin(t *testing.T) {
 	got := runGrant(t, isolatedEnv(t), "--provider", "azure")
 
-	if got.exitCode != 1 {
-		t.Fatalf("exit code = %d, want 1\noutput:\n%s", got.exitCode, got.output)
+	if got.exitCode != exitNotAuthenticated {
+		t.Fatalf("exit code = %d, want %d\noutput:\n%s", got.exitCode, exitNotAuthenticated, got.output)
 	}
 	// Exact text, not a keyword soup. With an empty sandbox profile directory
 	// the SDK authenticator refuses before any network call, and the non-
@@ -237,8 +238,8 @@ func TestIntegration_ElevateWithoutLogin(t *testing.T) {
 func TestIntegration_StatusWithoutLogin(t *testing.T) {
 	got := runGrant(t, isolatedEnv(t), "status")
 
-	if got.exitCode != 1 {
-		t.Fatalf("exit code = %d, want 1\noutput:\n%s", got.exitCode, got.output)
+	if got.exitCode != exitNotAuthenticated {
+		t.Fatalf("exit code = %d, want %d\noutput:\n%s", got.exitCode, exitNotAuthenticated, got.output)
 	}
 	if want := "authentication failed: either a profile or a specific auth profile must be supplied"; !got.contains(want) {
 		t.Errorf("output missing %q, got:\n%s", want, got.output)
@@ -261,10 +262,12 @@ func TestIntegration_FavoritesList(t *testing.T) {
 	}
 }
 
+// TestIntegration_FavoritesAddWithFlags adds a group favorite: one added with
+// --target and --role is resolved against eligibility, which needs a login.
 func TestIntegration_FavoritesAddWithFlags(t *testing.T) {
 	env := isolatedEnv(t)
 
-	added := runGrant(t, env, "favorites", "add", "test-fav", "--target", "sub-123", "--role", "Contributor")
+	added := runGrant(t, env, "favorites", "add", "test-fav", "--type", "groups", "--group", "IT Admins")
 	if added.exitCode != 0 {
 		t.Fatalf("favorites add exit code = %d, want 0\noutput:\n%s", added.exitCode, added.output)
 	}
@@ -276,7 +279,7 @@ func TestIntegration_FavoritesAddWithFlags(t *testing.T) {
 	if listed.exitCode != 0 {
 		t.Fatalf("favorites list exit code = %d, want 0\noutput:\n%s", listed.exitCode, listed.output)
 	}
-	for _, want := range []string{"test-fav", "azure/sub-123/Contributor"} {
+	for _, want := range []string{"test-fav", "groups/IT Admins"} {
 		if !listed.contains(want) {
 			t.Errorf("favorites list missing %q, got:\n%s", want, listed.output)
 		}
@@ -306,14 +309,35 @@ func TestIntegration_FavoritesAddWithoutTTYFailsBeforeAuth(t *testing.T) {
 func TestIntegration_InvalidCommand(t *testing.T) {
 	got := runGrant(t, isolatedEnv(t), "nonexistent-command")
 
-	if got.exitCode != 1 {
-		t.Fatalf("exit code = %d, want 1\noutput:\n%s", got.exitCode, got.output)
+	if got.exitCode != exitUsage {
+		t.Fatalf("exit code = %d, want %d\noutput:\n%s", got.exitCode, exitUsage, got.output)
 	}
 	if want := `unknown command "nonexistent-command" for "grant"`; !got.contains(want) {
 		t.Errorf("output missing %q, got:\n%s", want, got.output)
 	}
 }
 
+// TestIntegration_ErrorEnvelope pins the structured error output end to end:
+// with --output json a failure is a JSON envelope with a stable code and the
+// matching exit code, not free-form text and a hint.
+func TestIntegration_ErrorEnvelope(t *testing.T) {
+	got := runGrant(t, isolatedEnv(t), "--provider", "azure", "--output", "json")
+
+	if got.exitCode != exitNotAuthenticated {
+		t.Fatalf("exit code = %d, want %d\noutput:\n%s", got.exitCode, exitNotAuthenticated, got.output)
+	}
+	var envelope errorOutput
+	if err := json.Unmarshal([]byte(got.output), &envelope); err != nil {
+		t.Fatalf("output is not a JSON error envelope: %v\n%s", err, got.output)
+	}
+	if envelope.Error.Code != string(codeNotAuthenticated) || envelope.Error.ExitCode != exitNotAuthenticated {
+		t.Errorf("envelope = %+v, want code %s and exit code %d", envelope.Error, codeNotAuthenticated, exitNotAuthenticated)
+	}
+	if got.contains("Hint:") {
+		t.Errorf("structured output must not carry the text hint:\n%s", got.output)
+	}
+}
+
 // TestIntegration_VerboseHint pins the hint's CALL SITE in Execute(), not just
 // the shouldShowVerboseHint predicate.
 //
@@ -328,30 +352,35 @@ func TestIntegration_VerboseHint(t *testing.T) {
 	tests := []struct {
 		name     string
 		args     []string
+		wantExit int
 		wantHint bool
 		why      string
 	}{
 		{
 			name:     "runtime error prints the hint",
 			args:     []string{"--provider", "azure"},
+			wantExit: exitNotAuthenticated,
 			wantHint: true,
 			why:      "PersistentPreRunE ran, so --verbose would have added detail",
 		},
 		{
 			name:     "argument validation error does not",
 			args:     []string{"nonexistent-command"},
+			wantExit: exitUsage,
 			wantHint: false,
 			why:      "PersistentPreRunE never ran, so --verbose would add nothing",
 		},
 		{
 			name:     "unknown flag does not",
 			args:     []string{"--no-such-flag"},
+			wantExit: exitUsage,
 			wantHint: false,
 			why:      "flag parsing fails before PersistentPreRunE",
 		},
 		{
 			name:     "already verbose does not",
 			args:     []string{"--verbose", "--provider", "azure"},
+			wantExit: exitNotAuthenticated,
 			wantHint: false,
 			why:      "the hint tells the user to do what they already did",
 		},
@@ -360,8 +389,8 @@ func TestIntegration_VerboseHint(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := runGrant(t, isolatedEnv(t), tt.args...)
-			if got.exitCode != 1 {
-				t.Fatalf("exit code = %d, want 1\noutput:\n%s", got.exitCode, got.output)
+			if got.exitCode != tt.wantExit {
+				t.Fatalf("exit code = %d, want %d\noutput:\n%s", got.exitCode, tt.wantExit, got.output)
 			}
 			if hasHint := got.contains(hint); hasHint != tt.wantHint {
 				t.Errorf("hint present = %v, want %v (%s)\noutput:\n%s",
@@ -66,7 +66,7 @@ Examples:
 		RunE:          runFn,
 	}
 
-	cmd.Flags().StringP("provider", "p", "", "Cloud provider: azure, aws, gcp (omit to show all)")
+	cmd.Flags().StringP("provider", "p", "", "Cloud provider: azure, aws, gcp, or all (default: the providers setting, else all)")
 	cmd.Flags().Bool("groups", false, "Show only Entra ID groups")
 	cmd.Flags().Bool("refresh", false, "Bypass eligibility cache and fetch fresh data")
 
@@ -114,7 +114,7 @@ func runList(
 	// Check authentication
 	_, err := auth.LoadAuthentication(nil, true)
 	if err != nil {
-		return fmt.Errorf("not authenticated, run 'grant login' first: %w", err)
+		return notAuthenticatedError(err)
 	}
 
 	provider, _ := cmd.Flags().GetString("provider")
@@ -134,8 +134,9 @@ func runList(
 		}
 	}
 
-	// Fetch groups (unless --provider is set)
-	if provider == "" {
+	// Fetch groups (unless --provider is set or the providers queried leave
+	// out azure)
+	if !isProviderFilter(provider) && groupsInScope(provider) {
 		groups, err = fetchGroupsEligibility(ctx, groupsElig, eligLister)
 		if err != nil {
 			log.Info("groups eligibility fetch failed: %v", err)
@@ -143,10 +144,10 @@ func runList(
 	}
 
 	if len(cloudTargets) == 0 && len(groups) == 0 {
-		return errors.New("no eligible targets or groups found, check your SCA policies")
+		return withCode(codeNotEligible, errors.New("no eligible targets or groups found, check your SCA policies"))
 	}
 
-	if isJSONOutput() {
+	if isStructuredOutput() {
 		return writeListJSON(cmd, cloudTargets, groups)
 	}
 
@@ -181,7 +182,7 @@ func writeListJSON(cmd *cobra.Command, cloudTargets []models.EligibleTarget, gro
 		})
 	}
 
-	return writeJSON(cmd.OutOrStdout(), out)
+	return writeOutput(cmd.OutOrStdout(), out)
 }
 
 // writeListText outputs the list as formatted text.
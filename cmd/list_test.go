@@ -321,6 +321,29 @@ func TestListCommand_ProviderSuppressesGroups(t *testing.T) {
 	}
 }
 
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestListCommand_GroupsSkippedWithoutAzure(t *testing.T) {
+	writeConfigFile(t, "providers: [aws]\n")
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+	eligLister := &mockEligibilityLister{response: &models.EligibilityResponse{
+		Response: []models.EligibleTarget{{WorkspaceID: "ws-id", WorkspaceName: "ws-name",
+			RoleInfo: models.RoleInfo{ID: "role-id", Name: "role-name"}}},
+		Total: 1,
+	}}
+	groupsElig := &mockGroupsEligibilityLister{
+		listFunc: func(_ context.Context, _ models.CSP) (*models.GroupsEligibilityResponse, error) {
+			t.Error("groups eligibility must not be fetched when the providers queried leave out azure")
+			return &models.GroupsEligibilityResponse{}, nil
+		},
+	}
+
+	root := newTestRootCommand()
+	root.AddCommand(NewListCommandWithDeps(auth, eligLister, groupsElig))
+	if _, err := executeCommand(root, "list"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 // TestListCommand_RefreshFlagRegistered pins the --refresh flag on `grant list`.
 // The flag is not a no-op: NewListCommand reads it and passes it into
 // buildCachedLister. That wiring runs behind bootstrapSCAService, which unit
@@ -42,8 +42,9 @@ func NewLoginCommandWithAuth(auth authenticator) *cobra.Command {
 func runLogin(cmd *cobra.Command, auth authenticator) error {
 	// Load the SDK profile
 	log.Info("Loading profile...")
+	name := activeProfile()
 	loader := profiles.DefaultProfilesLoader()
-	profile, err := (*loader).LoadProfile("grant")
+	profile, err := (*loader).LoadProfile(name)
 	if err != nil {
 		return fmt.Errorf("failed to load profile: %w", err)
 	}
@@ -59,7 +60,7 @@ func runLogin(cmd *cobra.Command, auth authenticator) error {
 		}
 
 		// Reload profile after configuration
-		profile, err = (*loader).LoadProfile("grant")
+		profile, err = (*loader).LoadProfile(name)
 		if err != nil {
 			return fmt.Errorf("failed to load profile after configuration: %w", err)
 		}
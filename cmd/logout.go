@@ -9,18 +9,7 @@ import (
 
 // NewLogoutCommand creates the logout command
 func NewLogoutCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "logout",
-		Short: "Log out and clear cached authentication tokens",
-		Long:  "Log out of grant by clearing cached authentication tokens from the system keyring.",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			kr, err := keyring.NewIdsecKeyring("grant").GetKeyring(true)
-			if err != nil {
-				return fmt.Errorf("failed to access keyring: %w", err)
-			}
-			return runLogout(cmd, kr)
-		},
-	}
+	return NewLogoutCommandWithDeps(sdkKeyringClearer{})
 }
 
 // NewLogoutCommandWithDeps creates a logout command with injected dependencies for testing
@@ -28,7 +17,7 @@ func NewLogoutCommandWithDeps(clearer keyringClearer) *cobra.Command {
 	return &cobra.Command{
 		Use:   "logout",
 		Short: "Log out and clear cached authentication tokens",
-		Long:  "Log out of grant by clearing cached authentication tokens from the system keyring.",
+		Long:  "Log out of grant by clearing cached authentication tokens from the system keyring\nand deleting the cached eligibility and session state of the logged-out identity.\n\nTokens of every profile share the keyring and are all cleared; cached state is\ndeleted for the active profile (--profile) only.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runLogout(cmd, clearer)
 		},
@@ -36,12 +25,36 @@ func NewLogoutCommandWithDeps(clearer keyringClearer) *cobra.Command {
 }
 
 func runLogout(cmd *cobra.Command, clearer keyringClearer) error {
+	if err := clearTokens(clearer); err != nil {
+		return err
+	}
+
+	// Eligibility and session state belong to the identity just logged out.
+	invalidateCache(loadCacheNamespace())
+
+	fmt.Fprintln(cmd.OutOrStdout(), "Logged out successfully")
+	return nil
+}
+
+// clearTokens clears the login tokens from the keyring. The SDK keeps the
+// tokens of every profile in one keyring, under entry names of its own, and
+// only clears them all, so every profile is logged out.
+func clearTokens(clearer keyringClearer) error {
 	log.Info("Clearing keyring...")
 	if err := clearer.ClearAllPasswords(); err != nil {
 		return fmt.Errorf("failed to clear authentication: %w", err)
 	}
-
 	log.Info("Keyring cleared")
-	fmt.Fprintln(cmd.OutOrStdout(), "Logged out successfully")
 	return nil
 }
+
+// sdkKeyringClearer clears the SDK keyring, which it opens only when asked.
+type sdkKeyringClearer struct{}
+
+func (sdkKeyringClearer) ClearAllPasswords() error {
+	kr, err := keyring.NewIdsecKeyring("grant").GetKeyring(true)
+	if err != nil {
+		return fmt.Errorf("failed to access keyring: %w", err)
+	}
+	return kr.ClearAllPasswords()
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"strings"
 	"testing"
+
+	"github.com/cyberark/idsec-sdk-golang/pkg/common/keyring"
 )
 
 func TestLogoutCommand(t *testing.T) {
@@ -83,6 +85,34 @@ func TestLogoutCommand_VerboseLogs(t *testing.T) {
 	}
 }
 
+// TestLogoutCommand_ClearsSDKKeyring runs the production command against the
+// SDK's own keyring, in the sandbox's file keyring: whatever entry names the
+// SDK gives tokens, none of them survives logout.
+//
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestLogoutCommand_ClearsSDKKeyring(t *testing.T) {
+	writeProfileConfig(t, "acme")
+	kr, err := keyring.NewIdsecKeyring("grant").GetKeyring(true)
+	if err != nil {
+		t.Fatalf("GetKeyring() = %v", err)
+	}
+	entries := [][2]string{{"grant-acme", "me@acme.com"}, {"grant-globex", "globex_isp"}, {"idsec", "acme"}}
+	for _, e := range entries {
+		if err := kr.SetPassword(e[0], e[1], "token"); err != nil {
+			t.Fatalf("SetPassword(%s, %s) = %v", e[0], e[1], err)
+		}
+	}
+
+	if _, err := executeCommand(NewLogoutCommand()); err != nil {
+		t.Fatalf("logout error = %v", err)
+	}
+	for _, e := range entries {
+		if token, err := kr.GetPassword(e[0], e[1]); err == nil && token != "" {
+			t.Errorf("token %s/%s survived logout", e[0], e[1])
+		}
+	}
+}
+
 func TestLogoutCommandIntegration(t *testing.T) {
 	// Test that logout command is properly registered
 	rootCmd := newTestRootCommand()
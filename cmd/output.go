@@ -1,16 +1,98 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
 )
 
 // outputFormat holds the global output format flag value.
 var outputFormat string
 
-// isJSONOutput returns true when the user has requested JSON output.
-func isJSONOutput() bool {
-	return outputFormat == "json"
+// outputEnvVar sets the output format when --output is not given.
+const outputEnvVar = "GRANT_OUTPUT"
+
+// Output format names accepted by --output. template= and jsonpath= carry
+// their expression after the '='.
+const (
+	formatText     = "text"
+	formatJSON     = "json"
+	formatYAML     = "yaml"
+	formatCSV      = "csv"
+	formatTSV      = "tsv"
+	formatWide     = "wide"
+	formatTemplate = "template"
+	formatJSONPath = "jsonpath"
+	formatNDJSON   = "ndjson"
+)
+
+// validOutputFormats is the --output help and error text.
+const validOutputFormats = "text, json, ndjson, yaml, csv, tsv, wide, template=<go-template>, jsonpath=<expr>"
+
+// splitOutputFormat splits an --output value into its format name and, for
+// template= and jsonpath=, the expression.
+func splitOutputFormat(value string) (name, expr string) {
+	name, expr, _ = strings.Cut(value, "=")
+	return name, expr
+}
+
+// validateOutputFormat rejects unknown formats and unparseable expressions up
+// front, before a command does any work.
+func validateOutputFormat(value string) error {
+	name, expr := splitOutputFormat(value)
+	switch name {
+	case formatText, formatJSON, formatNDJSON, formatYAML, formatCSV, formatTSV, formatWide:
+		if name == value {
+			return nil
+		}
+	case formatTemplate:
+		if expr == "" {
+			return fmt.Errorf("invalid output format %q: template= needs a Go template, e.g. template='{{.sessionId}}'", value)
+		}
+		if _, err := template.New("output").Parse(expr); err != nil {
+			return fmt.Errorf("invalid output format %q: %w", value, err)
+		}
+		return nil
+	case formatJSONPath:
+		if _, err := parseJSONPath(expr); err != nil {
+			return fmt.Errorf("invalid output format %q: %w", value, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q: must be one of: %s", value, validOutputFormats)
+}
+
+// isStructuredOutput returns true when the user has requested machine-readable
+// output (any format but text). Commands then emit one typed document from
+// cmd/output_types.go through writeOutput instead of their text rendering.
+func isStructuredOutput() bool {
+	return outputFormat != "" && outputFormat != formatText
+}
+
+// writeOutput encodes data in the requested --output format. Every format is
+// derived from the document's JSON encoding, so field names and values are
+// the same in all of them.
+func writeOutput(w io.Writer, data any) error {
+	name, expr := splitOutputFormat(outputFormat)
+	switch name {
+	case formatYAML:
+		return writeYAML(w, data)
+	case formatCSV, formatTSV, formatWide:
+		return writeTable(w, name, data)
+	case formatTemplate:
+		return writeTemplate(w, expr, data)
+	case formatJSONPath:
+		return writeJSONPath(w, expr, data)
+	case formatNDJSON:
+		return writeNDJSON(w, data)
+	default:
+		return writeJSON(w, data)
+	}
 }
 
 // writeJSON encodes data as indented JSON to the given writer.
@@ -19,3 +101,66 @@ func writeJSON(w io.Writer, data any) error {
 	enc.SetIndent("", "  ")
 	return enc.Encode(data)
 }
+
+// toGeneric round-trips data through JSON into maps, slices and json.Number,
+// so the non-JSON formats see exactly the JSON field names and omissions.
+func toGeneric(data any) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// writeYAML encodes data as YAML with the JSON field names, in JSON field order.
+func writeYAML(w io.Writer, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML: decoding it into a node keeps the key order that a
+	// map round-trip would lose, and re-encoding yields block-style YAML.
+	var node yaml.Node
+	if err := yaml.Unmarshal(raw, &node); err != nil {
+		return err
+	}
+	clearFlowStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// clearFlowStyle switches a node tree decoded from JSON to block style with
+// plain scalars; the encoder re-quotes any string that needs it.
+func clearFlowStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, c := range n.Content {
+		clearFlowStyle(c)
+	}
+}
+
+// writeTemplate executes a Go template against the JSON form of data, so
+// fields are referenced by their JSON names: {{.sessionId}}.
+func writeTemplate(w io.Writer, expr string, data any) error {
+	tmpl, err := template.New("output").Parse(expr)
+	if err != nil {
+		return fmt.Errorf("invalid output template: %w", err)
+	}
+	v, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(w, v); err != nil {
+		return fmt.Errorf("output template failed: %w", err)
+	}
+	return nil
+}
@@ -31,16 +31,16 @@ import (
 
 // --- status -----------------------------------------------------------------
 
-// pinnedRemainingSeconds is the value substituted for the wall-clock-derived
-// remainingSeconds field before a whole-object comparison.
-const pinnedRemainingSeconds = 2700
-
-// pinRemainingSeconds range-checks every sessions[].remainingSeconds and
-// rewrites it to pinnedRemainingSeconds. The field is computed from time.Now()
-// and therefore cannot appear verbatim in a literal; pinning it keeps the rest
-// of the document — including whether the field is present at all — under the
-// whole-object comparison.
-func pinRemainingSeconds(t *testing.T, raw []byte, minSecs, maxSecs int) []byte {
+// remainingWindow is the accepted range of a wall-clock-derived
+// remainingSeconds value; max is also the value substituted for it.
+type remainingWindow struct{ min, max int }
+
+// pinRemainingSeconds range-checks every sessions[].remainingSeconds against
+// the window for its sessionId and rewrites it to the window's max. The field
+// is computed from time.Now() and therefore cannot appear verbatim in a
+// literal; pinning it keeps the rest of the document — including whether the
+// field is present at all — under the whole-object comparison.
+func pinRemainingSeconds(t *testing.T, raw []byte, windows map[string]remainingWindow) []byte {
 	t.Helper()
 
 	var doc map[string]interface{}
@@ -64,10 +64,14 @@ func pinRemainingSeconds(t *testing.T, raw []byte, minSecs, maxSecs int) []byte
 		if !ok {
 			t.Fatalf("remainingSeconds is not a number: %#v", v)
 		}
-		if int(secs) < minSecs || int(secs) > maxSecs {
-			t.Errorf("remainingSeconds = %d, want between %d and %d", int(secs), minSecs, maxSecs)
+		w, ok := windows[session["sessionId"].(string)]
+		if !ok {
+			t.Fatalf("unexpected remainingSeconds on %v", session["sessionId"])
+		}
+		if int(secs) < w.min || int(secs) > w.max {
+			t.Errorf("%v remainingSeconds = %d, want between %d and %d", session["sessionId"], int(secs), w.min, w.max)
 		}
-		session["remainingSeconds"] = pinnedRemainingSeconds
+		session["remainingSeconds"] = w.max
 	}
 
 	pinned, err := json.Marshal(doc)
@@ -130,10 +134,15 @@ func TestStatusJSON_Contract(t *testing.T) {
 		t.Fatalf("unexpected error: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
 	}
 
-	// The fixture makes this deterministically 2699; the window is narrow on
-	// purpose so a whole-minute arithmetic error dies at the pin itself rather
-	// than relying on the sibling text assertion.
-	got := pinRemainingSeconds(t, []byte(stdout), 2695, pinnedRemainingSeconds)
+	// The fixture makes the cloud session deterministically 2699; the window is
+	// narrow on purpose so a whole-minute arithmetic error dies at the pin
+	// itself rather than relying on the sibling text assertion. The group
+	// session was not elevated locally, so status observes it for the first
+	// time now and reports its full duration as an upper bound.
+	got := pinRemainingSeconds(t, []byte(stdout), map[string]remainingWindow{
+		"sess-cloud": {min: 2695, max: 2700},
+		"sess-group": {min: 1795, max: 1800},
+	})
 
 	assertJSONEqual(t, got, `{
   "authenticated": true,
@@ -147,6 +156,7 @@ func TestStatusJSON_Contract(t *testing.T) {
       "roleId": "role-id",
       "duration": 3600,
       "remainingSeconds": 2700,
+      "remainingBasis": "elevated",
       "type": "cloud"
     },
     {
@@ -155,6 +165,8 @@ func TestStatusJSON_Contract(t *testing.T) {
       "workspaceId": "dir-ws-id",
       "workspaceName": "dir-ws-name",
       "duration": 1800,
+      "remainingSeconds": 1800,
+      "remainingBasis": "first_observed",
       "type": "group",
       "groupId": "grp-id",
       "groupName": "grp-name"
@@ -554,6 +566,7 @@ func TestFavoritesListJSON_Contract(t *testing.T) {
 	cfg := config.DefaultConfig()
 	if err := config.AddFavorite(cfg, "fav-cloud", config.Favorite{
 		Provider: "aws", Target: "ws-name", Role: "role-name",
+		Description: "desc", Tags: []string{"tag-a", "tag-b"}, DefaultReason: "reason",
 	}); err != nil {
 		t.Fatalf("AddFavorite() error = %v", err)
 	}
@@ -581,14 +594,21 @@ func TestFavoritesListJSON_Contract(t *testing.T) {
     "type": "cloud",
     "provider": "aws",
     "target": "ws-name",
-    "role": "role-name"
+    "role": "role-name",
+    "description": "desc",
+    "tags": ["tag-a", "tag-b"],
+    "defaultReason": "reason",
+    "source": "user",
+    "readOnly": false
   },
   {
     "name": "fav-group",
     "type": "groups",
     "provider": "azure",
     "group": "grp-name",
-    "directoryId": "dir-id"
+    "directoryId": "dir-id",
+    "source": "user",
+    "readOnly": false
   }
 ]`)
 }
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep is one step of a parsed jsonpath= expression: a field name, an
+// array index, or a wildcard over every element.
+type jsonPathStep struct {
+	field    string
+	index    int
+	wildcard bool
+	isIndex  bool
+}
+
+// parseJSONPath parses the jsonpath subset grant supports: an optional
+// kubectl-style {} wrapper and leading $, then .field, [n] and [*] steps, e.g.
+// {.sessions[*].sessionId} or $.requests[0].state.
+func parseJSONPath(expr string) ([]jsonPathStep, error) {
+	p := strings.TrimSpace(expr)
+	if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+		p = strings.TrimSpace(p[1 : len(p)-1])
+	}
+	p = strings.TrimPrefix(p, "$")
+	if p == "" {
+		return nil, errors.New("jsonpath= needs an expression, e.g. jsonpath='{.sessions[*].sessionId}'")
+	}
+
+	var steps []jsonPathStep
+	for p != "" {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty field name in jsonpath %q", expr)
+			}
+			steps = append(steps, jsonPathStep{field: p[:end]})
+			p = p[end:]
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in jsonpath %q", expr)
+			}
+			inner := strings.TrimSpace(p[1:end])
+			p = p[end+1:]
+			if inner == "*" {
+				steps = append(steps, jsonPathStep{wildcard: true})
+				continue
+			}
+			n, err := strconv.Atoi(inner)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid index [%s] in jsonpath %q: must be a non-negative number or *", inner, expr)
+			}
+			steps = append(steps, jsonPathStep{index: n, isIndex: true})
+		default:
+			return nil, fmt.Errorf("invalid jsonpath %q: steps must start with . or [", expr)
+		}
+	}
+	return steps, nil
+}
+
+// writeJSONPath evaluates a jsonpath= expression against the JSON form of data
+// and writes each match on its own line: strings raw, anything else as JSON. A
+// path that matches nothing writes nothing.
+func writeJSONPath(w io.Writer, expr string, data any) error {
+	steps, err := parseJSONPath(expr)
+	if err != nil {
+		return err
+	}
+	doc, err := toGeneric(data)
+	if err != nil {
+		return err
+	}
+
+	matches := []any{doc}
+	for _, step := range steps {
+		var next []any
+		for _, m := range matches {
+			switch {
+			case step.wildcard:
+				if list, ok := m.([]any); ok {
+					next = append(next, list...)
+				}
+			case step.isIndex:
+				if list, ok := m.([]any); ok && step.index < len(list) {
+					next = append(next, list[step.index])
+				}
+			default:
+				if obj, ok := m.(map[string]any); ok {
+					if v, ok := obj[step.field]; ok {
+						next = append(next, v)
+					}
+				}
+			}
+		}
+		matches = next
+	}
+
+	for _, m := range matches {
+		if s, ok := m.(string); ok {
+			fmt.Fprintln(w, s)
+			continue
+		}
+		raw, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(raw))
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+)
+
+// tableListColumn is the extra leading column added when a document holds
+// more than one list (grant list: cloud and groups), naming the list a row
+// came from.
+const tableListColumn = "kind"
+
+// writeTable renders data as csv, tsv or a wide aligned table.
+//
+// Rows come from the document's list: a top-level slice, or the slice fields
+// of a wrapper struct such as statusOutput.sessions. Wrapper fields that are
+// not lists (authenticated, totalCount) are not part of the table. A document
+// with no list is a single row. Columns are every JSON field of the row type,
+// in declaration order, so the header is stable even when a field is omitted
+// from every row; a nested value is written as compact JSON.
+func writeTable(w io.Writer, format string, data any) error {
+	columns, rows, err := tabulate(data)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		_ = cw.Write(columns)
+		_ = cw.WriteAll(rows) // WriteAll flushes
+		return cw.Error()
+	case formatTSV:
+		var b strings.Builder
+		for _, row := range append([][]string{columns}, rows...) {
+			for i, cell := range row {
+				row[i] = tsvEscaper.Replace(cell)
+			}
+			b.WriteString(strings.Join(row, "\t"))
+			b.WriteByte('\n')
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		headers := make([]string, len(columns))
+		for i, c := range columns {
+			headers[i] = wideHeader(c)
+		}
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+		for _, row := range rows {
+			for i, cell := range row {
+				row[i] = tsvEscaper.Replace(cell)
+			}
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// tsvEscaper keeps a cell on one line and in one column.
+var tsvEscaper = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+// tableSource is one list of rows and the JSON name it appears under.
+type tableSource struct {
+	name    string // JSON field name; "" for a top-level slice or a single row
+	rowType reflect.Type
+}
+
+// tabulate finds the rows of data and flattens them into string cells.
+func tabulate(data any) ([]string, [][]string, error) {
+	t := reflect.TypeOf(data)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, nil, nil
+	}
+
+	var sources []tableSource
+	switch {
+	case t.Kind() == reflect.Slice && isStructType(t.Elem()):
+		sources = []tableSource{{rowType: derefType(t.Elem())}}
+	case t.Kind() == reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, ok := jsonFieldName(f)
+			if ok && f.Type.Kind() == reflect.Slice && isStructType(f.Type.Elem()) {
+				sources = append(sources, tableSource{name: name, rowType: derefType(f.Type.Elem())})
+			}
+		}
+		if len(sources) == 0 {
+			sources = []tableSource{{rowType: t}}
+		}
+	default:
+		return nil, nil, fmt.Errorf("output format %q is not supported for this command", outputFormat)
+	}
+
+	// Column union in first-seen order; with several lists, a leading column
+	// says which list each row belongs to.
+	var columns []string
+	seen := make(map[string]bool)
+	if len(sources) > 1 {
+		columns = append(columns, tableListColumn)
+	}
+	for _, src := range sources {
+		for _, c := range structColumns(src.rowType) {
+			if !seen[c] {
+				seen[c] = true
+				columns = append(columns, c)
+			}
+		}
+	}
+
+	doc, err := toGeneric(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows [][]string
+	for _, src := range sources {
+		var items []any
+		switch v := doc.(type) {
+		case []any:
+			items = v
+		case map[string]any:
+			if src.name == "" {
+				items = []any{v}
+			} else if list, ok := v[src.name].([]any); ok {
+				items = list
+			}
+		}
+		for _, item := range items {
+			obj, _ := item.(map[string]any)
+			row := make([]string, len(columns))
+			for i, c := range columns {
+				if c == tableListColumn && len(sources) > 1 {
+					row[i] = src.name
+					continue
+				}
+				row[i] = cellString(obj[c])
+			}
+			rows = append(rows, row)
+		}
+	}
+	return columns, rows, nil
+}
+
+// structColumns returns the JSON field names of a struct type in order.
+func structColumns(t reflect.Type) []string {
+	var cols []string
+	for i := 0; i < t.NumField(); i++ {
+		if name, ok := jsonFieldName(t.Field(i)); ok {
+			cols = append(cols, name)
+		}
+	}
+	return cols
+}
+
+// jsonFieldName returns the name a field is encoded under, and false for
+// unexported or json:"-" fields.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
+
+func isStructType(t reflect.Type) bool {
+	return derefType(t).Kind() == reflect.Struct
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// cellString formats one generic JSON value as a table cell.
+func cellString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	default:
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	}
+}
+
+// wideHeader turns a JSON field name into a table header: sessionId -> SESSION_ID.
+func wideHeader(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -3,10 +3,11 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
-func TestIsJSONOutput(t *testing.T) {
+func TestIsStructuredOutput(t *testing.T) {
 	tests := []struct {
 		name   string
 		format string
@@ -14,6 +15,9 @@ func TestIsJSONOutput(t *testing.T) {
 	}{
 		{"text format", "text", false},
 		{"json format", "json", true},
+		{"yaml format", "yaml", true},
+		{"csv format", "csv", true},
+		{"template format", "template={{.name}}", true},
 		{"empty format", "", false},
 	}
 
@@ -23,8 +27,8 @@ func TestIsJSONOutput(t *testing.T) {
 			defer func() { outputFormat = old }()
 
 			outputFormat = tt.format
-			if got := isJSONOutput(); got != tt.want {
-				t.Errorf("isJSONOutput() = %v, want %v", got, tt.want)
+			if got := isStructuredOutput(); got != tt.want {
+				t.Errorf("isStructuredOutput() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -54,3 +58,160 @@ func TestWriteJSON(t *testing.T) {
 		t.Errorf("expected count=42, got %v", parsed["count"])
 	}
 }
+
+// formatFixture is a status-shaped document: a wrapper with one list, a
+// pointer field that is omitted on one row, and a value needing CSV quoting.
+func formatFixture() statusOutput {
+	secs := 2700
+	return statusOutput{
+		Authenticated: true,
+		Username:      "user@example.test",
+		Sessions: []sessionOutput{
+			{SessionID: "s1", Provider: "aws", WorkspaceID: "111", WorkspaceName: "Prod, EU", RoleID: "Admin", Duration: 3600, RemainingSeconds: &secs, RemainingBasis: "elevated", Type: "cloud"},
+			{SessionID: "s2", Provider: "azure", WorkspaceID: "dir", Duration: 1800, Type: "group", GroupID: "g1", GroupName: "Cloud Admins"},
+		},
+	}
+}
+
+func TestWriteOutput_Formats(t *testing.T) {
+	header := "sessionId,provider,workspaceId,workspaceName,roleId,duration,remainingSeconds,remainingBasis,type,groupId,groupName"
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "csv",
+			want: header + "\n" +
+				`s1,aws,111,"Prod, EU",Admin,3600,2700,elevated,cloud,,` + "\n" +
+				"s2,azure,dir,,,1800,,,group,g1,Cloud Admins\n",
+		},
+		{
+			format: "tsv",
+			want: strings.ReplaceAll(header, ",", "\t") + "\n" +
+				"s1\taws\t111\tProd, EU\tAdmin\t3600\t2700\televated\tcloud\t\t\n" +
+				"s2\tazure\tdir\t\t\t1800\t\t\tgroup\tg1\tCloud Admins\n",
+		},
+		{
+			format: "template={{range .sessions}}{{.sessionId}}={{.provider}};{{end}}",
+			want:   "s1=aws;s2=azure;",
+		},
+		{
+			format: "jsonpath={.sessions[*].sessionId}",
+			want:   "s1\ns2\n",
+		},
+		{
+			format: "jsonpath=$.sessions[0].remainingSeconds",
+			want:   "2700\n",
+		},
+		{
+			format: "jsonpath={.authenticated}",
+			want:   "true\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			old := outputFormat
+			defer func() { outputFormat = old }()
+			outputFormat = tt.format
+
+			var buf bytes.Buffer
+			if err := writeOutput(&buf, formatFixture()); err != nil {
+				t.Fatalf("writeOutput() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got:\n%q\nwant:\n%q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteOutput_YAMLUsesJSONFieldNames(t *testing.T) {
+	old := outputFormat
+	defer func() { outputFormat = old }()
+	outputFormat = "yaml"
+
+	var buf bytes.Buffer
+	if err := writeOutput(&buf, formatFixture()); err != nil {
+		t.Fatalf("writeOutput() error = %v", err)
+	}
+	want := `authenticated: true
+username: user@example.test
+sessions:
+  - sessionId: s1
+    provider: aws
+    workspaceId: "111"
+    workspaceName: Prod, EU
+    roleId: Admin
+    duration: 3600
+    remainingSeconds: 2700
+    remainingBasis: elevated
+    type: cloud
+  - sessionId: s2
+    provider: azure
+    workspaceId: dir
+    duration: 1800
+    type: group
+    groupId: g1
+    groupName: Cloud Admins
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteOutput_WideAndMultipleLists(t *testing.T) {
+	old := outputFormat
+	defer func() { outputFormat = old }()
+
+	t.Run("wide aligns columns under upper-case headers", func(t *testing.T) {
+		outputFormat = "wide"
+		var buf bytes.Buffer
+		if err := writeOutput(&buf, []favoriteOutput{{Name: "prod-admin", Type: "cloud", Provider: "aws", Target: "Prod", Role: "Admin"}}); err != nil {
+			t.Fatalf("writeOutput() error = %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], "NAME        TYPE   PROVIDER  TARGET  ROLE") || !strings.Contains(lines[0], "DIRECTORY_ID") {
+			t.Errorf("unexpected table:\n%s", buf.String())
+		}
+	})
+
+	t.Run("several lists get a kind column", func(t *testing.T) {
+		outputFormat = "csv"
+		var buf bytes.Buffer
+		doc := listOutput{
+			Cloud:  []listCloudTarget{{Provider: "aws", Target: "Prod", WorkspaceID: "111", Role: "Admin"}},
+			Groups: []listGroupTarget{{GroupName: "Admins", GroupID: "g1"}},
+		}
+		if err := writeOutput(&buf, doc); err != nil {
+			t.Fatalf("writeOutput() error = %v", err)
+		}
+		want := "kind,provider,target,workspaceId,workspaceType,role,roleId,groupName,groupId,directoryId,directory\n" +
+			"cloud,aws,Prod,111,,Admin,,,,,\n" +
+			"groups,,,,,,,Admins,g1,,\n"
+		if buf.String() != want {
+			t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+		}
+	})
+
+	t.Run("a document without a list is one row", func(t *testing.T) {
+		outputFormat = "csv"
+		var buf bytes.Buffer
+		if err := writeOutput(&buf, cloudElevationOutput{Type: "cloud", Provider: "aws", SessionID: "s1", Target: "Prod", Role: "Admin"}); err != nil {
+			t.Fatalf("writeOutput() error = %v", err)
+		}
+		want := "type,provider,sessionId,target,role,credentials\ncloud,aws,s1,Prod,Admin,\n"
+		if buf.String() != want {
+			t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+		}
+	})
+}
+
+func TestParseJSONPath_Errors(t *testing.T) {
+	for _, expr := range []string{"", "{}", "$", ".a[", ".a[-1]", ".a[x]", "a.b", ".a..b"} {
+		if _, err := parseJSONPath(expr); err == nil {
+			t.Errorf("parseJSONPath(%q) succeeded, want an error", expr)
+		}
+	}
+}
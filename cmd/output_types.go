@@ -1,5 +1,7 @@
 package cmd
 
+import "time"
+
 // cloudElevationOutput is the JSON representation of a cloud elevation result.
 type cloudElevationOutput struct {
 	Type        string               `json:"type"`
@@ -36,11 +38,31 @@ type sessionOutput struct {
 	RoleID           string `json:"roleId,omitempty"`
 	Duration         int    `json:"duration"`
 	RemainingSeconds *int   `json:"remainingSeconds,omitempty"`
+	RemainingBasis   string `json:"remainingBasis,omitempty"` // elevated (exact) | first_observed (upper bound)
 	Type             string `json:"type"`
 	GroupID          string `json:"groupId,omitempty"`
 	GroupName        string `json:"groupName,omitempty"`
 }
 
+// promptSessionOutput is one session rendered by grant prompt. Its field names
+// are also the fields available to the --format template.
+type promptSessionOutput struct {
+	SessionID        string `json:"sessionId"`
+	Provider         string `json:"provider"`
+	Type             string `json:"type"`
+	Target           string `json:"target"`
+	Role             string `json:"role,omitempty"`
+	Remaining        string `json:"remaining,omitempty"` // "42m", "1h05m", "≤42m"; empty when unknown
+	RemainingSeconds *int   `json:"remainingSeconds,omitempty"`
+	UpperBound       bool   `json:"upperBound,omitempty"` // remaining is counted from first observation
+}
+
+// remainingBasis values: what a session's remainingSeconds is counted from.
+const (
+	remainingBasisElevated      = "elevated"       // local elevation time; exact
+	remainingBasisFirstObserved = "first_observed" // first time grant saw it listed; an upper bound
+)
+
 // statusOutput is the JSON representation of grant status.
 type statusOutput struct {
 	Authenticated bool            `json:"authenticated"`
@@ -48,6 +70,18 @@ type statusOutput struct {
 	Sessions      []sessionOutput `json:"sessions"`
 }
 
+// requirementOutput is the JSON representation of grant status --require.
+// It is written on stdout whether or not the requirement was met; reason is
+// one of not_authenticated, no_matching_session, insufficient_remaining or
+// remaining_unknown. session is the satisfying session, or with
+// insufficient_remaining the closest match.
+type requirementOutput struct {
+	Satisfied bool           `json:"satisfied"`
+	Reason    string         `json:"reason,omitempty"`
+	Message   string         `json:"message,omitempty"`
+	Session   *sessionOutput `json:"session,omitempty"`
+}
+
 // revocationOutput is the JSON representation of a revocation result.
 // There is one entry per *requested* session, in requested order, plus any
 // results the service returned that could not be attributed to a request.
@@ -59,20 +93,59 @@ type statusOutput struct {
 type revocationOutput struct {
 	SessionID  string `json:"sessionId"`
 	Status     string `json:"status"`           // raw API value; "" when no row was returned
-	Outcome    string `json:"outcome"`          // revoked | in_progress | not_applicable | unknown
+	Outcome    string `json:"outcome"`          // revoked | in_progress | not_applicable | unknown; with --wait also confirmed_gone | still_present
 	Reason     string `json:"reason,omitempty"` // explanation when not confirmed revoked
 	Unexpected bool   `json:"unexpected,omitempty"`
 }
 
 // favoriteOutput is the JSON representation of a saved favorite.
 type favoriteOutput struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Provider       string   `json:"provider"`
+	Target         string   `json:"target,omitempty"`
+	Role           string   `json:"role,omitempty"`
+	Group          string   `json:"group,omitempty"`
+	DirectoryID    string   `json:"directoryId,omitempty"`
+	GroupID        string   `json:"groupId,omitempty"`
+	WorkspaceID    string   `json:"workspaceId,omitempty"`
+	RoleID         string   `json:"roleId,omitempty"`
+	OrganizationID string   `json:"organizationId,omitempty"`
+	Profile        string   `json:"profile,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	DefaultReason  string   `json:"defaultReason,omitempty"`
+	Source         string   `json:"source"`
+	ReadOnly       bool     `json:"readOnly"`
+}
+
+// favoriteCheckOutput is the JSON representation of one favorite in grant
+// favorites check. status is ok, ambiguous or missing; candidates are the
+// matches of an ambiguous favorite or the closest ones of a missing favorite.
+type favoriteCheckOutput struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Favorite   string   `json:"favorite"`
+	Status     string   `json:"status"`
+	Reason     string   `json:"reason,omitempty"`
+	Candidates []string `json:"candidates,omitempty"`
+	Pinned     bool     `json:"pinned,omitempty"`
+}
+
+// configValueOutput is the JSON representation of one setting in grant config
+// get and view. Its fields mirror config.Setting so one converts to the other.
+type configValueOutput struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// profileOutput is the JSON representation of a profile in grant profiles list.
+type profileOutput struct {
 	Name        string `json:"name"`
-	Type        string `json:"type"`
-	Provider    string `json:"provider"`
-	Target      string `json:"target,omitempty"`
-	Role        string `json:"role,omitempty"`
-	Group       string `json:"group,omitempty"`
-	DirectoryID string `json:"directoryId,omitempty"`
+	Username    string `json:"username"`
+	IdentityURL string `json:"identityUrl,omitempty"`
+	Current     bool   `json:"current"`
 }
 
 // accessRequestOutput is the JSON representation of an access request.
@@ -103,3 +176,148 @@ type accessRequestListOutput struct {
 	Requests   []accessRequestOutput `json:"requests"`
 	TotalCount int                   `json:"totalCount"`
 }
+
+// cacheEntryOutput is the JSON representation of one cache entry in
+// grant cache list. cachedAt and ageSeconds are absent for an undecodable file.
+type cacheEntryOutput struct {
+	Key        string    `json:"key"`
+	Kind       string    `json:"kind"`
+	CachedAt   time.Time `json:"cachedAt,omitzero"`
+	AgeSeconds int       `json:"ageSeconds,omitempty"`
+	SizeBytes  int64     `json:"sizeBytes"`
+	Items      int       `json:"items"`
+	Expired    bool      `json:"expired"`
+}
+
+// cacheClearOutput is the JSON representation of grant cache clear.
+type cacheClearOutput struct {
+	Removed []string `json:"removed"`
+}
+
+// cacheWarmOutput is one prefetch made by grant cache warm.
+type cacheWarmOutput struct {
+	Kind     string `json:"kind"` // eligibility | groups
+	Provider string `json:"provider"`
+	Items    int    `json:"items"`
+	Error    string `json:"error,omitempty"`
+}
+
+// cacheStatsOutput is the JSON representation of grant cache stats.
+type cacheStatsOutput struct {
+	Since time.Time              `json:"since,omitzero"`
+	Kinds []cacheKindStatsOutput `json:"kinds"`
+}
+
+// cacheKindStatsOutput holds the lookup counters of one cache kind. hitRate
+// is hits over hits plus misses, 0 when there were no lookups.
+type cacheKindStatsOutput struct {
+	Kind    string  `json:"kind"`
+	Hits    int     `json:"hits"`
+	Misses  int     `json:"misses"`
+	Stale   int     `json:"stale"`
+	HitRate float64 `json:"hitRate"`
+}
+
+// errorOutput is the structured error envelope written to stderr when a
+// command fails under a structured --output format.
+type errorOutput struct {
+	Error errorDetail `json:"error"`
+}
+
+// errorDetail describes a failed command. code is one of the errorCode
+// constants and is stable; message is for humans and may change. httpStatus is
+// set when the failure was an API response, and errorInfo when the service
+// explained the failure, in a refused elevation or an error response body.
+type errorDetail struct {
+	Code       string           `json:"code"`
+	Message    string           `json:"message"`
+	ExitCode   int              `json:"exitCode"`
+	HTTPStatus int              `json:"httpStatus,omitempty"`
+	ErrorInfo  *errorInfoOutput `json:"errorInfo,omitempty"`
+}
+
+// errorInfoOutput is the JSON representation of the service's ErrorInfo.
+type errorInfoOutput struct {
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	Description string `json:"description"`
+	Link        string `json:"link,omitempty"`
+}
+
+// eventOutput is one line of --output ndjson. data is one of the *EventData
+// types below, the command's document for a result event, or an errorDetail
+// for an error event.
+type eventOutput struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	Data any       `json:"data,omitempty"`
+}
+
+// eligibilityEventData reports one eligibility fetch. kind is cloud or groups;
+// error is set instead of count when the fetch failed.
+type eligibilityEventData struct {
+	Kind     string `json:"kind"`
+	Provider string `json:"provider"`
+	Count    int    `json:"count"`
+	Error    string `json:"error,omitempty"`
+}
+
+// pageEventData reports one page of a paginated list call. page counts from 1;
+// operation names what was listed and route the API path.
+type pageEventData struct {
+	Operation string `json:"operation"`
+	Route     string `json:"route"`
+	Page      int    `json:"page"`
+	Count     int    `json:"count"`
+}
+
+// cacheEventData reports one cache lookup. A cache_stale event adds the age of
+// the expired entry served and the API failure that caused it.
+type cacheEventData struct {
+	Key        string `json:"key"`
+	AgeSeconds int    `json:"ageSeconds,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// elevationEventData reports the progress of one elevation. sessionId is set
+// once it succeeded; error and errorInfo once it failed.
+type elevationEventData struct {
+	Type      string           `json:"type"`
+	Provider  string           `json:"provider"`
+	Target    string           `json:"target,omitempty"`
+	Role      string           `json:"role,omitempty"`
+	GroupName string           `json:"groupName,omitempty"`
+	GroupID   string           `json:"groupId,omitempty"`
+	SessionID string           `json:"sessionId,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	ErrorInfo *errorInfoOutput `json:"errorInfo,omitempty"`
+}
+
+// revokeBatchEventData reports one revoke request. batch counts from 1.
+type revokeBatchEventData struct {
+	Batch      int                    `json:"batch"`
+	Batches    int                    `json:"batches"`
+	SessionIDs []string               `json:"sessionIds"`
+	Results    []revokeBatchEventItem `json:"results,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// revokeBatchEventItem is the service's raw status for one session in a batch.
+type revokeBatchEventItem struct {
+	SessionID string `json:"sessionId"`
+	Status    string `json:"status"`
+}
+
+// revokePollEventData reports one session-list poll made by revoke --wait.
+// pending counts the revoked sessions still listed.
+type revokePollEventData struct {
+	Pending int    `json:"pending"`
+	Error   string `json:"error,omitempty"`
+}
+
+// requestStateEventData reports an access request's state after grant changed it.
+type requestStateEventData struct {
+	RequestID string `json:"requestId"`
+	State     string `json:"state"`
+	Result    string `json:"result,omitempty"`
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	sdkmodels "github.com/cyberark/idsec-sdk-golang/pkg/models"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+	"github.com/cyberark/idsec-sdk-golang/pkg/profiles"
+	"github.com/spf13/cobra"
+)
+
+// profileEnvVar selects the profile when --profile is not given.
+const profileEnvVar = "GRANT_PROFILE"
+
+// grantProfileDescription marks the SDK profiles written by grant configure,
+// which share the SDK's profile folder with other tools.
+const grantProfileDescription = "SCA CLI Profile"
+
+// profileFlag holds the value of the persistent --profile flag.
+var profileFlag string
+
+// validProfileName matches profile names that are safe as SDK profile file
+// names.
+var validProfileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// validateProfileName rejects a name that cannot be used as a profile.
+func validateProfileName(name string) error {
+	if !validProfileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use up to 64 letters, digits, '.', '_' or '-', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// explicitProfile returns the profile selected on the command line or in the
+// environment, or "".
+func explicitProfile() string {
+	if profileFlag != "" {
+		return profileFlag
+	}
+	return os.Getenv(profileEnvVar)
+}
+
+// activeProfile returns the SDK profile this command works with: --profile,
+// then GRANT_PROFILE, then the current profile set with 'grant profiles use'.
+func activeProfile() string {
+	if name := explicitProfile(); name != "" {
+		return name
+	}
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err != nil {
+		return config.DefaultProfile
+	}
+	return profileOf(cfg)
+}
+
+// profileOf is activeProfile for an already loaded config.
+func profileOf(cfg *config.Config) string {
+	if name := explicitProfile(); name != "" {
+		return name
+	}
+	if cfg == nil || cfg.Profile == "" {
+		return config.DefaultProfile
+	}
+	return cfg.Profile
+}
+
+// profileStore manages the SDK profiles on disk.
+type profileStore interface {
+	LoadProfile(name string) (*sdkmodels.IdsecProfile, error)
+	LoadAllProfiles() ([]*sdkmodels.IdsecProfile, error)
+	ProfileExists(name string) bool
+	DeleteProfile(name string) error
+}
+
+// NewProfilesCommand creates the profiles parent command with subcommands.
+func NewProfilesCommand() *cobra.Command {
+	return NewProfilesCommandWithDeps(&profiles.FileSystemProfilesLoader{}, sdkKeyringClearer{})
+}
+
+// NewProfilesCommandWithDeps creates a profiles command over store and the
+// keyring behind clearer for testing.
+func NewProfilesCommandWithDeps(store profileStore, clearer keyringClearer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage tenant profiles",
+		Long: `List, switch between and remove the tenant profiles created with
+'grant configure --profile <name>'. Each profile has its own Identity URL,
+username, login and cache. A single command can use another profile with
+--profile or GRANT_PROFILE without switching.
+
+Examples:
+  grant configure --profile acme
+  grant login --profile acme
+  grant profiles use acme
+  grant profiles list`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List configured profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesList(cmd, store)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:     "use <name>",
+		Short:   "Make a profile the current one",
+		Example: "  grant profiles use acme",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesUse(cmd, store, args[0])
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a profile, its tokens and its cache",
+		Long: `Remove a profile and delete its cached eligibility and session state.
+Its login tokens are cleared from the keyring first; since every profile's
+tokens share the keyring, as with 'grant logout', the other profiles are
+logged out too. Favorites saved under the profile are kept and come back if a
+profile of the same name is configured again. Removing the current profile
+makes the default profile current.`,
+		Example: "  grant profiles remove acme",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfilesRemove(cmd, store, clearer, args[0])
+		},
+	})
+	return cmd
+}
+
+func runProfilesList(cmd *cobra.Command, store profileStore) error {
+	all, err := store.LoadAllProfiles()
+	if err != nil {
+		return fmt.Errorf("failed to load profiles: %w", err)
+	}
+	current := activeProfile()
+
+	out := []profileOutput{}
+	for _, p := range all {
+		if p == nil || p.ProfileDescription != grantProfileDescription {
+			continue
+		}
+		o := profileOutput{Name: p.ProfileName, Current: p.ProfileName == current}
+		if ap := p.AuthProfiles["isp"]; ap != nil {
+			o.Username = ap.Username
+			if settings, ok := ap.AuthMethodSettings.(*authmodels.IdentityIdsecAuthMethodSettings); ok && settings != nil {
+				o.IdentityURL = settings.IdentityURL
+			}
+		}
+		out = append(out, o)
+	}
+	slices.SortFunc(out, func(a, b profileOutput) int { return strings.Compare(a.Name, b.Name) })
+
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), out)
+	}
+
+	if len(out) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No profiles configured. Run 'grant configure' to create one.")
+		return nil
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tUSERNAME\tIDENTITY URL")
+	for _, o := range out {
+		marker := ""
+		if o.Current {
+			marker = "*"
+		}
+		identityURL := o.IdentityURL
+		if identityURL == "" {
+			identityURL = "(auto-discovered)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", marker, o.Name, o.Username, identityURL)
+	}
+	return w.Flush()
+}
+
+func runProfilesUse(cmd *cobra.Command, store profileStore, name string) error {
+	if err := validateProfileName(name); err != nil {
+		return withCode(codeUsage, err)
+	}
+	if !store.ProfileExists(name) {
+		return fmt.Errorf("profile %q not found, run 'grant configure --profile %s' to create it", name, name)
+	}
+
+	cfg, cfgPath, err := config.LoadUserWithPath()
+	if err != nil {
+		return err
+	}
+	cfg.Profile = name
+	if err := config.Save(cfg, cfgPath); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Now using profile %q\n", name)
+	return nil
+}
+
+func runProfilesRemove(cmd *cobra.Command, store profileStore, clearer keyringClearer, name string) error {
+	if err := validateProfileName(name); err != nil {
+		return withCode(codeUsage, err)
+	}
+	profile, err := store.LoadProfile(name)
+	if err != nil {
+		return fmt.Errorf("failed to load profile: %w", err)
+	}
+	if profile == nil {
+		return fmt.Errorf("profile %q not found", name)
+	}
+
+	// Clear the tokens while the profile still exists: once it is gone,
+	// nothing would log it out.
+	if err := clearTokens(clearer); err != nil {
+		return err
+	}
+	if err := store.DeleteProfile(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove profile: %w", err)
+	}
+	invalidateCache(namespaceOf(name, profile))
+
+	cfg, cfgPath, err := config.LoadUserWithPath()
+	if err != nil {
+		return err
+	}
+	if cfg.Profile == name {
+		cfg.Profile = config.DefaultProfile
+		if err := config.Save(cfg, cfgPath); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Removed profile %q; other profiles need 'grant login' again\n", name)
+	return nil
+}
@@ -0,0 +1,335 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/cyberark/idsec-sdk-golang/pkg/common/keyring"
+	sdkmodels "github.com/cyberark/idsec-sdk-golang/pkg/models"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+	"github.com/spf13/cobra"
+)
+
+// fakeProfileStore is an in-memory profileStore.
+type fakeProfileStore struct {
+	profiles map[string]*sdkmodels.IdsecProfile
+	deleted  []string
+}
+
+func newFakeProfileStore(names ...string) *fakeProfileStore {
+	s := &fakeProfileStore{profiles: make(map[string]*sdkmodels.IdsecProfile)}
+	for _, name := range names {
+		s.profiles[name] = &sdkmodels.IdsecProfile{
+			ProfileName:        name,
+			ProfileDescription: grantProfileDescription,
+			AuthProfiles: map[string]*authmodels.IdsecAuthProfile{
+				"isp": {
+					Username: "me@" + name + ".com",
+					AuthMethodSettings: &authmodels.IdentityIdsecAuthMethodSettings{
+						IdentityURL: "https://" + name + ".id.cyberark.cloud",
+					},
+				},
+			},
+		}
+	}
+	return s
+}
+
+func (s *fakeProfileStore) LoadProfile(name string) (*sdkmodels.IdsecProfile, error) {
+	return s.profiles[name], nil
+}
+
+func (s *fakeProfileStore) LoadAllProfiles() ([]*sdkmodels.IdsecProfile, error) {
+	var all []*sdkmodels.IdsecProfile
+	for _, p := range s.profiles {
+		all = append(all, p)
+	}
+	return all, nil
+}
+
+func (s *fakeProfileStore) ProfileExists(name string) bool {
+	_, ok := s.profiles[name]
+	return ok
+}
+
+func (s *fakeProfileStore) DeleteProfile(name string) error {
+	delete(s.profiles, name)
+	s.deleted = append(s.deleted, name)
+	return nil
+}
+
+// writeProfileConfig points GRANT_CONFIG at a config whose current profile is
+// current and returns its path.
+func writeProfileConfig(t *testing.T, current string) string {
+	t.Helper()
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := config.DefaultConfig()
+	cfg.Profile = current
+	if err := config.Save(cfg, cfgPath); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GRANT_CONFIG", cfgPath)
+	return cfgPath
+}
+
+func newProfilesTestRoot(store profileStore) *cobra.Command {
+	return newProfilesTestRootWithKeyring(store, &mockKeyringClearer{})
+}
+
+func newProfilesTestRootWithKeyring(store profileStore, clearer keyringClearer) *cobra.Command {
+	root := newTestRootCommand()
+	root.AddCommand(NewProfilesCommandWithDeps(store, clearer))
+	return root
+}
+
+// Not parallel: sets GRANT_CONFIG and GRANT_PROFILE for the process.
+func TestActiveProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		env     string
+		flag    string
+		want    string
+	}{
+		{name: "default", want: config.DefaultProfile},
+		{name: "current profile", current: "acme", want: "acme"},
+		{name: "env overrides current", current: "acme", env: "globex", want: "globex"},
+		{name: "flag overrides env", current: "acme", env: "globex", flag: "initech", want: "initech"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeProfileConfig(t, tt.current)
+			t.Setenv(profileEnvVar, tt.env)
+			profileFlag = tt.flag
+			defer func() { profileFlag = "" }()
+
+			if got := activeProfile(); got != tt.want {
+				t.Errorf("activeProfile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootCommand_InvalidProfile(t *testing.T) {
+	root := newTestRootCommand()
+	root.AddCommand(newNoOpCommand())
+
+	_, err := executeCommand(root, "noop", "--profile", "../etc")
+	if err == nil || !strings.Contains(err.Error(), "invalid profile name") {
+		t.Fatalf("err = %v, want an invalid profile name error", err)
+	}
+	if got := classifyError(err, true); got.code != codeUsage {
+		t.Errorf("code = %s, want %s", got.code, codeUsage)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestProfilesList(t *testing.T) {
+	writeProfileConfig(t, "acme")
+	store := newFakeProfileStore("acme", "globex")
+	store.profiles["other-tool"] = &sdkmodels.IdsecProfile{ProfileName: "other-tool", ProfileDescription: "Another CLI"}
+
+	output, err := executeCommand(newProfilesTestRoot(store), "profiles", "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"CURRENT", "acme", "me@globex.com", "https://globex.id.cyberark.cloud"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "other-tool") {
+		t.Errorf("output lists a profile grant did not create:\n%s", output)
+	}
+
+	stdout, _, err := executeCommandStreams(newProfilesTestRoot(store), "profiles", "list", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []profileOutput
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	want := []profileOutput{
+		{Name: "acme", Username: "me@acme.com", IdentityURL: "https://acme.id.cyberark.cloud", Current: true},
+		{Name: "globex", Username: "me@globex.com", IdentityURL: "https://globex.id.cyberark.cloud"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("profiles = %+v, want %+v", got, want)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestProfilesUse(t *testing.T) {
+	cfgPath := writeProfileConfig(t, "grant")
+	store := newFakeProfileStore("grant", "acme")
+
+	if _, err := executeCommand(newProfilesTestRoot(store), "profiles", "use", "acme"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != "acme" {
+		t.Errorf("profile = %q, want %q", cfg.Profile, "acme")
+	}
+
+	_, err = executeCommand(newProfilesTestRoot(store), "profiles", "use", "missing")
+	if err == nil || !strings.Contains(err.Error(), "grant configure --profile missing") {
+		t.Errorf("err = %v, want a hint to configure the profile", err)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestProfilesRemove(t *testing.T) {
+	cfgPath := writeProfileConfig(t, "acme")
+	store := newFakeProfileStore("grant", "acme")
+	kr := &mockKeyringClearer{}
+
+	output, err := executeCommand(newProfilesTestRootWithKeyring(store, kr), "profiles", "remove", "acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, output)
+	}
+	if len(store.deleted) != 1 || store.deleted[0] != "acme" {
+		t.Errorf("deleted = %v, want [acme]", store.deleted)
+	}
+	if kr.cleared != 1 {
+		t.Errorf("keyring cleared %d times, want the profile's tokens cleared once", kr.cleared)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != config.DefaultProfile {
+		t.Errorf("profile = %q after removing the current profile, want %q", cfg.Profile, config.DefaultProfile)
+	}
+
+	if _, err := executeCommand(newProfilesTestRoot(store), "profiles", "remove", "acme"); err == nil {
+		t.Error("removing an unknown profile succeeded, want an error")
+	}
+}
+
+// TestConfigure_ExplicitProfileKeepsCurrent pins that configuring another
+// tenant with GRANT_PROFILE writes that SDK profile without switching to it.
+//
+// Not parallel: sets GRANT_CONFIG, GRANT_PROFILE and IDSEC_PROFILES_FOLDER for
+// the process.
+func TestConfigure_ExplicitProfileKeepsCurrent(t *testing.T) {
+	cfgPath := writeProfileConfig(t, "grant")
+	t.Setenv("IDSEC_PROFILES_FOLDER", filepath.Join(t.TempDir(), "profiles"))
+	t.Setenv(profileEnvVar, "acme")
+
+	var saved *sdkmodels.IdsecProfile
+	saver := &mockProfileSaver{saveFunc: func(p *sdkmodels.IdsecProfile) error {
+		saved = p
+		return nil
+	}}
+	output, err := executeCommand(NewConfigureCommandWithDeps(saver, "https://acme.cyberark.cloud", "me@acme.com"))
+	if err != nil {
+		t.Fatalf("configure() error = %v", err)
+	}
+	if saved == nil || saved.ProfileName != "acme" {
+		t.Fatalf("saved profile = %+v, want profile %q", saved, "acme")
+	}
+	if !strings.Contains(output, "grant profiles use acme") {
+		t.Errorf("output missing the switch hint:\n%s", output)
+	}
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != "grant" {
+		t.Errorf("profile = %q, want the current profile %q kept", cfg.Profile, "grant")
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG and GRANT_PROFILE for the process.
+func TestFavorites_ScopedToProfile(t *testing.T) {
+	cfgPath := writeProfileConfig(t, "grant")
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Favorites = map[string]config.Favorite{
+		"legacy": {Provider: "azure", Target: "Old", Role: "Reader"},
+		"home":   {Provider: "azure", Target: "Home", Role: "Reader", Profile: "grant"},
+		"acme":   {Provider: "aws", Target: "Acme", Role: "Admin", Profile: "acme"},
+	}
+	if err := config.Save(cfg, cfgPath); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := executeCommand(NewFavoritesCommand(), "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "legacy") || !strings.Contains(output, "home") || strings.Contains(output, "Acme") {
+		t.Errorf("favorites list in profile grant:\n%s", output)
+	}
+
+	if err := checkFavoriteProfile(cfg, "acme", cfg.Favorites["acme"]); err == nil || !strings.Contains(err.Error(), "--profile acme") {
+		t.Errorf("checkFavoriteProfile(acme) = %v, want a --profile hint", err)
+	}
+
+	t.Setenv(profileEnvVar, "acme")
+	if err := checkFavoriteProfile(cfg, "acme", cfg.Favorites["acme"]); err != nil {
+		t.Errorf("checkFavoriteProfile(acme) in profile acme = %v", err)
+	}
+	lister := targetsLister(eligibleTarget(models.CSPAzure, "Acme", "Reader"))
+	if _, err := executeCommand(NewFavoritesCommandWithAllDeps(lister, nil, nil, nil), "add", "acme-ro", "--target", "Acme", "--role", "Reader"); err != nil {
+		t.Fatalf("favorites add: %v", err)
+	}
+	cfg, err = config.Load(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Favorites["acme-ro"].Profile; got != "acme" {
+		t.Errorf("new favorite profile = %q, want %q", got, "acme")
+	}
+}
+
+// TestProfilesRemove_ClearsSDKKeyring removes a profile with the production
+// keyring, the sandbox's SDK file keyring, and checks its token is gone.
+//
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestProfilesRemove_ClearsSDKKeyring(t *testing.T) {
+	writeProfileConfig(t, "grant")
+	kr, err := keyring.NewIdsecKeyring("grant").GetKeyring(true)
+	if err != nil {
+		t.Fatalf("GetKeyring() = %v", err)
+	}
+	if err := kr.SetPassword("grant-acme", "me@acme.com", "acme-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	store := newFakeProfileStore("grant", "acme")
+	if _, err := executeCommand(newProfilesTestRootWithKeyring(store, sdkKeyringClearer{}), "profiles", "remove", "acme"); err != nil {
+		t.Fatalf("remove error = %v", err)
+	}
+	if token, err := kr.GetPassword("grant-acme", "me@acme.com"); err == nil && token != "" {
+		t.Error("the removed profile's token survived in the keyring")
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestProfilesRemove_KeyringError(t *testing.T) {
+	writeProfileConfig(t, "grant")
+	store := newFakeProfileStore("grant", "acme")
+	kr := &mockKeyringClearer{clearErr: errors.New("keyring unavailable")}
+
+	_, err := executeCommand(newProfilesTestRootWithKeyring(store, kr), "profiles", "remove", "acme")
+	if err == nil || !strings.Contains(err.Error(), "keyring unavailable") {
+		t.Fatalf("remove error = %v, want the keyring error", err)
+	}
+	if len(store.deleted) != 0 {
+		t.Errorf("deleted = %v; a profile whose tokens were not cleared must be kept", store.deleted)
+	}
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+// defaultPromptFormat renders "aws:Prod/Admin 42m", or "azure:Cloud Admins" for
+// a group session with unknown remaining time.
+const defaultPromptFormat = `{{.Provider}}:{{.Target}}{{if .Role}}/{{.Role}}{{end}}{{if .Remaining}} {{.Remaining}}{{end}}`
+
+// promptInitSnippets are the shell integrations printed by 'grant prompt --init'.
+// Each swallows errors so a broken grant can never break the prompt.
+var promptInitSnippets = map[string]string{
+	"bash": `# grant prompt segment: add to ~/.bashrc
+#   eval "$(grant prompt --init bash)"
+__grant_ps1() {
+  local s
+  s="$(grant prompt 2>/dev/null)" && [ -n "$s" ] && printf '⚡ %s ' "$s"
+}
+PS1='$(__grant_ps1)'"$PS1"
+`,
+	"zsh": `# grant prompt segment: add to ~/.zshrc
+#   eval "$(grant prompt --init zsh)"
+setopt PROMPT_SUBST
+__grant_ps1() {
+  local s
+  s="$(grant prompt 2>/dev/null)" && [[ -n "$s" ]] && print -rn -- "⚡ $s "
+}
+PROMPT='$(__grant_ps1)'"$PROMPT"
+`,
+	"fish": `# grant prompt segment: add to ~/.config/fish/config.fish
+#   grant prompt --init fish | source
+function __grant_prompt
+    set -l s (grant prompt 2>/dev/null)
+    test -n "$s"; and printf '⚡ %s ' $s
+end
+if not functions -q __grant_orig_fish_prompt
+    functions -c fish_prompt __grant_orig_fish_prompt
+end
+function fish_prompt
+    __grant_prompt
+    __grant_orig_fish_prompt
+end
+`,
+	"starship": `# grant prompt segment: append to ~/.config/starship.toml
+#   grant prompt --init starship >> ~/.config/starship.toml
+[custom.grant]
+command = "grant prompt"
+when = true
+symbol = "⚡ "
+style = "bold yellow"
+format = "[$symbol$output]($style) "
+`,
+}
+
+// newPromptCommand creates the prompt cobra command with the given RunE function.
+func newPromptCommand(runFn func(*cobra.Command, []string) error) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print active sessions for a shell prompt (offline)",
+		Long: `Print a compact segment describing active sessions, for use in PS1 or a
+prompt framework.
+
+prompt never authenticates or calls the network: it reads only the session
+list last seen by 'grant status' or recorded at elevation, plus the locally
+recorded elevation timestamps. Sessions that must have ended are dropped; a
+session revoked or elevated elsewhere shows up after the next 'grant status'.
+Nothing is printed when there are no known sessions.
+
+--format is a Go template over each session with the fields .Provider,
+.Target, .Role, .Type, .SessionID, .Remaining ("42m"; "≤42m" when only an
+upper bound is known for a session elevated elsewhere; empty when unknown) and
+.UpperBound. Multiple sessions are joined with --separator.`,
+		Example: `  grant prompt
+  grant prompt --format '{{.Provider}}:{{.Target}} {{.Remaining}}'
+  eval "$(grant prompt --init bash)"`,
+		Args: cobra.NoArgs,
+		RunE: runFn,
+	}
+
+	cmd.Flags().String("format", defaultPromptFormat, "Go template rendered for each session")
+	cmd.Flags().String("separator", " | ", "text placed between sessions")
+	cmd.Flags().StringP("provider", "p", "", "only show sessions for this provider (azure, aws, gcp)")
+	cmd.Flags().String("init", "", "print a prompt snippet for a shell: bash, zsh, fish, starship")
+
+	return cmd
+}
+
+// NewPromptCommand creates the production prompt command.
+func NewPromptCommand() *cobra.Command {
+	return newPromptCommand(func(cmd *cobra.Command, args []string) error {
+		return runPrompt(cmd, loadKnownSessions(), loadSessionStarts(), time.Now())
+	})
+}
+
+func runPrompt(cmd *cobra.Command, known []cache.KnownSession, starts map[string]cache.SessionStart, now time.Time) error {
+	if shell, _ := cmd.Flags().GetString("init"); shell != "" {
+		snippet, ok := promptInitSnippets[strings.ToLower(shell)]
+		if !ok {
+			return fmt.Errorf("invalid --init %q: must be one of: bash, zsh, fish, starship", shell)
+		}
+		fmt.Fprint(cmd.OutOrStdout(), snippet)
+		return nil
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	tmpl, err := template.New("prompt").Parse(format)
+	if err == nil {
+		// Reject unknown fields now, not only once a session is known
+		err = tmpl.Execute(io.Discard, promptSessionOutput{})
+	}
+	if err != nil {
+		return fmt.Errorf("invalid --format: %w", err)
+	}
+
+	provider, _ := cmd.Flags().GetString("provider")
+	if provider != "" {
+		if _, err := parseProvider(provider); err != nil {
+			return err
+		}
+	}
+
+	sessions := buildPromptSessions(known, starts, provider, now)
+
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), sessions)
+	}
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	separator, _ := cmd.Flags().GetString("separator")
+	segments := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, s); err != nil {
+			return fmt.Errorf("invalid --format: %w", err)
+		}
+		segments = append(segments, buf.String())
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), strings.Join(segments, separator))
+	return nil
+}
+
+// buildPromptSessions turns the last-known session list into prompt rows,
+// computing remaining time where a start is tracked and dropping sessions
+// whose tracked start plus duration is already past. Remaining time counted
+// from a first observation is an upper bound and rendered as "≤42m".
+func buildPromptSessions(known []cache.KnownSession, starts map[string]cache.SessionStart, provider string, now time.Time) []promptSessionOutput {
+	out := make([]promptSessionOutput, 0, len(known))
+	for _, ks := range known {
+		if provider != "" && !strings.EqualFold(ks.Provider, provider) {
+			continue
+		}
+
+		ps := promptSessionOutput{
+			SessionID: ks.SessionID,
+			Provider:  ks.Provider,
+			Type:      ks.Type,
+			Target:    ks.Target,
+			Role:      ks.Role,
+		}
+		if start, ok := starts[ks.SessionID]; ok && ks.DurationSeconds > 0 {
+			remaining := start.At.Add(time.Duration(ks.DurationSeconds) * time.Second).Sub(now)
+			if remaining <= 0 {
+				continue
+			}
+			secs := int(remaining.Seconds())
+			ps.Remaining = formatPromptRemaining(remaining)
+			ps.RemainingSeconds = &secs
+			if !start.Exact {
+				ps.Remaining = "≤" + ps.Remaining
+				ps.UpperBound = true
+			}
+		}
+		out = append(out, ps)
+	}
+	return out
+}
+
+// formatPromptRemaining formats a remaining duration compactly: "42m", "1h05m".
+func formatPromptRemaining(d time.Duration) string {
+	totalMin := int(d.Minutes())
+	if totalMin >= 60 {
+		return fmt.Sprintf("%dh%02dm", totalMin/60, totalMin%60)
+	}
+	return fmt.Sprintf("%dm", totalMin)
+}
@@ -0,0 +1,169 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+	"github.com/spf13/cobra"
+)
+
+func promptFixture() ([]cache.KnownSession, map[string]cache.SessionStart, time.Time) {
+	now := time.Date(2026, 2, 21, 12, 0, 0, 0, time.UTC)
+	known := []cache.KnownSession{
+		{SessionID: "aws-1", Provider: "aws", Type: "cloud", Target: "Prod", Role: "Admin", DurationSeconds: 3600},
+		{SessionID: "grp-1", Provider: "azure", Type: "group", Target: "Cloud Admins", DurationSeconds: 7200},
+		{SessionID: "expired", Provider: "gcp", Type: "cloud", Target: "proj", Role: "Viewer", DurationSeconds: 1800},
+	}
+	starts := map[string]cache.SessionStart{
+		"aws-1":   {At: now.Add(-18 * time.Minute), Exact: true},
+		"grp-1":   {At: now.Add(-time.Hour)},
+		"expired": {At: now.Add(-time.Hour), Exact: true},
+	}
+	return known, starts, now
+}
+
+func newTestPromptCommand() *cobra.Command {
+	known, starts, now := promptFixture()
+	return newPromptCommand(func(cmd *cobra.Command, args []string) error {
+		return runPrompt(cmd, known, starts, now)
+	})
+}
+
+func TestPromptCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "default format",
+			want: "aws:Prod/Admin 42m | azure:Cloud Admins ≤1h00m\n",
+		},
+		{
+			name: "custom format and separator",
+			args: []string{"--format", "{{.Provider}}:{{.Target}} {{.Remaining}}", "--separator", ", "},
+			want: "aws:Prod 42m, azure:Cloud Admins ≤1h00m\n",
+		},
+		{
+			name: "provider filter",
+			args: []string{"--provider", "AWS"},
+			want: "aws:Prod/Admin 42m\n",
+		},
+		{
+			name: "no sessions prints nothing",
+			args: []string{"--provider", "gcp"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := executeCommand(newTestPromptCommand(), tt.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output != tt.want {
+				t.Errorf("output = %q, want %q", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptCommand_JSON(t *testing.T) {
+	cmd := newTestPromptCommand()
+	root := newTestRootCommand()
+	root.AddCommand(cmd)
+
+	output, err := executeCommand(root, "prompt", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed []promptSessionOutput
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+	if len(parsed) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(parsed))
+	}
+	if parsed[0].RemainingSeconds == nil || *parsed[0].RemainingSeconds != 42*60 {
+		t.Errorf("aws-1 remainingSeconds = %v, want 2520", parsed[0].RemainingSeconds)
+	}
+	if !parsed[1].UpperBound || parsed[1].RemainingSeconds == nil || *parsed[1].RemainingSeconds != 3600 {
+		t.Errorf("first-observed group session = %+v, want an upper bound of 3600s", parsed[1])
+	}
+}
+
+func TestPromptCommand_Init(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "starship"} {
+		t.Run(shell, func(t *testing.T) {
+			output, err := executeCommand(newTestPromptCommand(), "--init", shell)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(output, "grant prompt") {
+				t.Errorf("snippet does not invoke grant prompt:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestPromptCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"unknown shell", []string{"--init", "tcsh"}, `invalid --init "tcsh"`},
+		{"unparseable format", []string{"--format", "{{.Provider"}, "invalid --format"},
+		{"unknown field", []string{"--format", "{{.Account}}"}, "invalid --format"},
+		{"invalid provider", []string{"--provider", "oci"}, `invalid provider "oci"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeCommand(newTestPromptCommand(), tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatPromptRemaining(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{42*time.Minute + 30*time.Second, "42m"},
+		{65 * time.Minute, "1h05m"},
+		{30 * time.Second, "0m"},
+	}
+	for _, tt := range tests {
+		if got := formatPromptRemaining(tt.in); got != tt.want {
+			t.Errorf("formatPromptRemaining(%s) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStatusCommand_SavesKnownSessions(t *testing.T) {
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt", Username: "user@test.com"}}
+	f := requireFixture(t, auth)
+
+	if _, err := f.run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	known := cache.KnownSessions(f.tracker)
+	if len(known) != 2 {
+		t.Fatalf("expected 2 known sessions, got %+v", known)
+	}
+	if known[0].Target != "Prod" || known[0].Role != "Admin" || known[0].Provider != "aws" {
+		t.Errorf("known[0] = %+v, want resolved aws Prod/Admin", known[0])
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/aaearon/grant-cli/internal/config"
+	"github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+// providerAll is the --provider value that queries every supported provider,
+// overriding the providers config setting.
+const providerAll = "all"
+
+// isProviderFilter reports whether a --provider value narrows the query to a
+// single provider; "" and providerAll do not.
+func isProviderFilter(provider string) bool {
+	return provider != "" && provider != providerAll
+}
+
+// providerScope returns the providers queried when --provider does not name
+// one: every supported provider for providerAll, else the providers allow-list
+// of the effective config, else its default_provider, else every supported
+// provider.
+func providerScope(provider string) []models.CSP {
+	if provider == providerAll {
+		return supportedCSPs
+	}
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err != nil {
+		log.Info("failed to load providers setting, querying every provider: %v", err)
+		return supportedCSPs
+	}
+	if len(cfg.Providers) == 0 {
+		if cfg.DefaultProvider != "" {
+			return []models.CSP{models.CSP(strings.ToUpper(cfg.DefaultProvider))}
+		}
+		return supportedCSPs
+	}
+	csps := make([]models.CSP, len(cfg.Providers))
+	for i, p := range cfg.Providers {
+		csps[i] = models.CSP(strings.ToUpper(p))
+	}
+	return csps
+}
+
+// groupsInScope reports whether the providers queried for provider include
+// azure, the only provider with group eligibility.
+func groupsInScope(provider string) bool {
+	return slices.Contains(providerScope(provider), models.CSPAzure)
+}
@@ -63,6 +63,7 @@ func bootstrapWorkflowsService() (*workflows.AccessRequestService, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create access request service: %w", err)
 	}
+	svc.SetPageHook(emitPageFetched)
 
 	return svc, nil
 }
@@ -197,3 +198,13 @@ func toAccessRequestOutput(r *models.AccessRequest) accessRequestOutput {
 		UpdatedAt:          r.UpdatedAt,
 	}
 }
+
+// emitRequestState reports the state of a request grant just submitted,
+// canceled or finalized.
+func emitRequestState(r *models.AccessRequest) {
+	emitEvent(eventRequestStateChanged, requestStateEventData{
+		RequestID: r.RequestID,
+		State:     string(r.RequestState),
+		Result:    string(r.RequestResult),
+	})
+}
@@ -61,9 +61,10 @@ func runRequestCancel(cmd *cobra.Command, requestID string, svc accessRequestSer
 	if err != nil {
 		return fmt.Errorf("failed to cancel request: %w", err)
 	}
+	emitRequestState(result)
 
-	if isJSONOutput() {
-		return writeJSON(cmd.OutOrStdout(), toAccessRequestOutput(result))
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), toAccessRequestOutput(result))
 	}
 
 	fmt.Fprintf(cmd.OutOrStdout(), "Request %s canceled.\n", result.RequestID)
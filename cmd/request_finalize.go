@@ -101,9 +101,10 @@ func runFinalize(cmd *cobra.Command, requestID, decision string, svc accessReque
 	if err != nil {
 		return fmt.Errorf("failed to %s request: %w", decisionVerb(decision), err)
 	}
+	emitRequestState(result)
 
-	if isJSONOutput() {
-		return writeJSON(cmd.OutOrStdout(), toAccessRequestOutput(result))
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), toAccessRequestOutput(result))
 	}
 
 	fmt.Fprintf(cmd.OutOrStdout(), "Request %s %s.\n", result.RequestID, decisionPastTense(decision))
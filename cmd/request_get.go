@@ -55,8 +55,8 @@ func runRequestGet(cmd *cobra.Command, requestID string, svc accessRequestServic
 		return fmt.Errorf("failed to get request: %w", err)
 	}
 
-	if isJSONOutput() {
-		return writeJSON(cmd.OutOrStdout(), toAccessRequestOutput(result))
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), toAccessRequestOutput(result))
 	}
 
 	formatRequestDetail(cmd, result)
@@ -107,12 +107,12 @@ func runRequestList(cmd *cobra.Command, svc accessRequestService) error {
 		return fmt.Errorf("failed to list requests: %w", err)
 	}
 
-	if isJSONOutput() {
+	if isStructuredOutput() {
 		outputs := make([]accessRequestOutput, len(items))
 		for i := range items {
 			outputs[i] = toAccessRequestOutput(&items[i])
 		}
-		return writeJSON(cmd.OutOrStdout(), accessRequestListOutput{
+		return writeOutput(cmd.OutOrStdout(), accessRequestListOutput{
 			Requests:   outputs,
 			TotalCount: totalCount,
 		})
@@ -37,6 +37,7 @@ func newRequestSubmitCommand(svc accessRequestService) *cobra.Command {
 
 	cmd.Flags().StringP("provider", "p", "", "Cloud provider: azure, aws")
 	cmd.Flags().StringP("target", "t", "", "Target workspace name")
+	cmd.Flags().StringP("favorite", "f", "", "Use a cloud favorite's provider and target, and its default reason")
 	cmd.Flags().String("role-id", "", "Role ID to request access for (required)")
 	cmd.Flags().StringP("role", "r", "", "Role name (display only)")
 	cmd.Flags().String("reason", "", "Reason for the request (required)")
@@ -214,6 +215,10 @@ func confirmSubmit() (bool, error) {
 }
 
 func runRequestSubmit(cmd *cobra.Command, svc accessRequestService) error {
+	if err := applySubmitFavorite(cmd); err != nil {
+		return err
+	}
+
 	provider, _ := cmd.Flags().GetString("provider")
 	if provider != "" {
 		csp, err := parseProvider(provider)
@@ -276,7 +281,7 @@ func runRequestSubmit(cmd *cobra.Command, svc accessRequestService) error {
 	}
 
 	// Summary before submission
-	if !isJSONOutput() {
+	if !isStructuredOutput() {
 		fmt.Fprintf(cmd.ErrOrStderr(), "\nWorkspace: %s\n", workspace.WorkspaceName)
 		fmt.Fprintf(cmd.ErrOrStderr(), "Role:      %s (ID: %s)\n", roleName, roleID)
 		fmt.Fprintf(cmd.ErrOrStderr(), "Date:      %s\n", fields.date)
@@ -287,7 +292,7 @@ func runRequestSubmit(cmd *cobra.Command, svc accessRequestService) error {
 
 	// Confirmation
 	yesFlag, _ := cmd.Flags().GetBool("yes")
-	if !yesFlag && !isJSONOutput() {
+	if !yesFlag && !isStructuredOutput() {
 		confirmed, confirmErr := confirmSubmitFn()
 		if confirmErr != nil {
 			return confirmErr
@@ -309,9 +314,10 @@ func runRequestSubmit(cmd *cobra.Command, svc accessRequestService) error {
 	if err != nil {
 		return fmt.Errorf("failed to submit request: %w", err)
 	}
+	emitRequestState(result)
 
-	if isJSONOutput() {
-		return writeJSON(cmd.OutOrStdout(), toAccessRequestOutput(result))
+	if isStructuredOutput() {
+		return writeOutput(cmd.OutOrStdout(), toAccessRequestOutput(result))
 	}
 
 	fmt.Fprintf(cmd.OutOrStdout(), "Access request submitted successfully.\n")
@@ -323,6 +329,40 @@ func runRequestSubmit(cmd *cobra.Command, svc accessRequestService) error {
 	return nil
 }
 
+// applySubmitFavorite fills --provider, --target and --reason from the
+// --favorite, where they were not given. The role is still picked from the
+// on-demand roles or given with --role-id: a favorite holds the role name of
+// an eligible target, not the ID of an on-demand role.
+func applySubmitFavorite(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("favorite")
+	if name == "" {
+		return nil
+	}
+	cfg, _, err := config.LoadDefaultWithPath()
+	if err != nil {
+		return err
+	}
+	fav, err := config.GetFavorite(cfg, name)
+	if err != nil {
+		return withCode(codeTargetNotFound, fmt.Errorf("favorite %q not found, run 'grant favorites list'", name))
+	}
+	if err := checkFavoriteProfile(cfg, name, fav); err != nil {
+		return withCode(codeTargetNotFound, err)
+	}
+	if fav.ResolvedType() == config.FavoriteTypeGroups {
+		return usageErrorf("favorite %q is a group favorite; access requests are for cloud targets", name)
+	}
+
+	for flag, value := range map[string]string{"provider": fav.Provider, "target": fav.Target, "reason": fav.DefaultReason} {
+		if value != "" && !cmd.Flags().Changed(flag) {
+			if err := cmd.Flags().Set(flag, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func resolveSubmitFields(cmd *cobra.Command) (*submitFields, error) {
 	f := &submitFields{}
 	f.reason, _ = cmd.Flags().GetString("reason")
@@ -548,11 +588,11 @@ func buildRequestDetails(ws *submitWorkspace, roleID, roleName string, f *submit
 
 // buildCachedRolesLister wraps an on-demand roles lister in the file cache.
 // It mirrors buildCachedLister: an unresolvable cache directory falls back to
-// the unwrapped service, and an invalid cache_ttl is an error. config.Load
+// the unwrapped service, and an invalid cache setting is an error. config.Load
 // already rejects a bad value, so that arm is reachable only for a Config
 // assembled in memory.
 func buildCachedRolesLister(cfg *config.Config, refresh bool, inner cache.OnDemandRolesLister) (cache.OnDemandRolesLister, error) {
-	cacheDir, err := cache.CacheDir()
+	dir, err := cacheDir()
 	if err != nil {
 		return inner, nil
 	}
@@ -560,8 +600,16 @@ func buildCachedRolesLister(cfg *config.Config, refresh bool, inner cache.OnDema
 	if err != nil {
 		return nil, err
 	}
-	store := cache.NewStore(cacheDir, ttl)
-	return cache.NewCachedRolesLister(inner, store, refresh, common.GetLogger("grant", -1)), nil
+	store := newCacheStore(dir, ttl)
+	if err := applyCachePolicies(cfg, store); err != nil {
+		return nil, err
+	}
+	lister := cache.NewCachedRolesLister(inner, store, refresh, common.GetLogger("grant", -1))
+	lister.OnLookup(cacheLookupHook(store))
+	if err := enableStaleFallback(cfg, store, lister); err != nil {
+		return nil, err
+	}
+	return lister, nil
 }
 
 // resolveSubmitRole fetches on-demand roles for the selected workspace and
@@ -544,6 +544,59 @@ func TestRunRequestSubmit_NonInteractive(t *testing.T) {
 	}
 }
 
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestRunRequestSubmit_Favorite(t *testing.T) {
+	writeConfigFile(t, "favorites:\n"+
+		"  prod:\n    provider: azure\n    target: Test Sub\n    role: Contributor\n    default_reason: on-call\n"+
+		"  ops:\n    type: groups\n    group: Ops\n")
+	original := resolveSubmitTargetFn
+	defer func() { resolveSubmitTargetFn = original }()
+
+	var gotProvider, gotTarget string
+	resolveSubmitTargetFn = func(_ context.Context, provider, target string, _ bool) (*submitWorkspace, error) {
+		gotProvider, gotTarget = provider, target
+		return &submitWorkspace{WorkspaceName: target, WorkspaceID: "ws-1", CSP: models.CSPAzure, OrganizationID: "org-1"}, nil
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantReason string
+		wantErr    string
+	}{
+		{name: "default reason", args: []string{"--favorite", "prod"}, wantReason: "on-call"},
+		{name: "reason flag wins", args: []string{"--favorite", "prod", "--reason", "incident"}, wantReason: "incident"},
+		{name: "group favorite", args: []string{"--favorite", "ops"}, wantErr: "is a group favorite"},
+		{name: "unknown favorite", args: []string{"--favorite", "missing"}, wantErr: `favorite "missing" not found`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockAccessRequestService{submitResult: &wfmodels.AccessRequest{RequestID: "req-new"}}
+			root := newTestRootCommand()
+			root.AddCommand(NewRequestCommandWithDeps(svc))
+
+			args := append([]string{"request", "submit", "--role-id", "role-1", "--date", "2026-04-21",
+				"--timezone", "UTC", "--from", "09:00", "--to", "17:00", "--yes"}, tt.args...)
+			_, err := executeCommand(root, args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("submit = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("submit: %v", err)
+			}
+			if gotProvider != "azure" || gotTarget != "Test Sub" {
+				t.Errorf("target = %s/%s, want the favorite's", gotProvider, gotTarget)
+			}
+			if len(svc.submitCalls) != 1 || svc.submitCalls[0].RequestDetails["reason"] != tt.wantReason {
+				t.Errorf("submitted %+v, want reason %q", svc.submitCalls, tt.wantReason)
+			}
+		})
+	}
+}
+
 func TestRunRequestSubmit_JSONOutput(t *testing.T) {
 	original := resolveSubmitTargetFn
 	defer func() { resolveSubmitTargetFn = original }()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/aaearon/grant-cli/internal/config"
 	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
@@ -52,7 +53,25 @@ Three execution modes:
 2. All mode: grant revoke --all [--provider azure]
 3. Interactive mode: grant revoke (multi-select prompt)
 
+In all and interactive mode the listed sessions can be narrowed with
+--target, --role, --group, --older-than, --mine-only and --except. Age is
+only known for sessions elevated from this machine, so --older-than never
+matches a session grant did not record. --dry-run prints the sessions that
+would be revoked and exits without revoking anything.
+
+--wait polls the session list after revoking until every accepted session is
+gone, and exits non-zero if any is still active when the timeout expires
+(default 5m; set another with --wait=10m).
+
 Use 'grant status' to view session IDs.`,
+		Example: `  # Revoke all AWS sessions except one
+  grant revoke --all --provider aws --except <session-id> --yes
+
+  # Preview revoking sessions elevated from this machine over 2 hours ago
+  grant revoke --all --mine-only --older-than 2h --dry-run
+
+  # Block until every session is confirmed gone
+  grant revoke --all --yes --wait && echo safe`,
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		RunE:          runFn,
@@ -61,6 +80,15 @@ Use 'grant status' to view session IDs.`,
 	cmd.Flags().BoolP("all", "a", false, "revoke all active sessions")
 	cmd.Flags().BoolP("yes", "y", false, "skip confirmation prompt")
 	cmd.Flags().StringP("provider", "p", "", "filter sessions by provider (azure, aws, gcp)")
+	cmd.Flags().StringP("target", "t", "", "filter sessions by target name or workspace ID")
+	cmd.Flags().StringP("role", "r", "", "filter sessions by role name")
+	cmd.Flags().StringP("group", "g", "", "filter sessions by Entra ID group name or ID")
+	cmd.Flags().String("older-than", "", "only sessions elevated from this machine longer ago than this (e.g. 2h)")
+	cmd.Flags().Bool("mine-only", false, "only sessions elevated from this machine")
+	cmd.Flags().StringSlice("except", nil, "session ID to keep (repeatable)")
+	cmd.Flags().Bool("dry-run", false, "show the sessions that would be revoked without revoking them")
+	cmd.Flags().Duration("wait", 0, "wait until revoked sessions are gone, up to this long (--wait=10m)")
+	cmd.Flags().Lookup("wait").NoOptDefVal = defaultRevokeWait.String()
 
 	return cmd
 }
@@ -78,12 +106,12 @@ func NewRevokeCommand() *cobra.Command {
 			return err
 		}
 
-		cachedLister, err := buildCachedLister(cfg, false, svc, nil)
+		cachedLister, err := buildCachedLister(cfg, false, svc, svc)
 		if err != nil {
 			return err
 		}
 
-		return runRevoke(cmd, args, ispAuth, svc, cachedLister, svc, &uiSessionSelector{}, &uiConfirmPrompter{}, profile)
+		return runRevoke(cmd, args, ispAuth, withSessionObservation(svc), cachedLister, svc, &uiSessionSelector{}, &uiConfirmPrompter{}, profile)
 	})
 }
 
@@ -115,13 +143,32 @@ func runRevoke(
 	allFlag, _ := cmd.Flags().GetBool("all")
 	yesFlag, _ := cmd.Flags().GetBool("yes")
 	provider, _ := cmd.Flags().GetString("provider")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	wait, _ := cmd.Flags().GetDuration("wait")
+
+	filter, err := parseRevokeFilter(cmd)
+	if err != nil {
+		return withCode(codeUsage, err)
+	}
 
 	// Validate mutual exclusivity
 	if allFlag && len(args) > 0 {
-		return errors.New("--all cannot be used with session ID arguments")
+		return usageErrorf("--all cannot be used with session ID arguments")
 	}
 	if len(args) > 0 && provider != "" {
-		return errors.New("--provider cannot be used with session ID arguments")
+		return usageErrorf("--provider cannot be used with session ID arguments")
+	}
+	if len(args) > 0 && filter.active() {
+		return usageErrorf("session filters cannot be used with session ID arguments")
+	}
+	if len(args) > 0 && dryRun {
+		return usageErrorf("--dry-run cannot be used with session ID arguments")
+	}
+	if dryRun && cmd.Flags().Changed("wait") {
+		return usageErrorf("--wait cannot be used with --dry-run")
+	}
+	if wait < 0 {
+		return usageErrorf("invalid --wait %s: must not be negative", wait)
 	}
 
 	// Validate provider
@@ -135,13 +182,12 @@ func runRevoke(
 	}
 
 	// Check authentication
-	_, err := auth.LoadAuthentication(profile, true)
-	if err != nil {
-		return fmt.Errorf("not authenticated, run 'grant login' first: %w", err)
+	if _, err := auth.LoadAuthentication(profile, true); err != nil {
+		return notAuthenticatedError(err)
 	}
 
 	// Determine which sessions to revoke.
-	sessionIDs, done, err := resolveRevokeTargets(cmd, args, lister, elig, selector, confirmer, cspFilter, allFlag, yesFlag)
+	sessionIDs, done, err := resolveRevokeTargets(cmd, args, lister, elig, selector, confirmer, cspFilter, filter, allFlag, yesFlag, dryRun)
 	if err != nil || done {
 		return err
 	}
@@ -155,8 +201,19 @@ func runRevoke(
 
 	records, unattached := reconcileRevocations(sessionIDs, results)
 
-	if isJSONOutput() {
-		if err := writeJSON(cmd.OutOrStdout(), buildRevocationJSON(records, unattached)); err != nil {
+	// Waiting after a failed batch would only delay the error: the sessions of
+	// the unsent batches were never asked to go away.
+	if wait > 0 && revokeErr == nil {
+		if !isStructuredOutput() {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Waiting up to %s for revoked sessions to disappear...\n", wait)
+		}
+		waitForRevocations(context.Background(), lister, cspFilter, records, wait)
+	}
+
+	forgetSessions(acceptedSessionIDs(records))
+
+	if isStructuredOutput() {
+		if err := writeOutput(cmd.OutOrStdout(), buildRevocationJSON(records, unattached)); err != nil {
 			return err
 		}
 	} else {
@@ -169,15 +226,20 @@ func runRevoke(
 		return revokeErr
 	}
 
-	if summary := summarizeRevocations(records); !summary.allAccepted() {
+	summary := summarizeRevocations(records)
+	if !summary.allAccepted() {
 		return fmt.Errorf("%w: %s", errRevocationIncomplete, summaryLine(summary))
 	}
+	if summary.stillPresent > 0 {
+		return fmt.Errorf("%w: %s", errRevocationNotConfirmed, summaryLine(summary))
+	}
 
 	return nil
 }
 
 // resolveRevokeTargets determines the session IDs to revoke. done reports that
-// the command has already finished (nothing to revoke, or the user declined).
+// the command has already finished (nothing to revoke, the user declined, or
+// a dry run printed its preview).
 func resolveRevokeTargets(
 	cmd *cobra.Command,
 	args []string,
@@ -186,7 +248,8 @@ func resolveRevokeTargets(
 	selector sessionSelector,
 	confirmer confirmPrompter,
 	cspFilter *scamodels.CSP,
-	allFlag, yesFlag bool,
+	filter *sessionFilter,
+	allFlag, yesFlag, dryRun bool,
 ) (sessionIDs []string, done bool, err error) {
 	if len(args) > 0 {
 		// Direct mode: session IDs provided as arguments.
@@ -203,14 +266,36 @@ func resolveRevokeTargets(
 	}
 
 	if len(sessions.Response) == 0 {
-		fmt.Fprintln(cmd.OutOrStdout(), "No active sessions to revoke.")
-		return nil, true, nil
+		return nil, true, revokeNothing(cmd, "No active sessions to revoke.", dryRun)
 	}
 
-	selected := sessions.Response
+	// Names are resolved only when something reads them: the selector, a name
+	// filter, or the dry-run preview.
+	fc := sessionFilterContext{now: time.Now()}
+	if !allFlag || dryRun || filter.target != "" {
+		fc.nameMap = buildWorkspaceNameMap(ctx, elig, sessions.Response)
+	}
+	if filter.group != "" || dryRun {
+		if gl, ok := elig.(groupsEligibilityLister); ok {
+			fc.groupNameMap = buildGroupNameMap(ctx, gl)
+		}
+	}
+	if filter.needsTimestamps() {
+		fc.timestamps = loadSessionTimestamps()
+	}
+
+	candidates := filterSessions(sessions.Response, filter, fc)
+	if len(candidates) == 0 {
+		return nil, true, revokeNothing(cmd, "No active sessions match the filters.", dryRun)
+	}
+
+	if dryRun {
+		return nil, true, renderRevokeDryRun(cmd, candidates, fc)
+	}
+
+	selected := candidates
 	if !allFlag {
-		nameMap := buildWorkspaceNameMap(ctx, elig, sessions.Response)
-		selected, err = selector.SelectSessions(sessions.Response, nameMap)
+		selected, err = selector.SelectSessions(candidates, fc.nameMap)
 		if err != nil {
 			return nil, true, fmt.Errorf("session selection failed: %w", err)
 		}
@@ -218,8 +303,7 @@ func resolveRevokeTargets(
 		// confirmation. Handled here rather than after the request is built, so
 		// an empty selection can never be revoked as if it were a request.
 		if len(selected) == 0 {
-			fmt.Fprintln(cmd.OutOrStdout(), "No sessions selected.")
-			return nil, true, nil
+			return nil, true, revokeNothing(cmd, "No sessions selected.", dryRun)
 		}
 	}
 
@@ -233,10 +317,41 @@ func resolveRevokeTargets(
 			return nil, true, fmt.Errorf("confirmation failed: %w", cerr)
 		}
 		if !confirmed {
-			fmt.Fprintln(cmd.OutOrStdout(), "Revocation canceled.")
-			return nil, true, nil
+			return nil, true, revokeNothing(cmd, "Revocation canceled.", dryRun)
 		}
 	}
 
 	return sessionIDs, false, nil
 }
+
+// revokeNothing ends a revoke that has no sessions to send. Text output says
+// why; a structured format gets an empty document instead, so stdout stays
+// parseable and an ndjson stream still ends with its result event.
+func revokeNothing(cmd *cobra.Command, reason string, dryRun bool) error {
+	if !isStructuredOutput() {
+		fmt.Fprintln(cmd.OutOrStdout(), reason)
+		return nil
+	}
+	if dryRun {
+		return writeOutput(cmd.OutOrStdout(), []sessionOutput{})
+	}
+	return writeOutput(cmd.OutOrStdout(), []revocationOutput{})
+}
+
+// renderRevokeDryRun prints the sessions a revoke would target. With
+// --output json it emits the same per-session shape as 'grant status'.
+func renderRevokeDryRun(cmd *cobra.Command, sessions []scamodels.SessionInfo, fc sessionFilterContext) error {
+	if isStructuredOutput() {
+		out := make([]sessionOutput, 0, len(sessions))
+		for _, s := range sessions {
+			out = append(out, buildSessionOutput(s, fc.nameMap, fc.groupNameMap, nil, nil))
+		}
+		return writeOutput(cmd.OutOrStdout(), out)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Would revoke %d %s:\n", len(sessions), plural(len(sessions), "session", "sessions"))
+	for _, s := range sessions {
+		fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", ui.FormatSessionOption(s, fc.nameMap, fc.groupNameMap, nil, nil))
+	}
+	return nil
+}
@@ -32,16 +32,25 @@ func chunkSessionIDs(ids []string, size int) [][]string {
 func revokeInBatches(ctx context.Context, revoker sessionRevoker, ids []string) ([]scamodels.RevocationResult, error) {
 	var results []scamodels.RevocationResult
 
-	for _, chunk := range chunkSessionIDs(ids, scamodels.MaxRevokeBatchSize) {
+	chunks := chunkSessionIDs(ids, scamodels.MaxRevokeBatchSize)
+	for i, chunk := range chunks {
 		batchCtx, cancel := context.WithTimeout(ctx, apiTimeout)
 		resp, err := revoker.RevokeSessions(batchCtx, &scamodels.RevokeRequest{SessionIDs: chunk})
 		cancel()
+
+		ev := revokeBatchEventData{Batch: i + 1, Batches: len(chunks), SessionIDs: chunk}
 		if err != nil {
+			ev.Error = err.Error()
+			emitEvent(eventRevokeBatch, ev)
 			return results, err
 		}
 		if resp != nil {
 			results = append(results, resp.Response...)
+			for _, r := range resp.Response {
+				ev.Results = append(ev.Results, revokeBatchEventItem{SessionID: r.SessionID, Status: r.RevocationStatus})
+			}
 		}
+		emitEvent(eventRevokeBatch, ev)
 	}
 
 	return results, nil
@@ -114,11 +114,14 @@ func reconcileRevocations(requested []string, results []scamodels.RevocationResu
 }
 
 // revocationSummary counts outcomes over the requested session set.
+// stillPresent counts accepted revocations that --wait found still listed;
+// they are accepted, so they are not failures for allAccepted.
 type revocationSummary struct {
-	requested  int
-	revoked    int
-	inProgress int
-	failed     int
+	requested    int
+	revoked      int
+	inProgress   int
+	stillPresent int
+	failed       int
 }
 
 // allAccepted reports whether every requested session was accepted by the
@@ -133,13 +136,28 @@ func summarizeRevocations(records []revocationRecord) revocationSummary {
 	s := revocationSummary{requested: len(records)}
 	for _, r := range records {
 		switch r.Outcome {
-		case scamodels.OutcomeRevoked:
+		case scamodels.OutcomeRevoked, scamodels.OutcomeConfirmedGone:
 			s.revoked++
 		case scamodels.OutcomeInProgress:
 			s.inProgress++
+		case scamodels.OutcomeStillPresent:
+			s.stillPresent++
 		default:
 			s.failed++
 		}
 	}
 	return s
 }
+
+// acceptedSessionIDs returns the requested sessions the service accepted for
+// revocation and that were not seen still active afterwards.
+func acceptedSessionIDs(records []revocationRecord) []string {
+	var ids []string
+	for _, r := range records {
+		switch r.Outcome {
+		case scamodels.OutcomeRevoked, scamodels.OutcomeInProgress, scamodels.OutcomeConfirmedGone:
+			ids = append(ids, r.SessionID)
+		}
+	}
+	return ids
+}
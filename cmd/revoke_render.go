@@ -12,6 +12,10 @@ func describeRecord(r revocationRecord) string {
 	switch r.Outcome {
 	case scamodels.OutcomeRevoked:
 		return fmt.Sprintf("revoked (%s)", r.Status)
+	case scamodels.OutcomeConfirmedGone:
+		return fmt.Sprintf("revoked, confirmed gone (%s)", r.Status)
+	case scamodels.OutcomeStillPresent:
+		return fmt.Sprintf("STILL ACTIVE — %s (%s)", r.Reason, r.Status)
 	case scamodels.OutcomeInProgress:
 		return fmt.Sprintf("revocation in progress — %s (%s)", r.Reason, r.Status)
 	case scamodels.OutcomeNotApplicable:
@@ -29,6 +33,9 @@ func summaryLine(s revocationSummary) string {
 	if s.inProgress > 0 {
 		line += fmt.Sprintf("; %d %s in progress", s.inProgress, plural(s.inProgress, "revocation", "revocations"))
 	}
+	if s.stillPresent > 0 {
+		line += fmt.Sprintf("; %d still active after waiting", s.stillPresent)
+	}
 	if s.failed > 0 {
 		line += fmt.Sprintf("; %d not revoked", s.failed)
 	}
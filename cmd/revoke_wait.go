@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+// defaultRevokeWait is the --wait timeout when the flag is given without a value.
+const defaultRevokeWait = 5 * time.Minute
+
+// revokeWaitInterval is the pause between session-list polls while waiting.
+// Package-level var for test injection.
+var revokeWaitInterval = 5 * time.Second
+
+// errRevocationNotConfirmed is returned by --wait when at least one accepted
+// revocation was still listed as an active session when the timeout expired.
+var errRevocationNotConfirmed = errors.New("revoked sessions are still active")
+
+// awaitingConfirmation reports whether --wait should poll for a record: only
+// sessions the service accepted can be expected to disappear.
+func awaitingConfirmation(r revocationRecord) bool {
+	return r.Outcome == scamodels.OutcomeRevoked || r.Outcome == scamodels.OutcomeInProgress
+}
+
+// waitForRevocations polls the session list until every accepted revocation
+// in records is no longer listed, or timeout expires. Each accepted record is
+// upgraded in place to OutcomeConfirmedGone or OutcomeStillPresent; refused
+// and unknown records are left as they are.
+//
+// A failed poll is not evidence either way, so it is logged and retried. If
+// the last poll before the timeout failed, the remaining records are still
+// reported as still present: grant could not prove them gone.
+func waitForRevocations(
+	ctx context.Context,
+	lister sessionLister,
+	cspFilter *scamodels.CSP,
+	records []revocationRecord,
+	timeout time.Duration,
+) {
+	pending := make(map[string]int)
+	for i, r := range records {
+		if awaitingConfirmation(r) {
+			pending[r.SessionID] = i
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		lastErr = pollRevocations(waitCtx, lister, cspFilter, records, pending)
+		if len(pending) == 0 {
+			return
+		}
+
+		select {
+		case <-waitCtx.Done():
+			reason := "still listed as an active session when --wait timed out"
+			if lastErr != nil {
+				reason = "could not be confirmed gone before --wait timed out: " + lastErr.Error()
+			}
+			for _, i := range pending {
+				records[i].Outcome = scamodels.OutcomeStillPresent
+				records[i].Reason = reason
+			}
+			return
+		case <-time.After(revokeWaitInterval):
+		}
+	}
+}
+
+// pollRevocations lists sessions once and marks every pending record that is
+// no longer listed as confirmed gone, removing it from pending.
+func pollRevocations(
+	ctx context.Context,
+	lister sessionLister,
+	cspFilter *scamodels.CSP,
+	records []revocationRecord,
+	pending map[string]int,
+) error {
+	listCtx, cancel := context.WithTimeout(ctx, apiTimeout)
+	defer cancel()
+
+	sessions, err := lister.ListSessions(listCtx, cspFilter)
+	if err != nil {
+		log.Info("failed to list sessions while waiting for revocation: %v", err)
+		emitEvent(eventRevokePoll, revokePollEventData{Pending: len(pending), Error: err.Error()})
+		return err
+	}
+
+	live := make(map[string]bool)
+	if sessions != nil {
+		for _, s := range sessions.Response {
+			live[s.SessionID] = true
+		}
+	}
+	for id, i := range pending {
+		if !live[id] {
+			records[i].Outcome = scamodels.OutcomeConfirmedGone
+			records[i].Reason = ""
+			delete(pending, id)
+		}
+	}
+	emitEvent(eventRevokePoll, revokePollEventData{Pending: len(pending)})
+	return nil
+}
@@ -0,0 +1,178 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+)
+
+// withFastRevokeWait shortens the poll interval for the duration of the test.
+func withFastRevokeWait(t *testing.T) {
+	t.Helper()
+	orig := revokeWaitInterval
+	t.Cleanup(func() { revokeWaitInterval = orig })
+	revokeWaitInterval = time.Millisecond
+}
+
+// sequenceSessionLister returns one listing per call, repeating the last.
+type sequenceSessionLister struct {
+	listings [][]string
+	errs     []error
+	calls    int
+}
+
+func (l *sequenceSessionLister) ListSessions(_ context.Context, _ *scamodels.CSP) (*scamodels.SessionsResponse, error) {
+	i := min(l.calls, len(l.listings)-1)
+	l.calls++
+	if i < len(l.errs) && l.errs[i] != nil {
+		return nil, l.errs[i]
+	}
+	resp := &scamodels.SessionsResponse{}
+	for _, id := range l.listings[i] {
+		resp.Response = append(resp.Response, scamodels.SessionInfo{SessionID: id})
+	}
+	return resp, nil
+}
+
+func TestWaitForRevocations(t *testing.T) {
+	withFastRevokeWait(t)
+
+	records := func() []revocationRecord {
+		return []revocationRecord{
+			{SessionID: "s1", Status: scamodels.RevocationSuccessful, Outcome: scamodels.OutcomeRevoked},
+			{SessionID: "s2", Status: scamodels.RevocationInProgress, Outcome: scamodels.OutcomeInProgress},
+			{SessionID: "s3", Status: scamodels.RevocationNotApplicable, Outcome: scamodels.OutcomeNotApplicable},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		lister     *sequenceSessionLister
+		timeout    time.Duration
+		want       []scamodels.RevocationOutcome
+		wantReason string
+	}{
+		{
+			name:    "all accepted sessions disappear",
+			lister:  &sequenceSessionLister{listings: [][]string{{"s1", "s2", "s3"}, {"s2", "s3"}, {"s3"}}},
+			timeout: time.Minute,
+			want:    []scamodels.RevocationOutcome{scamodels.OutcomeConfirmedGone, scamodels.OutcomeConfirmedGone, scamodels.OutcomeNotApplicable},
+		},
+		{
+			name:       "timeout leaves a session present",
+			lister:     &sequenceSessionLister{listings: [][]string{{"s2"}}},
+			timeout:    20 * time.Millisecond,
+			want:       []scamodels.RevocationOutcome{scamodels.OutcomeConfirmedGone, scamodels.OutcomeStillPresent, scamodels.OutcomeNotApplicable},
+			wantReason: "still listed",
+		},
+		{
+			name:       "failing polls never confirm",
+			lister:     &sequenceSessionLister{listings: [][]string{nil}, errs: []error{errors.New("boom")}},
+			timeout:    20 * time.Millisecond,
+			want:       []scamodels.RevocationOutcome{scamodels.OutcomeStillPresent, scamodels.OutcomeStillPresent, scamodels.OutcomeNotApplicable},
+			wantReason: "could not be confirmed gone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := records()
+			waitForRevocations(context.Background(), tt.lister, nil, got, tt.timeout)
+
+			for i, want := range tt.want {
+				if got[i].Outcome != want {
+					t.Errorf("%s outcome = %q, want %q", got[i].SessionID, got[i].Outcome, want)
+				}
+				if want == scamodels.OutcomeStillPresent && !strings.Contains(got[i].Reason, tt.wantReason) {
+					t.Errorf("%s reason = %q, want it to contain %q", got[i].SessionID, got[i].Reason, tt.wantReason)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForRevocations_NothingAccepted(t *testing.T) {
+	lister := &sequenceSessionLister{listings: [][]string{{"s1"}}}
+	records := []revocationRecord{{SessionID: "s1", Outcome: scamodels.OutcomeUnknown}}
+
+	waitForRevocations(context.Background(), lister, nil, records, time.Minute)
+
+	if lister.calls != 0 {
+		t.Errorf("polled %d times with nothing to wait for", lister.calls)
+	}
+}
+
+func TestRevokeCommand_Wait(t *testing.T) {
+	withFastRevokeWait(t)
+
+	revoker := &mockSessionRevoker{response: &scamodels.RevokeResponse{
+		Response: []scamodels.RevocationResult{{SessionID: "s1", RevocationStatus: scamodels.RevocationInProgress}},
+	}}
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+
+	t.Run("confirmed gone exits zero", func(t *testing.T) {
+		lister := &sequenceSessionLister{listings: [][]string{{"s1"}, {}}}
+		cmd := NewRevokeCommandWithDeps(auth, lister, &mockEligibilityLister{}, revoker, &mockSessionSelector{}, &mockConfirmPrompter{})
+		root := newTestRootCommand()
+		root.AddCommand(cmd)
+
+		stdout, _, err := executeCommandStreams(root, "revoke", "s1", "--wait", "--output", "json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var parsed []revocationOutput
+		if err := json.Unmarshal([]byte(stdout), &parsed); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+		if parsed[0].Outcome != string(scamodels.OutcomeConfirmedGone) {
+			t.Errorf("outcome = %q, want confirmed_gone", parsed[0].Outcome)
+		}
+	})
+
+	t.Run("timeout exits non-zero", func(t *testing.T) {
+		lister := &sequenceSessionLister{listings: [][]string{{"s1"}}}
+		cmd := NewRevokeCommandWithDeps(auth, lister, &mockEligibilityLister{}, revoker, &mockSessionSelector{}, &mockConfirmPrompter{})
+
+		output, err := executeCommand(cmd, "s1", "--wait=20ms")
+		if !errors.Is(err, errRevocationNotConfirmed) {
+			t.Fatalf("error = %v, want errRevocationNotConfirmed", err)
+		}
+		for _, want := range []string{"STILL ACTIVE", "1 still active after waiting"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q\ngot:\n%s", want, output)
+			}
+		}
+	})
+}
+
+func TestRevokeCommand_WaitDefault(t *testing.T) {
+	cmd := newRevokeCommand(nil)
+	if err := cmd.ParseFlags([]string{"--wait"}); err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	got, _ := cmd.Flags().GetDuration("wait")
+	if got != defaultRevokeWait {
+		t.Errorf("--wait without a value = %s, want %s", got, defaultRevokeWait)
+	}
+}
+
+func TestRevokeCommand_NegativeWaitIsUsageError(t *testing.T) {
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+	cmd := NewRevokeCommandWithDeps(auth, &sequenceSessionLister{}, &mockEligibilityLister{}, &mockSessionRevoker{}, &mockSessionSelector{}, &mockConfirmPrompter{})
+
+	_, err := executeCommand(cmd, "s1", "--wait=-1m")
+	if err == nil || !strings.Contains(err.Error(), "must not be negative") {
+		t.Fatalf("error = %v, want a negative --wait error", err)
+	}
+	if got := classifyError(err, true).code; got != codeUsage {
+		t.Errorf("error code = %s, want %s", got, codeUsage)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
@@ -115,8 +116,22 @@ Examples:
 			} else {
 				sdkconfig.DisableVerboseLogging()
 			}
-			if outputFormat != "text" && outputFormat != "json" {
-				return fmt.Errorf("invalid output format %q: must be one of: text, json", outputFormat)
+			if v := os.Getenv(outputEnvVar); v != "" && !cmd.Flags().Changed("output") {
+				if err := validateOutputFormat(v); err != nil {
+					return withCode(codeUsage, fmt.Errorf("invalid %s: %w", outputEnvVar, err))
+				}
+				outputFormat = v
+			}
+			if err := validateOutputFormat(outputFormat); err != nil {
+				return withCode(codeUsage, err)
+			}
+			if name := explicitProfile(); name != "" {
+				if err := validateProfileName(name); err != nil {
+					return withCode(codeUsage, err)
+				}
+			}
+			if outputFormat == formatNDJSON {
+				startEvents(cmd.OutOrStdout())
 			}
 			return nil
 		},
@@ -124,8 +139,9 @@ Examples:
 	}
 
 	cmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose output")
-	cmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json")
-	cmd.Flags().StringP("provider", "p", "", "Cloud provider: azure, aws, gcp (omit to show all)")
+	cmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: "+validOutputFormats+"; $"+outputEnvVar+" overrides the default")
+	cmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Tenant profile to use (default: $"+profileEnvVar+", then the current profile)")
+	cmd.Flags().StringP("provider", "p", "", "Cloud provider: azure, aws, gcp, or all (default: the providers setting, else all)")
 	cmd.Flags().StringP("target", "t", "", "Target name (subscription, resource group, etc.)")
 	cmd.Flags().StringP("role", "r", "", "Role name")
 	cmd.Flags().StringP("favorite", "f", "", "Use a saved favorite (see 'grant favorites list')")
@@ -159,14 +175,18 @@ var (
 // Overridable for tests. refreshAuth=false lets the SDK reuse cached keyring
 // tokens while still valid; a full re-auth only happens on token expiry.
 var bootstrapImpl = func() (auth.IdsecAuth, *sdkmodels.IdsecProfile, error) {
+	name := activeProfile()
 	loader := profiles.DefaultProfilesLoader()
-	profile, err := (*loader).LoadProfile("grant")
+	profile, err := (*loader).LoadProfile(name)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to load profile: %w", err)
+		return nil, nil, withCode(codeNotAuthenticated, fmt.Errorf("failed to load profile: %w", err))
+	}
+	if profile == nil {
+		return nil, nil, withCode(codeNotAuthenticated, fmt.Errorf("profile %q is not configured, run 'grant configure --profile %s' first", name, name))
 	}
 	ispAuth := auth.NewIdsecISPAuth(true)
 	if _, err := ispAuth.Authenticate(profile, nil, &authmodels.IdsecSecret{Secret: ""}, false, false); err != nil {
-		return nil, nil, fmt.Errorf("authentication failed: %w", err)
+		return nil, nil, withCode(codeNotAuthenticated, fmt.Errorf("authentication failed: %w", err))
 	}
 	return ispAuth, profile, nil
 }
@@ -201,6 +221,7 @@ func bootstrapSCAService() (auth.IdsecAuth, *sca.SCAAccessService, *sdkmodels.Id
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to create SCA service: %w", err)
 	}
+	svc.SetPageHook(emitPageFetched)
 
 	return ispAuth, svc, profile, nil
 }
@@ -246,7 +267,7 @@ func runElevateProduction(cmd *cobra.Command, args []string) error {
 // this with a bad value means the config was built in memory, not read from disk.
 func buildCachedLister(cfg *config.Config, refresh bool, cloudInner cache.EligibilityLister, groupsInner cache.GroupsEligibilityLister) (*cache.CachedEligibilityLister, error) {
 	cacheLog := common.GetLogger("grant", -1)
-	cacheDir, err := cache.CacheDir()
+	dir, err := cacheDir()
 	if err != nil {
 		return cache.NewCachedEligibilityLister(cloudInner, groupsInner, cache.NewStore("", 0), true, nil), nil
 	}
@@ -254,8 +275,100 @@ func buildCachedLister(cfg *config.Config, refresh bool, cloudInner cache.Eligib
 	if err != nil {
 		return nil, err
 	}
-	store := cache.NewStore(cacheDir, ttl)
-	return cache.NewCachedEligibilityLister(cloudInner, groupsInner, store, refresh, cacheLog), nil
+	store := newCacheStore(dir, ttl)
+	if err := applyCachePolicies(cfg, store); err != nil {
+		return nil, err
+	}
+	lister := cache.NewCachedEligibilityLister(cloudInner, groupsInner, store, refresh, cacheLog)
+	lister.OnLookup(cacheLookupHook(store))
+	if err := enableStaleFallback(cfg, store, lister); err != nil {
+		return nil, err
+	}
+	return lister, nil
+}
+
+// staleServer is a cache decorator that can fall back to expired entries.
+type staleServer interface {
+	ServeStale(maxStale time.Duration, hook cache.StaleHook)
+	RefreshInBackground(r *cache.Revalidator)
+}
+
+// staleRefreshes runs the background refreshes of entries served stale while
+// the API is healthy; Execute waits up to staleRefreshWait for them before the
+// process exits.
+var staleRefreshes = cache.NewRevalidator(apiTimeout)
+
+// staleRefreshWait bounds how long a finished command waits for background
+// refreshes, so serving stale data stays fast. A refresh still running then is
+// abandoned, and the next command serves the entry stale and refreshes again.
+const staleRefreshWait = 2 * time.Second
+
+// enableStaleFallback lets lister answer from entries up to cache_max_stale
+// past their TTL: at once while staleRefreshes fetches a replacement, or with
+// a warning when the API is unreachable. A kind's max_stale in the cache:
+// block overrides cache_max_stale. Each stale answer is counted in store's
+// statistics.
+func enableStaleFallback(cfg *config.Config, store *cache.Store, lister staleServer) error {
+	maxStale, err := config.ParseCacheMaxStale(cfg)
+	if err != nil {
+		return err
+	}
+	lister.ServeStale(maxStale, func(key string, age time.Duration, err error) {
+		cacheStats.RecordStale(store, key)
+		warnStaleCache(key, age, err)
+	})
+	lister.RefreshInBackground(staleRefreshes)
+	return nil
+}
+
+// cacheStats counts cache lookups for 'grant cache stats' in memory; Execute
+// flushes them once, before the process exits.
+var cacheStats = cache.NewStatsRecorder()
+
+// cacheLookupHook counts every lookup in store's statistics for
+// 'grant cache stats', and reports it as an event under --output ndjson.
+func cacheLookupHook(store *cache.Store) cache.LookupHook {
+	return func(key string, hit bool) {
+		cacheStats.RecordLookup(store, key, hit)
+		emitCacheLookup(key, hit)
+	}
+}
+
+// staleWarnings receives stale-cache warnings; stdout stays the command's
+// output. Package-level var for test injection.
+var (
+	staleWarnings   io.Writer  = os.Stderr
+	staleWarningsMu sync.Mutex // eligibility is fetched for every CSP at once
+)
+
+// warnStaleCache tells the user that data is being served from an expired
+// cache entry because the API call failed. An entry served while it is
+// refreshed in the background (err is nil) is only logged and reported as an
+// event: the next command reads fresh data.
+func warnStaleCache(key string, age time.Duration, err error) {
+	if err == nil {
+		log.Info("Using cached data from %s ago (%s) while refreshing it in the background", formatAge(age), key)
+		emitEvent(eventCacheStale, cacheEventData{Key: key, AgeSeconds: int(age.Seconds())})
+		return
+	}
+	staleWarningsMu.Lock()
+	fmt.Fprintf(staleWarnings, "Warning: SCA API unavailable, using cached data from %s ago (%s): %v\n", formatAge(age), key, err)
+	staleWarningsMu.Unlock()
+	emitEvent(eventCacheStale, cacheEventData{Key: key, AgeSeconds: int(age.Seconds()), Error: err.Error()})
+}
+
+// formatAge formats an age coarsely, as in "data from 6h ago".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "less than a minute"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
 }
 
 // NewRootCommandWithDeps creates a root command with injected dependencies for testing.
@@ -304,36 +417,66 @@ func shouldShowVerboseHint(verboseOn, argValidationPassed bool) bool {
 
 func Execute() {
 	passedArgValidation = false
-	if err := executeWithKeyringOverride(rootCmd); err != nil {
-		fmt.Fprintln(rootCmd.ErrOrStderr(), err)
-		if shouldShowVerboseHint(verbose, passedArgValidation) {
-			fmt.Fprintln(rootCmd.ErrOrStderr(), "Hint: re-run with --verbose for more details")
+	err := executeWithKeyringOverride(rootCmd)
+	var classified *cliError
+	if err != nil {
+		classified = classifyError(err, passedArgValidation)
+		reportError(rootCmd.ErrOrStderr(), classified, shouldShowVerboseHint(verbose, passedArgValidation))
+	}
+	finishStaleRefreshes()
+	if err := cacheStats.Flush(); err != nil {
+		log.Info("failed to record cache statistics: %v", err)
+	}
+	if classified != nil {
+		os.Exit(classified.exitCode())
+	}
+}
+
+// finishStaleRefreshes waits up to staleRefreshWait for the background
+// refreshes of entries served stale, so the next command reads fresh data, and
+// warns about each that failed: the command ran on that old data. The
+// command's output, and its ndjson stream, have ended by then; the refreshes'
+// events are not part of it.
+func finishStaleRefreshes() {
+	startEvents(nil)
+	failures, pending := staleRefreshes.WaitTimeout(staleRefreshWait)
+	for _, key := range pending {
+		log.Info("Not waiting for the refresh of %s; the next command refreshes it", key)
+	}
+	for _, f := range failures {
+		staleWarningsMu.Lock()
+		if f.Outage {
+			fmt.Fprintf(staleWarnings, "Warning: SCA API unavailable, used cached data from %s ago (%s): %v\n", formatAge(f.Age), f.Key, f.Err)
+		} else {
+			fmt.Fprintf(staleWarnings, "Warning: used cached data from %s ago (%s) that could not be refreshed: %v\n", formatAge(f.Age), f.Key, f.Err)
 		}
-		os.Exit(1)
+		staleWarningsMu.Unlock()
 	}
 }
 
 // supportedCSPs lists the cloud providers supported for elevation.
 var supportedCSPs = []models.CSP{models.CSPAzure, models.CSPAWS, models.CSPGCP}
 
-// fetchEligibility retrieves eligible targets. When provider is empty, all
-// supported CSPs are queried and results merged. When set, only that CSP is queried.
-// Each returned target has its CSP field set.
+// fetchEligibility retrieves eligible targets. When provider is empty, the
+// configured providers (all supported CSPs by default) are queried and results
+// merged; providerAll queries every supported CSP. Otherwise only that CSP is
+// queried. Each returned target has its CSP field set.
 func fetchEligibility(ctx context.Context, eligLister eligibilityLister, provider string) ([]models.EligibleTarget, error) {
-	if provider == "" {
+	if !isProviderFilter(provider) {
+		csps := providerScope(provider)
 		type cspResult struct {
 			targets []models.EligibleTarget
 			csp     models.CSP
 			err     error
 		}
 
-		results := make(chan cspResult, len(supportedCSPs))
+		results := make(chan cspResult, len(csps))
 		var wg sync.WaitGroup
-		for _, csp := range supportedCSPs {
+		for _, csp := range csps {
 			wg.Add(1)
 			go func(csp models.CSP) {
 				defer wg.Done()
-				resp, err := eligLister.ListEligibility(ctx, csp)
+				resp, err := listEligibilityReported(ctx, eligLister, csp)
 				if err != nil {
 					results <- cspResult{csp: csp, err: err}
 					return
@@ -358,7 +501,7 @@ func fetchEligibility(ctx context.Context, eligLister eligibilityLister, provide
 			}
 		}
 		if len(all) == 0 {
-			return nil, errors.New("no eligible targets found, check your SCA policies")
+			return nil, withCode(codeNotEligible, errors.New("no eligible targets found, check your SCA policies"))
 		}
 		return all, nil
 	}
@@ -371,12 +514,12 @@ func fetchEligibility(ctx context.Context, eligLister eligibilityLister, provide
 		}
 		return nil, fmt.Errorf("provider %q is not supported, supported providers: %s", provider, strings.Join(names, ", "))
 	}
-	resp, err := eligLister.ListEligibility(ctx, csp)
+	resp, err := listEligibilityReported(ctx, eligLister, csp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch eligible targets: %w", err)
 	}
 	if len(resp.Response) == 0 {
-		return nil, fmt.Errorf("no eligible %s targets found, check your SCA policies", strings.ToLower(provider))
+		return nil, withCode(codeNotEligible, fmt.Errorf("no eligible %s targets found, check your SCA policies", strings.ToLower(provider)))
 	}
 	targets := make([]models.EligibleTarget, len(resp.Response))
 	copy(targets, resp.Response)
@@ -395,7 +538,7 @@ func resolveTargetCSP(target *models.EligibleTarget, allTargets []models.Eligibl
 	if target.CSP != "" {
 		return
 	}
-	if provider != "" {
+	if isProviderFilter(provider) {
 		target.CSP = models.CSP(strings.ToUpper(provider))
 		return
 	}
@@ -436,20 +579,24 @@ func resolveAndElevate(
 	// Check authentication state
 	_, err := authLoader.LoadAuthentication(profile, true)
 	if err != nil {
-		return nil, fmt.Errorf("not authenticated, run 'grant login' first: %w", err)
+		return nil, notAuthenticatedError(err)
 	}
 
 	// Determine execution mode
 	var targetName, roleName string
 	var isFavoriteMode bool
+	var fav config.Favorite
 	var provider string
 
 	if flags.favorite != "" {
 		// Favorite mode
 		isFavoriteMode = true
-		fav, err := config.GetFavorite(cfg, flags.favorite)
+		fav, err = config.GetFavorite(cfg, flags.favorite)
 		if err != nil {
-			return nil, fmt.Errorf("favorite %q not found, run 'grant favorites list'", flags.favorite)
+			return nil, withCode(codeTargetNotFound, fmt.Errorf("favorite %q not found, run 'grant favorites list'", flags.favorite))
+		}
+		if err := checkFavoriteProfile(cfg, flags.favorite, fav); err != nil {
+			return nil, withCode(codeTargetNotFound, err)
 		}
 
 		// Group favorites must be used via the groups command
@@ -458,7 +605,7 @@ func resolveAndElevate(
 		}
 
 		// Check provider mismatch
-		if flags.provider != "" && !strings.EqualFold(flags.provider, fav.Provider) {
+		if isProviderFilter(flags.provider) && !strings.EqualFold(flags.provider, fav.Provider) {
 			return nil, fmt.Errorf("provider %q does not match favorite provider %q", flags.provider, fav.Provider)
 		}
 
@@ -472,7 +619,7 @@ func resolveAndElevate(
 
 		// Validate direct mode flags
 		if (targetName != "" && roleName == "") || (targetName == "" && roleName != "") {
-			return nil, errors.New("both --target and --role must be provided")
+			return nil, usageErrorf("both --target and --role must be provided")
 		}
 
 		provider = flags.provider
@@ -487,11 +634,16 @@ func resolveAndElevate(
 	// Resolve target based on mode
 	var selectedTarget *models.EligibleTarget
 
-	if isFavoriteMode || (targetName != "" && roleName != "") {
-		// Direct or favorite mode - find matching target
+	if isFavoriteMode {
+		selectedTarget, err = resolveFavoriteTarget(flags.favorite, fav, allTargets)
+		if err != nil {
+			return nil, err
+		}
+	} else if targetName != "" && roleName != "" {
+		// Direct mode - find matching target
 		selectedTarget = findMatchingTarget(allTargets, targetName, roleName)
 		if selectedTarget == nil {
-			return nil, fmt.Errorf("target %q or role %q not found, run 'grant' to see available options", targetName, roleName)
+			return nil, withCode(codeTargetNotFound, fmt.Errorf("target %q or role %q not found, run 'grant' to see available options", targetName, roleName))
 		}
 	} else {
 		// Interactive mode
@@ -511,53 +663,30 @@ func resolveAndElevate(
 		}
 	}
 
-	// Build elevation request
-	req := &models.ElevateRequest{
-		CSP:            selectedTarget.CSP,
-		OrganizationID: selectedTarget.OrganizationID,
-		Targets: []models.ElevateTarget{
-			{
-				WorkspaceID: selectedTarget.WorkspaceID,
-				RoleID:      selectedTarget.RoleInfo.ID,
-			},
-		},
-	}
-
 	// Fresh context for elevation — the original ctx may have expired during
 	// an interactive prompt (the user can take arbitrarily long to select).
 	elevCtx, elevCancel := context.WithTimeout(context.Background(), apiTimeout)
 	defer elevCancel()
 
-	// Execute elevation
-	elevateResp, err := elevateService.Elevate(elevCtx, req)
-	if err != nil {
-		return nil, fmt.Errorf("elevation request failed: %w", err)
-	}
-
-	// Check for errors in response
-	if len(elevateResp.Response.Results) == 0 {
-		return nil, errors.New("elevation failed: no results returned")
-	}
-
-	result := elevateResp.Response.Results[0]
-	if result.ErrorInfo != nil {
-		return nil, fmt.Errorf("elevation failed: %s - %s\n%s",
-			result.ErrorInfo.Code,
-			result.ErrorInfo.Message,
-			result.ErrorInfo.Description)
-	}
-
-	return &elevationResult{target: selectedTarget, result: &result}, nil
+	res, _, err := elevateCloud(elevCtx, selectedTarget, elevateService)
+	return res, err
 }
 
 // fetchGroupsEligibility fetches groups eligibility and enriches with directory names.
 func fetchGroupsEligibility(ctx context.Context, groupsEligLister groupsEligibilityLister, cloudEligLister eligibilityLister) ([]models.GroupsEligibleTarget, error) {
 	eligResp, err := groupsEligLister.ListGroupsEligibility(ctx, models.CSPAzure)
+	ev := eligibilityEventData{Kind: "groups", Provider: "azure"}
+	if err != nil {
+		ev.Error = err.Error()
+	} else {
+		ev.Count = len(eligResp.Response)
+	}
+	emitEvent(eventEligibilityFetched, ev)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch eligible groups: %w", err)
 	}
 	if len(eligResp.Response) == 0 {
-		return nil, errors.New("no eligible groups found, check your SCA policies")
+		return nil, withCode(codeNotEligible, errors.New("no eligible groups found, check your SCA policies"))
 	}
 
 	// Resolve directory names from cloud eligibility (best-effort)
@@ -577,8 +706,12 @@ type resolvedFlags struct {
 	roleName        string
 	provider        string
 	favDirectoryID  string
+	favGroupID      string
 	isFavoriteMode  bool
 	isGroupFavorite bool
+	favoriteName    string
+	favorite        config.Favorite
+	favorites       []config.FavoriteEntry // listed first by the interactive selector
 }
 
 // resolveFavoriteFlags resolves favorite and direct flags into concrete values.
@@ -589,28 +722,35 @@ func resolveFavoriteFlags(flags *elevateFlags, cfg *config.Config) (*resolvedFla
 		rf.isFavoriteMode = true
 		fav, err := config.GetFavorite(cfg, flags.favorite)
 		if err != nil {
-			return nil, fmt.Errorf("favorite %q not found, run 'grant favorites list'", flags.favorite)
+			return nil, withCode(codeTargetNotFound, fmt.Errorf("favorite %q not found, run 'grant favorites list'", flags.favorite))
+		}
+		if err := checkFavoriteProfile(cfg, flags.favorite, fav); err != nil {
+			return nil, withCode(codeTargetNotFound, err)
 		}
 
 		if fav.ResolvedType() == config.FavoriteTypeGroups {
 			rf.isGroupFavorite = true
 			flags.group = fav.Group
 			rf.favDirectoryID = fav.DirectoryID
+			rf.favGroupID = fav.GroupID
 		} else {
-			if flags.provider != "" && !strings.EqualFold(flags.provider, fav.Provider) {
+			if isProviderFilter(flags.provider) && !strings.EqualFold(flags.provider, fav.Provider) {
 				return nil, fmt.Errorf("provider %q does not match favorite provider %q", flags.provider, fav.Provider)
 			}
 			rf.provider = fav.Provider
 			rf.targetName = fav.Target
 			rf.roleName = fav.Role
+			rf.favoriteName = flags.favorite
+			rf.favorite = fav
 		}
 	} else {
 		rf.targetName = flags.target
 		rf.roleName = flags.role
 		rf.provider = flags.provider
+		rf.favorites = profileFavorites(cfg)
 
 		if (rf.targetName != "" && rf.roleName == "") || (rf.targetName == "" && rf.roleName != "") {
-			return nil, errors.New("both --target and --role must be provided")
+			return nil, usageErrorf("both --target and --role must be provided")
 		}
 	}
 
@@ -637,7 +777,7 @@ func resolveAndElevateUnified(
 	// Check authentication state
 	_, err := authLoader.LoadAuthentication(profile, true)
 	if err != nil {
-		return nil, nil, fmt.Errorf("not authenticated, run 'grant login' first: %w", err)
+		return nil, nil, notAuthenticatedError(err)
 	}
 
 	rf, err := resolveFavoriteFlags(flags, cfg)
@@ -647,30 +787,30 @@ func resolveAndElevateUnified(
 
 	// Dispatch to the appropriate elevation path
 	if flags.group != "" {
-		return resolveAndElevateDirectGroup(ctx, flags.group, rf.favDirectoryID, groupsEligLister, eligibilityLister, groupsElevator)
+		return resolveAndElevateDirectGroup(ctx, flags.group, rf.favDirectoryID, rf.favGroupID, groupsEligLister, eligibilityLister, groupsElevator)
 	}
 	if flags.groups {
 		return resolveAndElevateGroupsFilter(ctx, groupsEligLister, eligibilityLister, selector, groupsElevator)
 	}
-	if rf.provider != "" || rf.isFavoriteMode || (rf.targetName != "" && rf.roleName != "") {
+	if isProviderFilter(rf.provider) || rf.isFavoriteMode || (rf.targetName != "" && rf.roleName != "") {
 		return resolveAndElevateCloudOnly(ctx, rf, eligibilityLister, elevateService, selector)
 	}
-	return resolveAndElevateUnifiedPath(ctx, eligibilityLister, groupsEligLister, selector, elevateService, groupsElevator)
+	return resolveAndElevateUnifiedPath(ctx, rf.provider, rf.favorites, eligibilityLister, groupsEligLister, selector, elevateService, groupsElevator)
 }
 
 // resolveAndElevateDirectGroup handles the --group flag or group favorite path.
-func resolveAndElevateDirectGroup(ctx context.Context, groupName, favDirectoryID string, groupsEligLister groupsEligibilityLister, cloudEligLister eligibilityLister, groupsElevator groupsElevator) (*elevationResult, *groupElevationResult, error) {
+func resolveAndElevateDirectGroup(ctx context.Context, groupName, favDirectoryID, favGroupID string, groupsEligLister groupsEligibilityLister, cloudEligLister eligibilityLister, groupsElevator groupsElevator) (*elevationResult, *groupElevationResult, error) {
 	groups, err := fetchGroupsEligibility(ctx, groupsEligLister, cloudEligLister)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	selectedGroup := findMatchingGroup(groups, groupName, favDirectoryID)
+	selectedGroup := findMatchingGroup(groups, groupName, favDirectoryID, favGroupID)
 	if selectedGroup == nil {
 		if favDirectoryID != "" {
-			return nil, nil, fmt.Errorf("group %q not found in directory %q, run 'grant' to see available options", groupName, favDirectoryID)
+			return nil, nil, withCode(codeTargetNotFound, fmt.Errorf("group %q not found in directory %q, run 'grant' to see available options", groupName, favDirectoryID))
 		}
-		return nil, nil, fmt.Errorf("group %q not found, run 'grant' to see available options", groupName)
+		return nil, nil, withCode(codeTargetNotFound, fmt.Errorf("group %q not found, run 'grant' to see available options", groupName))
 	}
 
 	return elevateGroup(ctx, selectedGroup, groupsElevator)
@@ -708,10 +848,15 @@ func resolveAndElevateCloudOnly(ctx context.Context, rf *resolvedFlags, eligList
 	}
 
 	var selectedTarget *models.EligibleTarget
-	if rf.isFavoriteMode && !rf.isGroupFavorite || (rf.targetName != "" && rf.roleName != "") {
+	if rf.isFavoriteMode && !rf.isGroupFavorite {
+		selectedTarget, err = resolveFavoriteTarget(rf.favoriteName, rf.favorite, allTargets)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if rf.targetName != "" && rf.roleName != "" {
 		selectedTarget = findMatchingTarget(allTargets, rf.targetName, rf.roleName)
 		if selectedTarget == nil {
-			return nil, nil, fmt.Errorf("target %q or role %q not found, run 'grant' to see available options", rf.targetName, rf.roleName)
+			return nil, nil, withCode(codeTargetNotFound, fmt.Errorf("target %q or role %q not found, run 'grant' to see available options", rf.targetName, rf.roleName))
 		}
 	} else {
 		var items []selectionItem
@@ -719,7 +864,7 @@ func resolveAndElevateCloudOnly(ctx context.Context, rf *resolvedFlags, eligList
 			items = append(items, selectionItem{kind: selectionCloud, cloud: &allTargets[i]})
 		}
 
-		selected, err := selector.SelectItem(items)
+		selected, err := selector.SelectItem(withFavoriteItems(items, rf.favorites))
 		if err != nil {
 			return nil, nil, fmt.Errorf("selection failed: %w", err)
 		}
@@ -735,8 +880,9 @@ func resolveAndElevateCloudOnly(ctx context.Context, rf *resolvedFlags, eligList
 	return elevateCloud(elevCtx, selectedTarget, elevateService)
 }
 
-// resolveAndElevateUnifiedPath handles the unified path (no filter flags) with parallel fetch.
-func resolveAndElevateUnifiedPath(ctx context.Context, eligLister eligibilityLister, groupsEligLister groupsEligibilityLister, selector unifiedSelector, elevateService elevateService, groupsElevator groupsElevator) (*elevationResult, *groupElevationResult, error) {
+// resolveAndElevateUnifiedPath handles the unified path (no filter flags, or
+// --provider all) with parallel fetch.
+func resolveAndElevateUnifiedPath(ctx context.Context, provider string, favorites []config.FavoriteEntry, eligLister eligibilityLister, groupsEligLister groupsEligibilityLister, selector unifiedSelector, elevateService elevateService, groupsElevator groupsElevator) (*elevationResult, *groupElevationResult, error) {
 	type cloudResult struct {
 		targets []models.EligibleTarget
 		err     error
@@ -750,11 +896,15 @@ func resolveAndElevateUnifiedPath(ctx context.Context, eligLister eligibilityLis
 	groupsCh := make(chan groupsResult, 1)
 
 	go func() {
-		targets, err := fetchEligibility(ctx, eligLister, "")
+		targets, err := fetchEligibility(ctx, eligLister, provider)
 		cloudCh <- cloudResult{targets: targets, err: err}
 	}()
 
 	go func() {
+		if !groupsInScope(provider) {
+			groupsCh <- groupsResult{}
+			return
+		}
 		groups, err := fetchGroupsEligibility(ctx, groupsEligLister, eligLister)
 		groupsCh <- groupsResult{groups: groups, err: err}
 	}()
@@ -775,10 +925,10 @@ func resolveAndElevateUnifiedPath(ctx context.Context, eligLister eligibilityLis
 	}
 
 	if len(items) == 0 {
-		return nil, nil, errors.New("no eligible targets or groups found, check your SCA policies")
+		return nil, nil, withCode(codeNotEligible, errors.New("no eligible targets or groups found, check your SCA policies"))
 	}
 
-	selected, err := selector.SelectItem(items)
+	selected, err := selector.SelectItem(withFavoriteItems(items, favorites))
 	if err != nil {
 		return nil, nil, fmt.Errorf("selection failed: %w", err)
 	}
@@ -800,7 +950,16 @@ func resolveAndElevateUnifiedPath(ctx context.Context, eligLister eligibilityLis
 }
 
 // elevateCloud performs cloud role elevation for a selected target.
-func elevateCloud(ctx context.Context, target *models.EligibleTarget, elevateService elevateService) (*elevationResult, *groupElevationResult, error) {
+func elevateCloud(ctx context.Context, target *models.EligibleTarget, elevateService elevateService) (res *elevationResult, _ *groupElevationResult, err error) {
+	ev := cloudElevationEvent(target)
+	emitEvent(eventElevationRequested, ev)
+	defer func() {
+		if res != nil {
+			ev.SessionID = res.result.SessionID
+		}
+		emitElevationOutcome(ev, err)
+	}()
+
 	req := &models.ElevateRequest{
 		CSP:            target.CSP,
 		OrganizationID: target.OrganizationID,
@@ -823,17 +982,23 @@ func elevateCloud(ctx context.Context, target *models.EligibleTarget, elevateSer
 
 	result := elevateResp.Response.Results[0]
 	if result.ErrorInfo != nil {
-		return nil, nil, fmt.Errorf("elevation failed: %s - %s\n%s",
-			result.ErrorInfo.Code,
-			result.ErrorInfo.Message,
-			result.ErrorInfo.Description)
+		return nil, nil, elevationFailedError(result.ErrorInfo)
 	}
 
 	return &elevationResult{target: target, result: &result}, nil, nil
 }
 
 // elevateGroup performs Entra ID group membership elevation.
-func elevateGroup(ctx context.Context, group *models.GroupsEligibleTarget, elevator groupsElevator) (*elevationResult, *groupElevationResult, error) {
+func elevateGroup(ctx context.Context, group *models.GroupsEligibleTarget, elevator groupsElevator) (_ *elevationResult, res *groupElevationResult, err error) {
+	ev := groupElevationEvent(group)
+	emitEvent(eventElevationRequested, ev)
+	defer func() {
+		if res != nil {
+			ev.SessionID = res.result.SessionID
+		}
+		emitElevationOutcome(ev, err)
+	}()
+
 	req := &models.GroupsElevateRequest{
 		DirectoryID: group.DirectoryID,
 		CSP:         models.CSPAzure,
@@ -853,10 +1018,7 @@ func elevateGroup(ctx context.Context, group *models.GroupsEligibleTarget, eleva
 
 	result := elevateResp.Results[0]
 	if result.ErrorInfo != nil {
-		return nil, nil, fmt.Errorf("elevation failed: %s - %s\n%s",
-			result.ErrorInfo.Code,
-			result.ErrorInfo.Message,
-			result.ErrorInfo.Description)
+		return nil, nil, elevationFailedError(result.ErrorInfo)
 	}
 
 	return nil, &groupElevationResult{group: group, result: &result}, nil
@@ -882,14 +1044,22 @@ func runElevateWithDeps(
 		return err
 	}
 
-	// Record session timestamp for remaining-time tracking (best-effort)
+	// Record session timestamp for remaining-time tracking and the session
+	// for 'grant prompt' (best-effort)
 	if groupRes != nil {
 		recordSessionTimestamp(groupRes.result.SessionID)
+		rememberSession(cache.KnownSession{
+			SessionID: groupRes.result.SessionID,
+			Provider:  strings.ToLower(string(models.CSPAzure)),
+			Type:      "group",
+			Target:    groupRes.group.GroupName,
+		})
 	} else if cloudRes != nil {
 		recordSessionTimestamp(cloudRes.result.SessionID)
+		rememberSession(knownCloudSession(cloudRes))
 	}
 
-	if isJSONOutput() {
+	if isStructuredOutput() {
 		return writeElevationJSON(cmd, cloudRes, groupRes)
 	}
 
@@ -935,6 +1105,18 @@ func runElevateWithDeps(
 	return nil
 }
 
+// knownCloudSession describes a just-elevated cloud session for the
+// last-known session list. Its duration is not known until it is listed.
+func knownCloudSession(res *elevationResult) cache.KnownSession {
+	return cache.KnownSession{
+		SessionID: res.result.SessionID,
+		Provider:  strings.ToLower(string(res.target.CSP)),
+		Type:      "cloud",
+		Target:    res.target.WorkspaceName,
+		Role:      res.target.RoleInfo.Name,
+	}
+}
+
 // writeElevationJSON writes the elevation result as JSON.
 func writeElevationJSON(cmd *cobra.Command, cloudRes *elevationResult, groupRes *groupElevationResult) error {
 	if groupRes != nil {
@@ -946,7 +1128,7 @@ func writeElevationJSON(cmd *cobra.Command, cloudRes *elevationResult, groupRes
 			DirectoryID: groupRes.group.DirectoryID,
 			Directory:   groupRes.group.DirectoryName,
 		}
-		return writeJSON(cmd.OutOrStdout(), out)
+		return writeOutput(cmd.OutOrStdout(), out)
 	}
 
 	out := cloudElevationOutput{
@@ -969,7 +1151,7 @@ func writeElevationJSON(cmd *cobra.Command, cloudRes *elevationResult, groupRes
 		}
 	}
 
-	return writeJSON(cmd.OutOrStdout(), out)
+	return writeOutput(cmd.OutOrStdout(), out)
 }
 
 // findMatchingTarget finds a target by workspace name and role name (case-insensitive)
@@ -1003,7 +1185,7 @@ func (s *uiUnifiedSelector) SelectItem(items []selectionItem) (*selectionItem, e
 	}
 
 	if len(items) == 0 {
-		return nil, errors.New("no eligible targets or groups available")
+		return nil, withCode(codeNotEligible, errors.New("no eligible targets or groups available"))
 	}
 
 	options, sorted := buildUnifiedOptions(items)
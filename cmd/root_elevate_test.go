@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -2177,3 +2178,85 @@ func TestRootElevate_JSONOutput(t *testing.T) {
 		})
 	}
 }
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestFetchEligibility_ProvidersSetting(t *testing.T) {
+	writeConfigFile(t, "providers: [aws]\n")
+
+	var mu sync.Mutex
+	var queried []models.CSP
+	lister := &mockEligibilityLister{
+		listFunc: func(ctx context.Context, csp models.CSP) (*models.EligibilityResponse, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			queried = append(queried, csp)
+			return &models.EligibilityResponse{Response: []models.EligibleTarget{{WorkspaceName: string(csp)}}, Total: 1}, nil
+		},
+	}
+
+	if _, err := fetchEligibility(t.Context(), lister, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queried) != 1 || queried[0] != models.CSPAWS {
+		t.Errorf("queried %v, want only AWS from the providers setting", queried)
+	}
+
+	queried = nil
+	if _, err := fetchEligibility(t.Context(), lister, providerAll); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queried) != len(supportedCSPs) {
+		t.Errorf("--provider all queried %v, want every supported provider", queried)
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestProviderScope_DefaultProvider(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		flag   string
+		want   []models.CSP
+	}{
+		{name: "unset queries every provider", config: "favorites: {}\n", want: supportedCSPs},
+		{name: "default_provider narrows", config: "default_provider: gcp\n", want: []models.CSP{models.CSPGCP}},
+		{name: "providers wins", config: "default_provider: gcp\nproviders: [aws, azure]\n", want: []models.CSP{models.CSPAWS, models.CSPAzure}},
+		{name: "all overrides", config: "default_provider: gcp\n", flag: providerAll, want: supportedCSPs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfigFile(t, tt.config)
+			if got := providerScope(tt.flag); !slices.Equal(got, tt.want) {
+				t.Errorf("providerScope(%q) = %v, want %v", tt.flag, got, tt.want)
+			}
+		})
+	}
+}
+
+// Not parallel: sets GRANT_CONFIG for the process.
+func TestResolveAndElevateUnifiedPath_GroupsOutOfScope(t *testing.T) {
+	writeConfigFile(t, "default_provider: aws\n")
+	target := models.EligibleTarget{WorkspaceID: "ws-1", WorkspaceName: "Prod", CSP: models.CSPAWS,
+		RoleInfo: models.RoleInfo{ID: "r-1", Name: "Admin"}}
+	eligLister := &mockEligibilityLister{listFunc: func(_ context.Context, csp models.CSP) (*models.EligibilityResponse, error) {
+		if csp != models.CSPAWS {
+			t.Errorf("queried %s, want only AWS", csp)
+		}
+		return &models.EligibilityResponse{Response: []models.EligibleTarget{target}, Total: 1}, nil
+	}}
+	groupsElig := &mockGroupsEligibilityLister{listFunc: func(context.Context, models.CSP) (*models.GroupsEligibilityResponse, error) {
+		t.Error("groups eligibility fetched although azure is out of scope")
+		return &models.GroupsEligibilityResponse{}, nil
+	}}
+	selector := &mockUnifiedSelector{selectFunc: func(items []selectionItem) (*selectionItem, error) {
+		return &items[0], nil
+	}}
+	elevSvc := &mockElevateService{response: &models.ElevateResponse{Response: models.ElevateAccessResult{
+		CSP: models.CSPAWS, Results: []models.ElevateTargetResult{{WorkspaceID: "ws-1", RoleID: "r-1", SessionID: "s-1"}},
+	}}}
+
+	res, _, err := resolveAndElevateUnifiedPath(t.Context(), "", nil, eligLister, groupsElig, selector, elevSvc, nil)
+	if err != nil || res == nil {
+		t.Fatalf("resolveAndElevateUnifiedPath() = %v, %v", res, err)
+	}
+}
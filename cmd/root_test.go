@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aaearon/grant-cli/internal/cache"
 	grantconfig "github.com/aaearon/grant-cli/internal/config"
 	"github.com/aaearon/grant-cli/internal/sca/models"
 	"github.com/aaearon/grant-cli/internal/ui"
@@ -108,6 +113,100 @@ func TestBuildCachedLister_TTL(t *testing.T) {
 	}
 }
 
+// TestBuildCachedLister_ServesStaleOnOutage drives the whole stale-while-error
+// path: an expired entry within cache_max_stale answers a failing API call,
+// with a warning on stderr and a cache_stale event.
+func TestBuildCachedLister_ServesStaleOnOutage(t *testing.T) {
+	stubCacheNamespace(t, cache.Namespace{Profile: "grant", Username: "stale@example.com"})
+	dir, err := cacheDir()
+	if err != nil {
+		t.Fatalf("cacheDir() error = %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	entry := fmt.Sprintf(`{"cached_at":%q,"response":{"response":[{"workspaceId":"ws-stale","workspaceName":"Stale"}],"total":1}}`,
+		time.Now().Add(-6*time.Hour-time.Minute).Format(time.RFC3339))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "eligibility_azure.json"), []byte(entry), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var warnings, events bytes.Buffer
+	origWarnings := staleWarnings
+	staleWarnings = &warnings
+	startEvents(&events)
+	t.Cleanup(func() {
+		staleWarnings = origWarnings
+		startEvents(nil)
+	})
+
+	outage := &mockEligibilityLister{listErr: errors.New("dial tcp: connection refused")}
+	tests := []struct {
+		name          string
+		cacheMaxStale string
+		wantStale     bool
+	}{
+		{name: "default max stale", cacheMaxStale: "", wantStale: true},
+		{name: "max stale too short", cacheMaxStale: "2h"},
+		{name: "disabled", cacheMaxStale: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings.Reset()
+			events.Reset()
+			cfg := grantconfig.DefaultConfig()
+			cfg.CacheTTL = "1h"
+			cfg.CacheMaxStale = tt.cacheMaxStale
+
+			lister, err := buildCachedLister(cfg, false, outage, nil)
+			if err != nil {
+				t.Fatalf("buildCachedLister() error = %v", err)
+			}
+			resp, err := lister.ListEligibility(t.Context(), models.CSPAzure)
+
+			if !tt.wantStale {
+				if err == nil || warnings.Len() > 0 {
+					t.Errorf("ListEligibility() = %+v, %v, warning %q; want the API error", resp, err, warnings.String())
+				}
+				return
+			}
+			if err != nil || len(resp.Response) != 1 || resp.Response[0].WorkspaceID != "ws-stale" {
+				t.Fatalf("ListEligibility() = %+v, %v; want the stale entry", resp, err)
+			}
+			if !strings.Contains(events.String(), `"type":"cache_stale"`) || !strings.Contains(events.String(), `"ageSeconds":216`) {
+				t.Errorf("events = %s, want a cache_stale event with the age", events.String())
+			}
+			// The entry was served at once; the outage shows once the
+			// background refresh has failed.
+			finishStaleRefreshes()
+			startEvents(&events)
+			if !strings.Contains(warnings.String(), "used cached data from 6h ago") ||
+				!strings.Contains(warnings.String(), "connection refused") {
+				t.Errorf("warning = %q, want the age and the API error", warnings.String())
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{30 * time.Second, "less than a minute"},
+		{42 * time.Minute, "42m"},
+		{6*time.Hour + 59*time.Minute, "6h"},
+		{47 * time.Hour, "47h"},
+		{72 * time.Hour, "3d"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.age); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
+
 func TestNewRootCommand_SilenceFlags(t *testing.T) {
 	cmd := newRootCommand(nil)
 
@@ -326,7 +425,18 @@ func TestOutputFlagValidation(t *testing.T) {
 	}{
 		{"text is valid", []string{"--output", "text", "noop"}, false},
 		{"json is valid", []string{"--output", "json", "noop"}, false},
+		{"ndjson is valid", []string{"--output", "ndjson", "noop"}, false},
+		{"yaml is valid", []string{"--output", "yaml", "noop"}, false},
+		{"csv is valid", []string{"--output", "csv", "noop"}, false},
+		{"tsv is valid", []string{"--output", "tsv", "noop"}, false},
+		{"wide is valid", []string{"--output", "wide", "noop"}, false},
+		{"template is valid", []string{"--output", "template={{.sessionId}}", "noop"}, false},
+		{"jsonpath is valid", []string{"--output", "jsonpath={.sessions[*].sessionId}", "noop"}, false},
 		{"xml is invalid", []string{"--output", "xml", "noop"}, true},
+		{"json with an expression is invalid", []string{"--output", "json=x", "noop"}, true},
+		{"empty template is invalid", []string{"--output", "template=", "noop"}, true},
+		{"unparseable template is invalid", []string{"--output", "template={{.x", "noop"}, true},
+		{"unparseable jsonpath is invalid", []string{"--output", "jsonpath={.a[x]}", "noop"}, true},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	"github.com/aaearon/grant-cli/internal/config"
+	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/spf13/cobra"
+)
+
+// schemaVersion is the version of the published output schemas. Bump it on
+// any change that can break a consumer: a removed or renamed field, a field
+// that becomes optional, or a narrowed type. Adding an optional field is not
+// breaking.
+const schemaVersion = 1
+
+// jsonSchemaDialect is the JSON Schema draft the schemas are written in.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// outputDocument is one machine-readable document grant emits.
+type outputDocument struct {
+	name        string
+	commands    string // the commands that print it
+	description string
+	sample      any      // a zero value of the document type
+	variants    []any    // for documents that are one of several shapes
+	aliases     []string // other names accepted by grant schema
+}
+
+// outputDocuments lists every --output json document. schema_test.go validates
+// a real instance of each against its schema; add a fixture with a new entry.
+var outputDocuments = []outputDocument{
+	{name: "elevate", commands: "grant", description: "Result of a cloud or group elevation",
+		variants: []any{cloudElevationOutput{}, groupElevationJSON{}}, aliases: []string{"grant"}},
+	{name: "env", commands: "grant env", description: "AWS credentials of a new elevation",
+		sample: awsCredentialOutput{}},
+	{name: "list", commands: "grant list", description: "Eligible cloud targets and groups",
+		sample: listOutput{}},
+	{name: "status", commands: "grant status", description: "Authentication state and active sessions",
+		sample: statusOutput{}},
+	{name: "status-require", commands: "grant status --require", description: "Whether a session requirement is met",
+		sample: requirementOutput{}},
+	{name: "revoke", commands: "grant revoke", description: "One entry per requested session revocation",
+		sample: []revocationOutput{}},
+	{name: "revoke-dry-run", commands: "grant revoke --dry-run", description: "Sessions a revoke would target",
+		sample: []sessionOutput{}},
+	{name: "prompt", commands: "grant prompt", description: "Last-known sessions for a shell prompt",
+		sample: []promptSessionOutput{}},
+	{name: "favorites-list", commands: "grant favorites list", description: "Saved favorites",
+		sample: []favoriteOutput{}, aliases: []string{"favorites"}},
+	{name: "favorites-check", commands: "grant favorites check", description: "Each favorite resolved against live eligibility",
+		sample: []favoriteCheckOutput{}},
+	{name: "request-list", commands: "grant request list", description: "Access requests and their total count",
+		sample: accessRequestListOutput{}},
+	{name: "request", commands: "grant request get|submit|cancel|approve|reject", description: "One access request",
+		sample:  accessRequestOutput{},
+		aliases: []string{"request-get", "request-submit", "request-cancel", "request-approve", "request-reject"}},
+	{name: "error", commands: "any command, on failure", description: "Error envelope written to stderr",
+		sample: errorOutput{}},
+	{name: "cache-list", commands: "grant cache list", description: "Entries in the local cache",
+		sample: []cacheEntryOutput{}},
+	{name: "cache-clear", commands: "grant cache clear", description: "Keys of the deleted cache entries",
+		sample: cacheClearOutput{}},
+	{name: "cache-warm", commands: "grant cache warm", description: "One entry per eligibility prefetch",
+		sample: []cacheWarmOutput{}},
+	{name: "cache-stats", commands: "grant cache stats", description: "Cache lookup counters per kind",
+		sample: cacheStatsOutput{}},
+	{name: "profiles-list", commands: "grant profiles list", description: "Configured tenant profiles",
+		sample: []profileOutput{}, aliases: []string{"profiles"}},
+	{name: "config-get", commands: "grant config get", description: "One effective setting and its source",
+		sample: configValueOutput{}},
+	{name: "config-view", commands: "grant config view", description: "Every effective setting and its source",
+		sample: []configValueOutput{}},
+	{name: "event", commands: "any command with --output ndjson", description: "One line of the event stream"},
+}
+
+// schemaEnums constrains string fields whose values are a closed set, keyed by
+// Go type name and JSON field name.
+var schemaEnums = map[string][]string{
+	"cloudElevationOutput.type":    {"cloud"},
+	"groupElevationJSON.type":      {"group"},
+	"sessionOutput.type":           {"cloud", "group"},
+	"sessionOutput.remainingBasis": {remainingBasisElevated, remainingBasisFirstObserved},
+	"promptSessionOutput.type":     {"cloud", "group"},
+	"requirementOutput.reason":     {requireReasonNotAuthenticated, requireReasonNoMatchingSession, requireReasonInsufficientRemaining, requireReasonRemainingUnknown},
+	"revocationOutput.outcome":     revocationOutcomeNames(),
+	"favoriteOutput.type":          {config.FavoriteTypeCloud, config.FavoriteTypeGroups},
+	"favoriteCheckOutput.type":     {config.FavoriteTypeCloud, config.FavoriteTypeGroups},
+	"favoriteCheckOutput.status":   {favoriteCheckOK, favoriteCheckAmbiguous, favoriteCheckMissing},
+	"errorDetail.code":             errorCodeNames(),
+	"eventOutput.type":             eventTypeNames(),
+	"eligibilityEventData.kind":    {"cloud", "groups"},
+	"elevationEventData.type":      {"cloud", "group"},
+	"cacheEntryOutput.kind":        append(slices.Clone(cache.Kinds), cache.KindOther),
+	"cacheWarmOutput.kind":         {cache.KindEligibility, cache.KindGroups},
+	"cacheKindStatsOutput.kind":    cache.Kinds,
+}
+
+// eventDataTypes maps each event type to the data it carries; nil data is the
+// command's own document, which depends on the command.
+var eventDataTypes = map[string]any{
+	eventEligibilityFetched:  eligibilityEventData{},
+	eventPageFetched:         pageEventData{},
+	eventCacheHit:            cacheEventData{},
+	eventCacheMiss:           cacheEventData{},
+	eventCacheStale:          cacheEventData{},
+	eventElevationRequested:  elevationEventData{},
+	eventElevationSucceeded:  elevationEventData{},
+	eventElevationFailed:     elevationEventData{},
+	eventRevokeBatch:         revokeBatchEventData{},
+	eventRevokePoll:          revokePollEventData{},
+	eventRequestStateChanged: requestStateEventData{},
+	eventResult:              nil,
+	eventError:               errorDetail{},
+}
+
+func errorCodeNames() []string {
+	names := make([]string, 0, len(exitCodes))
+	for code := range exitCodes {
+		names = append(names, string(code))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func revocationOutcomeNames() []string {
+	return []string{
+		string(scamodels.OutcomeRevoked), string(scamodels.OutcomeInProgress),
+		string(scamodels.OutcomeNotApplicable), string(scamodels.OutcomeUnknown),
+		string(scamodels.OutcomeConfirmedGone), string(scamodels.OutcomeStillPresent),
+	}
+}
+
+func eventTypeNames() []string {
+	names := make([]string, 0, len(eventDataTypes))
+	for t := range eventDataTypes {
+		names = append(names, t)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// findOutputDocument resolves a document by name or alias.
+func findOutputDocument(name string) (outputDocument, bool) {
+	for _, d := range outputDocuments {
+		if d.name == name || slices.Contains(d.aliases, name) {
+			return d, true
+		}
+	}
+	return outputDocument{}, false
+}
+
+// schemaBuilder derives JSON Schema from output structs. Named struct types
+// become $defs entries referenced by $ref.
+type schemaBuilder struct {
+	defs map[string]any
+}
+
+// documentSchema returns the complete schema for d.
+func documentSchema(d outputDocument) map[string]any {
+	b := &schemaBuilder{defs: map[string]any{}}
+
+	var root map[string]any
+	switch {
+	case d.name == "event":
+		root = b.eventSchema()
+	case len(d.variants) > 0:
+		var oneOf []any
+		for _, v := range d.variants {
+			oneOf = append(oneOf, b.typeSchema(reflect.TypeOf(v)))
+		}
+		root = map[string]any{"oneOf": oneOf}
+	default:
+		root = b.typeSchema(reflect.TypeOf(d.sample))
+	}
+
+	root["$schema"] = jsonSchemaDialect
+	root["$id"] = fmt.Sprintf("https://github.com/aaearon/grant-cli/schemas/v%d/%s.json", schemaVersion, d.name)
+	root["title"] = d.commands
+	root["description"] = d.description
+	root["x-schemaVersion"] = schemaVersion
+	if len(b.defs) > 0 {
+		root["$defs"] = b.defs
+	}
+	return root
+}
+
+// eventSchema describes an ndjson event: the data schema is selected by type.
+func (b *schemaBuilder) eventSchema() map[string]any {
+	var oneOf []any
+	for _, t := range eventTypeNames() {
+		data := map[string]any{"description": "the command's --output json document"}
+		if sample := eventDataTypes[t]; sample != nil {
+			data = b.typeSchema(reflect.TypeOf(sample))
+		}
+		oneOf = append(oneOf, map[string]any{
+			"properties": map[string]any{
+				"type": map[string]any{"const": t},
+				"data": data,
+			},
+		})
+	}
+	root := b.structSchema(reflect.TypeOf(eventOutput{}))
+	root["oneOf"] = oneOf
+	return root
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeSchema returns the schema for t, registering named structs in $defs.
+func (b *schemaBuilder) typeSchema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Pointer:
+		return b.typeSchema(t.Elem())
+	case t.Kind() == reflect.Slice:
+		return map[string]any{"type": "array", "items": b.typeSchema(t.Elem())}
+	case t.Kind() == reflect.Struct:
+		if _, ok := b.defs[t.Name()]; !ok {
+			b.defs[t.Name()] = b.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	case t.Kind() == reflect.Interface:
+		return map[string]any{}
+	case t.Kind() == reflect.String:
+		return map[string]any{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]any{"type": "number"}
+	}
+	panic(fmt.Sprintf("schema: unsupported output field type %s", t))
+}
+
+// structSchema returns the object schema for struct type t: a field is
+// required unless its json tag has omitempty or omitzero, and no other
+// properties are allowed.
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := b.typeSchema(f.Type)
+		if values := schemaEnums[t.Name()+"."+name]; len(values) > 0 {
+			prop["enum"] = values
+		}
+		props[name] = prop
+
+		tagOpts := strings.Split(opts, ",")
+		if !slices.Contains(tagOpts, "omitempty") && !slices.Contains(tagOpts, "omitzero") {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// NewSchemaCommand creates the schema command.
+func NewSchemaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema [document]",
+		Short: "Print JSON Schemas for machine-readable output",
+		Long: `Print the JSON Schema (draft 2020-12) of a document grant emits with
+--output json, derived from the same types that produce the output.
+
+Without an argument, lists the available documents. Name a document to print
+its schema; multi-word commands may be given as separate words. --all prints
+every schema in one object keyed by document name.
+
+Each schema carries x-schemaVersion, which is bumped on any change that can
+break a consumer.
+
+Examples:
+  grant schema
+  grant schema status
+  grant schema request list
+  grant schema --all > grant-schemas.json`,
+		RunE: runSchema,
+	}
+
+	cmd.Flags().Bool("all", false, "Print every schema, keyed by document name")
+
+	return cmd
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		if len(args) > 0 {
+			return usageErrorf("--all cannot be used with a document name")
+		}
+		schemas := make(map[string]any, len(outputDocuments))
+		for _, d := range outputDocuments {
+			schemas[d.name] = documentSchema(d)
+		}
+		return writeJSON(cmd.OutOrStdout(), schemas)
+	}
+
+	if len(args) == 0 {
+		if isStructuredOutput() {
+			names := make([]string, len(outputDocuments))
+			for i, d := range outputDocuments {
+				names[i] = d.name
+			}
+			return writeOutput(cmd.OutOrStdout(), names)
+		}
+		for _, d := range outputDocuments {
+			fmt.Fprintf(cmd.OutOrStdout(), "%-16s %s (%s)\n", d.name, d.description, d.commands)
+		}
+		return nil
+	}
+
+	name := strings.ToLower(strings.Join(args, "-"))
+	d, ok := findOutputDocument(name)
+	if !ok {
+		names := make([]string, len(outputDocuments))
+		for i, d := range outputDocuments {
+			names[i] = d.name
+		}
+		return usageErrorf("unknown document %q, available: %s", name, strings.Join(names, ", "))
+	}
+	return writeJSON(cmd.OutOrStdout(), documentSchema(d))
+}
@@ -0,0 +1,319 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validateSchema checks a decoded JSON value against a schema produced by
+// documentSchema. It implements exactly the draft 2020-12 keywords the
+// generator emits — $ref into $defs, type, properties, required,
+// additionalProperties: false, items, enum, const and oneOf — and fails on any
+// other keyword, so a generator change cannot silently outgrow the check.
+func validateSchema(root, schema map[string]any, v any, path string) []string {
+	var errs []string
+	fail := func(format string, a ...any) {
+		errs = append(errs, path+": "+fmt.Sprintf(format, a...))
+	}
+
+	for key := range schema {
+		switch key {
+		case "$ref", "type", "properties", "required", "additionalProperties", "items", "enum", "const", "oneOf",
+			"$schema", "$id", "$defs", "title", "description", "format", "x-schemaVersion":
+		default:
+			fail("validator does not implement keyword %q", key)
+		}
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		def, ok := root["$defs"].(map[string]any)[name].(map[string]any)
+		if !ok {
+			fail("unresolvable $ref %s", ref)
+			return errs
+		}
+		errs = append(errs, validateSchema(root, def, v, path)...)
+	}
+
+	switch want := schema["type"]; want {
+	case nil:
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("want object, got %T", v)
+			return errs
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for _, r := range schema["required"].([]any) {
+			if _, ok := obj[r.(string)]; !ok {
+				fail("missing required property %q", r)
+			}
+		}
+		for k, val := range obj {
+			prop, ok := props[k].(map[string]any)
+			if !ok {
+				if schema["additionalProperties"] == false {
+					fail("unexpected property %q", k)
+				}
+				continue
+			}
+			errs = append(errs, validateSchema(root, prop, val, path+"."+k)...)
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			fail("want array, got %T", v)
+			return errs
+		}
+		for i, item := range arr {
+			errs = append(errs, validateSchema(root, schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			fail("want string, got %T", v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("want boolean, got %T", v)
+		}
+	case "integer":
+		if n, ok := v.(float64); !ok || n != math.Trunc(n) {
+			fail("want integer, got %v", v)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			fail("want number, got %T", v)
+		}
+	default:
+		fail("validator does not implement type %v", want)
+	}
+
+	if props, ok := schema["properties"].(map[string]any); ok && schema["type"] == nil {
+		// Properties without a type, as in the oneOf branches of the event schema.
+		if obj, ok := v.(map[string]any); ok {
+			for k, prop := range props {
+				if val, ok := obj[k]; ok {
+					errs = append(errs, validateSchema(root, prop.(map[string]any), val, path+"."+k)...)
+				}
+			}
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, v) {
+		fail("%v is not one of %v", v, enum)
+	}
+	if c, ok := schema["const"]; ok && c != v {
+		fail("%v is not %v", v, c)
+	}
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		matched := 0
+		for _, branch := range oneOf {
+			if len(validateSchema(root, branch.(map[string]any), v, path)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail("matches %d oneOf branches, want exactly 1", matched)
+		}
+	}
+	return errs
+}
+
+// schemaJSON renders a document's schema the way grant schema prints it and
+// decodes it back, so the test sees exactly the published JSON.
+func schemaJSON(t *testing.T, name string) map[string]any {
+	t.Helper()
+	d, ok := findOutputDocument(name)
+	if !ok {
+		t.Fatalf("no document %q", name)
+	}
+	raw, err := json.Marshal(documentSchema(d))
+	if err != nil {
+		t.Fatalf("schema %s does not marshal: %v", name, err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func decodeDocument(t *testing.T, doc any) any {
+	t.Helper()
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// schemaFixtures are real documents for every published schema, covering
+// optional fields both present and absent.
+func schemaFixtures() map[string][]any {
+	secs := 1200
+	session := sessionOutput{SessionID: "s1", Provider: "aws", WorkspaceID: "111", WorkspaceName: "Prod", RoleID: "Admin",
+		Duration: 3600, RemainingSeconds: &secs, RemainingBasis: remainingBasisElevated, Type: "cloud"}
+	group := sessionOutput{SessionID: "s2", Provider: "azure", WorkspaceID: "dir", Duration: 3600, Type: "group", GroupID: "g1", GroupName: "Admins"}
+	request := accessRequestOutput{RequestID: "r1", TargetCategory: "CLOUD_CONSOLE", State: "PENDING", Result: "UNKNOWN",
+		Priority: "Medium", Target: "Prod", CreatedBy: "me", CreatedAt: "2026-01-01T00:00:00", UpdatedBy: "me", UpdatedAt: "2026-01-01T00:00:00"}
+	detail := errorDetail{Code: string(codeElevationFailed), Message: "denied", ExitCode: exitFailure,
+		ErrorInfo: &errorInfoOutput{Code: "DENIED", Message: "no", Description: "policy", Link: "https://example.test"}}
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	return map[string][]any{
+		"elevate": {
+			cloudElevationOutput{Type: "cloud", Provider: "aws", SessionID: "s1", Target: "Prod", Role: "Admin",
+				Credentials: &awsCredentialOutput{AccessKeyID: "AKIA", SecretAccessKey: "secret", SessionToken: "token"}},
+			groupElevationJSON{Type: "group", SessionID: "s2", GroupName: "Admins", GroupID: "g1", DirectoryID: "dir"},
+		},
+		"env":            {awsCredentialOutput{AccessKeyID: "AKIA", SecretAccessKey: "secret", SessionToken: "token"}},
+		"list":           {listOutput{Cloud: []listCloudTarget{{Provider: "aws", Target: "Prod", WorkspaceID: "111", WorkspaceType: "account", Role: "Admin", RoleID: "r"}}, Groups: []listGroupTarget{}}},
+		"status":         {statusOutput{Authenticated: true, Username: "me", Sessions: []sessionOutput{session, group}}, statusOutput{Sessions: []sessionOutput{}}},
+		"status-require": {requirementOutput{Satisfied: true, Session: &session}, requirementOutput{Reason: requireReasonNoMatchingSession, Message: "none"}},
+		"revoke":         {[]revocationOutput{{SessionID: "s1", Status: "SUCCESSFULLY_REVOKED", Outcome: "revoked"}, {SessionID: "s3", Outcome: "unknown", Reason: "no result", Unexpected: true}}},
+		"revoke-dry-run": {[]sessionOutput{group}},
+		"prompt":         {[]promptSessionOutput{{SessionID: "s1", Provider: "aws", Type: "cloud", Target: "Prod", Role: "Admin", Remaining: "≤20m", RemainingSeconds: &secs, UpperBound: true}}},
+		"favorites-list": {[]favoriteOutput{{Name: "prod", Type: "cloud", Provider: "aws", Target: "Prod", Role: "Admin", Profile: "grant",
+			WorkspaceID: "111", RoleID: "r", OrganizationID: "o", Description: "Production admin", Tags: []string{"prod"}, DefaultReason: "on-call", Source: "user"}, {Name: "grp", Type: "groups", Provider: "azure", Group: "Admins", DirectoryID: "dir", Source: "team", ReadOnly: true}}},
+		"favorites-check": {[]favoriteCheckOutput{{Name: "prod", Type: "cloud", Favorite: "aws/Prod/Admin", Status: favoriteCheckOK, Pinned: true},
+			{Name: "grp", Type: "groups", Favorite: "groups/Ops", Status: favoriteCheckMissing, Reason: "no eligible group", Candidates: []string{"groups/Opps"}}}},
+		"request-list": {accessRequestListOutput{Requests: []accessRequestOutput{request}, TotalCount: 1}},
+		"request":      {request},
+		"error":        {errorOutput{Error: detail}, errorOutput{Error: errorDetail{Code: string(codeAPIError), Message: "boom", ExitCode: exitAPIError, HTTPStatus: 503}}},
+		"cache-list": {[]cacheEntryOutput{
+			{Key: "eligibility_azure", Kind: "eligibility", CachedAt: at, AgeSeconds: 60, SizeBytes: 512, Items: 3},
+			{Key: "broken", Kind: "other", SizeBytes: 4, Expired: true},
+		}},
+		"cache-clear": {cacheClearOutput{Removed: []string{"eligibility_azure"}}, cacheClearOutput{Removed: []string{}}},
+		"cache-warm":  {[]cacheWarmOutput{{Kind: "eligibility", Provider: "aws", Items: 2}, {Kind: "groups", Provider: "azure", Error: "boom"}}},
+		"cache-stats": {
+			cacheStatsOutput{Since: at, Kinds: []cacheKindStatsOutput{{Kind: "ondemand", Hits: 3, Misses: 1, Stale: 1, HitRate: 0.75}}},
+			cacheStatsOutput{Kinds: []cacheKindStatsOutput{}},
+		},
+		"profiles-list": {[]profileOutput{{Name: "grant", Username: "me@example.com", Current: true}, {Name: "acme", Username: "me@acme.com", IdentityURL: "https://acme.id.cyberark.cloud"}}},
+		"config-get":    {configValueOutput{Key: "cache_ttl", Value: "4h", Source: "default"}},
+		"config-view":   {[]configValueOutput{{Key: "default_provider", Value: "aws", Source: "/home/me/.grant/config.yaml"}, {Key: "favorites.prod", Value: "aws/Prod/Admin", Source: "/home/me/.grant/config.yaml"}}},
+		"event": {
+			eventOutput{Time: at, Type: eventEligibilityFetched, Data: eligibilityEventData{Kind: "cloud", Provider: "aws", Count: 2}},
+			eventOutput{Time: at, Type: eventPageFetched, Data: pageEventData{Operation: "eligibility", Route: "/api/access/AWS/eligibility", Page: 2, Count: 50}},
+			eventOutput{Time: at, Type: eventCacheMiss, Data: cacheEventData{Key: "eligibility_aws"}},
+			eventOutput{Time: at, Type: eventCacheStale, Data: cacheEventData{Key: "eligibility_aws", AgeSeconds: 21600, Error: "timeout"}},
+			eventOutput{Time: at, Type: eventElevationFailed, Data: elevationEventData{Type: "cloud", Provider: "aws", Target: "Prod", Error: "denied", ErrorInfo: detail.ErrorInfo}},
+			eventOutput{Time: at, Type: eventRevokeBatch, Data: revokeBatchEventData{Batch: 1, Batches: 1, SessionIDs: []string{"s1"}, Results: []revokeBatchEventItem{{SessionID: "s1", Status: "SUCCESSFULLY_REVOKED"}}}},
+			eventOutput{Time: at, Type: eventRevokePoll, Data: revokePollEventData{Pending: 1}},
+			eventOutput{Time: at, Type: eventRequestStateChanged, Data: requestStateEventData{RequestID: "r1", State: "FINISHED", Result: "CANCELED"}},
+			eventOutput{Time: at, Type: eventResult, Data: statusOutput{Sessions: []sessionOutput{}}},
+			eventOutput{Time: at, Type: eventError, Data: detail},
+		},
+	}
+}
+
+func TestDocumentSchemas_ValidateRealDocuments(t *testing.T) {
+	fixtures := schemaFixtures()
+
+	for _, d := range outputDocuments {
+		t.Run(d.name, func(t *testing.T) {
+			schema := schemaJSON(t, d.name)
+			if schema["$schema"] != jsonSchemaDialect || schema["x-schemaVersion"] != float64(schemaVersion) {
+				t.Errorf("schema header = %v / %v", schema["$schema"], schema["x-schemaVersion"])
+			}
+
+			docs, ok := fixtures[d.name]
+			if !ok {
+				t.Fatalf("no fixture for %s; add one to schemaFixtures", d.name)
+			}
+			for _, doc := range docs {
+				if errs := validateSchema(schema, schema, decodeDocument(t, doc), "$"); len(errs) > 0 {
+					t.Errorf("%T does not validate:\n%s", doc, strings.Join(errs, "\n"))
+				}
+			}
+		})
+	}
+}
+
+// TestDocumentSchemas_RejectBrokenDocuments proves the schemas, and the
+// validator above, are not vacuous.
+func TestDocumentSchemas_RejectBrokenDocuments(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		doc      string
+		wantErr  string
+	}{
+		{"missing required field", "status", `{"sessions":[]}`, `missing required property "authenticated"`},
+		{"unknown field", "env", `{"accessKeyId":"a","secretAccessKey":"b","sessionToken":"c","expiry":"x"}`, `unexpected property "expiry"`},
+		{"wrong type", "request-list", `{"requests":[],"totalCount":"1"}`, "want integer"},
+		{"value outside enum", "revoke", `[{"sessionId":"s","status":"","outcome":"gone"}]`, "is not one of"},
+		{"matches no variant", "elevate", `{"type":"cloud","sessionId":"s","groupName":"g","groupId":"g","directoryId":"d"}`, "oneOf"},
+		{"event data of another type", "event", `{"time":"2026-03-01T12:00:00Z","type":"revoke_poll","data":{"key":"x"}}`, "oneOf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := schemaJSON(t, tt.document)
+			var v any
+			if err := json.Unmarshal([]byte(tt.doc), &v); err != nil {
+				t.Fatal(err)
+			}
+			errs := validateSchema(schema, schema, v, "$")
+			if !strings.Contains(strings.Join(errs, "\n"), tt.wantErr) {
+				t.Errorf("errors = %v, want one containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchemaCommand(t *testing.T) {
+	t.Run("lists documents", func(t *testing.T) {
+		out, err := executeCommand(NewSchemaCommand())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, d := range outputDocuments {
+			if !strings.Contains(out, d.name) {
+				t.Errorf("listing missing %s:\n%s", d.name, out)
+			}
+		}
+	})
+
+	t.Run("multi-word names and aliases", func(t *testing.T) {
+		for _, args := range [][]string{{"request", "list"}, {"request", "get"}, {"favorites", "list"}, {"grant"}} {
+			out, err := executeCommand(NewSchemaCommand(), args...)
+			if err != nil {
+				t.Fatalf("%v: unexpected error: %v", args, err)
+			}
+			var schema map[string]any
+			if err := json.Unmarshal([]byte(out), &schema); err != nil || schema["$schema"] != jsonSchemaDialect {
+				t.Errorf("%v: not a schema: %v\n%s", args, err, out)
+			}
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		out, err := executeCommand(NewSchemaCommand(), "--all")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var all map[string]any
+		if err := json.Unmarshal([]byte(out), &all); err != nil || len(all) != len(outputDocuments) {
+			t.Errorf("--all = %d schemas (%v), want %d", len(all), err, len(outputDocuments))
+		}
+	})
+
+	t.Run("unknown document", func(t *testing.T) {
+		_, err := executeCommand(NewSchemaCommand(), "nope")
+		if err == nil || classifyError(err, true).code != codeUsage || !strings.Contains(err.Error(), "available: elevate") {
+			t.Errorf("error = %v, want a usage error listing the documents", err)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/aaearon/grant-cli/internal/config"
 	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
 	"github.com/aaearon/grant-cli/internal/ui"
 )
@@ -16,10 +17,12 @@ const (
 )
 
 // selectionItem is a tagged union representing either a cloud target or a group target.
+// favorite is set on the copy of an item that a saved favorite points at.
 type selectionItem struct {
-	kind  selectionKind
-	cloud *scamodels.EligibleTarget
-	group *scamodels.GroupsEligibleTarget
+	kind     selectionKind
+	cloud    *scamodels.EligibleTarget
+	group    *scamodels.GroupsEligibleTarget
+	favorite *config.FavoriteEntry
 }
 
 // groupElevationResult holds the outcome of a successful group elevation request.
@@ -30,7 +33,15 @@ type groupElevationResult struct {
 
 // formatSelectionItem formats a selectionItem into a display string.
 // Group items always show an (azure) suffix since Entra ID groups are Azure-only.
+// Favorite items lead with the favorite's name and description.
 func formatSelectionItem(item selectionItem) string {
+	if fav := item.favorite; fav != nil {
+		base := formatSelectionItem(selectionItem{kind: item.kind, cloud: item.cloud, group: item.group})
+		if fav.Description != "" {
+			return fmt.Sprintf("Favorite: %s - %s (%s)", fav.Name, fav.Description, base)
+		}
+		return fmt.Sprintf("Favorite: %s (%s)", fav.Name, base)
+	}
 	switch item.kind {
 	case selectionCloud:
 		return ui.FormatTargetOption(*item.cloud)
@@ -59,7 +70,11 @@ func buildUnifiedOptions(items []selectionItem) ([]string, []selectionItem) {
 
 	// Stable so that items which render identically keep their input order, and the
 	// options slice and the sorted items slice stay index-for-index aligned.
+	// Favorites come first.
 	sort.SliceStable(pairs, func(i, j int) bool {
+		if fi, fj := pairs[i].item.favorite != nil, pairs[j].item.favorite != nil; fi != fj {
+			return fi
+		}
 		return pairs[i].display < pairs[j].display
 	})
 
@@ -73,6 +88,72 @@ func buildUnifiedOptions(items []selectionItem) ([]string, []selectionItem) {
 	return options, sorted
 }
 
+// withFavoriteItems adds, for each favorite that resolves to exactly one
+// eligible item as elevation would (by its pinned IDs, else by name), a copy
+// of that item labelled with the favorite, so the selector lists it first. A
+// favorite matching several items labels none of them. The eligible item
+// itself stays in the list.
+func withFavoriteItems(items []selectionItem, favorites []config.FavoriteEntry) []selectionItem {
+	var targets []scamodels.EligibleTarget
+	var groups []scamodels.GroupsEligibleTarget
+	var cloudItems, groupItems []selectionItem
+	for _, item := range items {
+		switch item.kind {
+		case selectionCloud:
+			targets = append(targets, *item.cloud)
+			cloudItems = append(cloudItems, item)
+		case selectionGroup:
+			groups = append(groups, *item.group)
+			groupItems = append(groupItems, item)
+		}
+	}
+
+	var favItems []selectionItem
+	for i := range favorites {
+		fav := &favorites[i]
+		if fav.ResolvedType() == config.FavoriteTypeGroups {
+			if matches, _ := matchFavoriteGroups(fav.Favorite, groups); len(matches) == 1 {
+				item := groupItems[indexOf(groups, matches[0])]
+				item.favorite = fav
+				favItems = append(favItems, item)
+			}
+			continue
+		}
+		if matches, _ := matchFavoriteTargets(fav.Favorite, targets); len(matches) == 1 {
+			item := cloudItems[indexOf(targets, matches[0])]
+			item.favorite = fav
+			favItems = append(favItems, item)
+		}
+	}
+	return append(favItems, items...)
+}
+
+// indexOf returns the index of the element of s that p points at, or -1.
+func indexOf[T any](s []T, p *T) int {
+	for i := range s {
+		if &s[i] == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// profileFavorites returns the favorites of cfg available in its active
+// profile; none for a nil cfg.
+func profileFavorites(cfg *config.Config) []config.FavoriteEntry {
+	if cfg == nil {
+		return nil
+	}
+	profile := profileOf(cfg)
+	var favorites []config.FavoriteEntry
+	for _, entry := range config.ListFavorites(cfg) {
+		if entry.AvailableIn(profile) {
+			favorites = append(favorites, entry)
+		}
+	}
+	return favorites
+}
+
 // resolveSelectionItem recovers the item at the index survey returned. Resolving by
 // index rather than by display text is what makes duplicate display strings safe:
 // formatSelectionItem carries no ID, so the same group name in two directories — or
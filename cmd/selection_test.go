@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/aaearon/grant-cli/internal/config"
 	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
 )
 
@@ -326,3 +327,78 @@ func TestBuildUnifiedOptions_StableAmongCollisions(t *testing.T) {
 		}
 	}
 }
+
+func TestWithFavoriteItems(t *testing.T) {
+	cloud := scamodels.EligibleTarget{WorkspaceName: "Prod", WorkspaceType: scamodels.WorkspaceTypeAccount,
+		RoleInfo: scamodels.RoleInfo{Name: "Admin"}, CSP: scamodels.CSPAWS}
+	other := scamodels.EligibleTarget{WorkspaceName: "Dev", WorkspaceType: scamodels.WorkspaceTypeAccount,
+		RoleInfo: scamodels.RoleInfo{Name: "Admin"}, CSP: scamodels.CSPAWS}
+	group := scamodels.GroupsEligibleTarget{GroupName: "Ops", DirectoryID: "dir-1"}
+	items := []selectionItem{
+		{kind: selectionCloud, cloud: &other},
+		{kind: selectionCloud, cloud: &cloud},
+		{kind: selectionGroup, group: &group},
+	}
+	favorites := []config.FavoriteEntry{
+		{Name: "prod", Favorite: config.Favorite{Provider: "aws", Target: "prod", Role: "admin", Description: "Production admin"}},
+		{Name: "ops", Favorite: config.Favorite{Type: config.FavoriteTypeGroups, Provider: "azure", Group: "Ops"}},
+		{Name: "wrong-provider", Favorite: config.Favorite{Provider: "azure", Target: "Prod", Role: "Admin"}},
+		{Name: "other-directory", Favorite: config.Favorite{Type: config.FavoriteTypeGroups, Group: "Ops", DirectoryID: "dir-2"}},
+	}
+
+	options, sorted := buildUnifiedOptions(withFavoriteItems(items, favorites))
+	want := []string{
+		"Favorite: ops (Group: Ops (azure))",
+		"Favorite: prod - Production admin (Account: Prod / Role: Admin (aws))",
+		"Account: Dev / Role: Admin (aws)",
+		"Account: Prod / Role: Admin (aws)",
+		"Group: Ops (azure)",
+	}
+	if !reflect.DeepEqual(options, want) {
+		t.Fatalf("options = %q\nwant %q", options, want)
+	}
+	if sorted[1].cloud != &cloud || sorted[0].group != &group {
+		t.Error("favorite items do not point at the eligible target and group they match")
+	}
+}
+
+func TestWithFavoriteItems_AmbiguousOrPinned(t *testing.T) {
+	first := scamodels.EligibleTarget{WorkspaceID: "ws-1", WorkspaceName: "Prod", WorkspaceType: scamodels.WorkspaceTypeAccount,
+		RoleInfo: scamodels.RoleInfo{ID: "role-1", Name: "Admin"}, CSP: scamodels.CSPAWS}
+	second := scamodels.EligibleTarget{WorkspaceID: "ws-2", WorkspaceName: "Prod", WorkspaceType: scamodels.WorkspaceTypeAccount,
+		RoleInfo: scamodels.RoleInfo{ID: "role-1", Name: "Admin"}, CSP: scamodels.CSPAWS}
+	items := []selectionItem{
+		{kind: selectionCloud, cloud: &first},
+		{kind: selectionCloud, cloud: &second},
+	}
+
+	tests := []struct {
+		name     string
+		favorite config.Favorite
+		want     *scamodels.EligibleTarget
+	}{
+		{name: "ambiguous name labels nothing", favorite: config.Favorite{Provider: "aws", Target: "Prod", Role: "Admin"}},
+		{name: "pinned ID labels its target", favorite: config.Favorite{Provider: "aws", Target: "Prod", Role: "Admin", WorkspaceID: "ws-2", RoleID: "role-1"}, want: &second},
+		{name: "pinned ID wins over a stale name", favorite: config.Favorite{Provider: "aws", Target: "Old", Role: "Admin", WorkspaceID: "ws-1"}, want: &first},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withFavoriteItems(items, []config.FavoriteEntry{{Name: "prod", Favorite: tt.favorite}})
+			var labelled []*scamodels.EligibleTarget
+			for _, item := range got {
+				if item.favorite != nil {
+					labelled = append(labelled, item.cloud)
+				}
+			}
+			if tt.want == nil {
+				if len(labelled) != 0 {
+					t.Fatalf("labelled %d items, want none", len(labelled))
+				}
+				return
+			}
+			if len(labelled) != 1 || labelled[0] != tt.want {
+				t.Fatalf("labelled %v, want only %s", labelled, tt.want.WorkspaceID)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/spf13/cobra"
+)
+
+// sessionFilter selects active sessions by what they grant access to. It
+// narrows the sessions offered by 'grant revoke' and is the matcher behind
+// 'grant status --require'. The zero value matches every session.
+//
+// The provider filter is not part of it: that one is applied server-side by
+// ListSessions. Everything here is evaluated locally against the listed rows.
+type sessionFilter struct {
+	target    string        // workspace name or ID, case-insensitive
+	role      string        // role name, case-insensitive
+	group     string        // Entra ID group name or ID, case-insensitive
+	olderThan time.Duration // 0 = no age filter
+	mineOnly  bool          // only sessions with a local elevation timestamp
+	except    map[string]bool
+}
+
+// parseRevokeFilter reads the revoke filter flags from the command.
+func parseRevokeFilter(cmd *cobra.Command) (*sessionFilter, error) {
+	f := &sessionFilter{}
+	f.target, _ = cmd.Flags().GetString("target")
+	f.role, _ = cmd.Flags().GetString("role")
+	f.group, _ = cmd.Flags().GetString("group")
+	f.mineOnly, _ = cmd.Flags().GetBool("mine-only")
+
+	if (f.target != "" || f.role != "") && f.group != "" {
+		return nil, errors.New("--group cannot be combined with --target or --role")
+	}
+
+	if s, _ := cmd.Flags().GetString("older-than"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --older-than %q: must be a Go duration such as 30m or 2h", s)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid --older-than %q: must be positive", s)
+		}
+		f.olderThan = d
+	}
+
+	except, _ := cmd.Flags().GetStringSlice("except")
+	for _, id := range except {
+		if f.except == nil {
+			f.except = make(map[string]bool, len(except))
+		}
+		f.except[id] = true
+	}
+
+	return f, nil
+}
+
+// active reports whether any filter flag was given.
+func (f *sessionFilter) active() bool {
+	return f.target != "" || f.role != "" || f.group != "" || f.olderThan > 0 || f.mineOnly || len(f.except) > 0
+}
+
+// needsTimestamps reports whether matching reads the local elevation timestamps.
+func (f *sessionFilter) needsTimestamps() bool {
+	return f.olderThan > 0 || f.mineOnly
+}
+
+// sessionFilterContext holds the lookups a filter is matched against.
+type sessionFilterContext struct {
+	nameMap      map[string]string    // workspaceID -> workspaceName
+	groupNameMap map[string]string    // groupID -> groupName
+	timestamps   map[string]time.Time // sessionID -> locally recorded elevation time
+	now          time.Time
+}
+
+// matches reports whether a session passes every filter.
+//
+// Age is only known for sessions grant recorded locally, so --older-than never
+// matches a session without a timestamp: a cleanup filter must not revoke a
+// session it cannot prove is old enough.
+func (f *sessionFilter) matches(s scamodels.SessionInfo, fc sessionFilterContext) bool {
+	if f.except[s.SessionID] {
+		return false
+	}
+
+	if f.target != "" || f.role != "" {
+		if s.IsGroupSession() {
+			return false
+		}
+		if f.target != "" && !matchesNameOrID(f.target, s.WorkspaceID, fc.nameMap[s.WorkspaceID]) {
+			return false
+		}
+		if f.role != "" && !strings.EqualFold(f.role, s.RoleID) {
+			return false
+		}
+	}
+
+	if f.group != "" {
+		if !s.IsGroupSession() || !matchesNameOrID(f.group, s.Target.ID, fc.groupNameMap[s.Target.ID]) {
+			return false
+		}
+	}
+
+	if f.needsTimestamps() {
+		elevatedAt, ok := fc.timestamps[s.SessionID]
+		if !ok {
+			return false
+		}
+		if f.olderThan > 0 && fc.now.Sub(elevatedAt) < f.olderThan {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterSessions returns the sessions that pass every filter, preserving order.
+func filterSessions(sessions []scamodels.SessionInfo, f *sessionFilter, fc sessionFilterContext) []scamodels.SessionInfo {
+	var out []scamodels.SessionInfo
+	for _, s := range sessions {
+		if f.matches(s, fc) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// matchesNameOrID compares want case-insensitively against an ID and its
+// resolved display name. An unresolved name is "" and never matches.
+func matchesNameOrID(want, id, name string) bool {
+	return strings.EqualFold(want, id) || (name != "" && strings.EqualFold(want, name))
+}
@@ -0,0 +1,246 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+)
+
+// withSessionTimestamps replaces loadSessionTimestamps for the duration of the test.
+func withSessionTimestamps(t *testing.T, ts map[string]time.Time) {
+	t.Helper()
+	orig := loadSessionTimestamps
+	t.Cleanup(func() { loadSessionTimestamps = orig })
+	loadSessionTimestamps = func() map[string]time.Time { return ts }
+}
+
+func filterFixtureSessions() []scamodels.SessionInfo {
+	return []scamodels.SessionInfo{
+		{SessionID: "aws-prod", CSP: scamodels.CSPAWS, WorkspaceID: "111111111111", RoleID: "AdministratorAccess", SessionDuration: 3600},
+		{SessionID: "aws-prod-ro", CSP: scamodels.CSPAWS, WorkspaceID: "111111111111", RoleID: "ReadOnlyAccess", SessionDuration: 3600},
+		{SessionID: "aws-dev", CSP: scamodels.CSPAWS, WorkspaceID: "222222222222", RoleID: "AdministratorAccess", SessionDuration: 3600},
+		{SessionID: "grp-1", CSP: scamodels.CSPAzure, WorkspaceID: "dir-1", SessionDuration: 3600,
+			Target: &scamodels.SessionTarget{ID: "group-id-1", Type: scamodels.TargetTypeGroups}},
+	}
+}
+
+func TestRevokeFilter_Matches(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	fc := sessionFilterContext{
+		nameMap:      map[string]string{"111111111111": "Prod", "222222222222": "Dev"},
+		groupNameMap: map[string]string{"group-id-1": "Cloud Admins"},
+		timestamps: map[string]time.Time{
+			"aws-prod":    now.Add(-3 * time.Hour),
+			"aws-prod-ro": now.Add(-30 * time.Minute),
+		},
+		now: now,
+	}
+
+	tests := []struct {
+		name   string
+		filter sessionFilter
+		want   []string
+	}{
+		{name: "zero filter matches everything", want: []string{"aws-prod", "aws-prod-ro", "aws-dev", "grp-1"}},
+		{name: "target by name, case-insensitive", filter: sessionFilter{target: "prod"}, want: []string{"aws-prod", "aws-prod-ro"}},
+		{name: "target by workspace ID", filter: sessionFilter{target: "222222222222"}, want: []string{"aws-dev"}},
+		{name: "role excludes group sessions", filter: sessionFilter{role: "administratoraccess"}, want: []string{"aws-prod", "aws-dev"}},
+		{name: "target and role", filter: sessionFilter{target: "Prod", role: "ReadOnlyAccess"}, want: []string{"aws-prod-ro"}},
+		{name: "group by name", filter: sessionFilter{group: "cloud admins"}, want: []string{"grp-1"}},
+		{name: "group by ID", filter: sessionFilter{group: "group-id-1"}, want: []string{"grp-1"}},
+		{name: "mine only", filter: sessionFilter{mineOnly: true}, want: []string{"aws-prod", "aws-prod-ro"}},
+		{name: "older than skips unrecorded sessions", filter: sessionFilter{olderThan: 2 * time.Hour}, want: []string{"aws-prod"}},
+		{name: "except", filter: sessionFilter{except: map[string]bool{"aws-prod-ro": true, "grp-1": true}}, want: []string{"aws-prod", "aws-dev"}},
+		{name: "nothing matches", filter: sessionFilter{target: "Staging"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range filterSessions(filterFixtureSessions(), &tt.filter, fc) {
+				got = append(got, s.SessionID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRevokeFilter_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "unparseable older-than", args: []string{"--older-than", "2 hours"}, wantErr: "invalid --older-than"},
+		{name: "negative older-than", args: []string{"--older-than", "-1h"}, wantErr: "must be positive"},
+		{name: "group with target", args: []string{"--group", "x", "--target", "y"}, wantErr: "--group cannot be combined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newRevokeCommand(nil)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags: %v", err)
+			}
+			_, err := parseRevokeFilter(cmd)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRevokeCommand_Filters(t *testing.T) {
+	withSessionTimestamps(t, map[string]time.Time{
+		"aws-prod": time.Now().Add(-3 * time.Hour),
+		"aws-dev":  time.Now().Add(-3 * time.Hour),
+	})
+
+	elig := &mockEligibilityLister{response: &scamodels.EligibilityResponse{
+		Response: []scamodels.EligibleTarget{
+			{WorkspaceID: "111111111111", WorkspaceName: "Prod"},
+			{WorkspaceID: "222222222222", WorkspaceName: "Dev"},
+		},
+	}}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantRevoked []string
+		wantContain []string
+	}{
+		{
+			name:        "all except one",
+			args:        []string{"--all", "--yes", "--except", "aws-prod-ro"},
+			wantRevoked: []string{"aws-prod", "aws-dev", "grp-1"},
+		},
+		{
+			name:        "target and role",
+			args:        []string{"--all", "--yes", "--target", "Prod", "--role", "AdministratorAccess"},
+			wantRevoked: []string{"aws-prod"},
+		},
+		{
+			name:        "mine only and older than compose with except",
+			args:        []string{"--all", "--yes", "--mine-only", "--older-than", "2h", "--except", "aws-dev"},
+			wantRevoked: []string{"aws-prod"},
+		},
+		{
+			name:        "no match is a no-op",
+			args:        []string{"--all", "--yes", "--target", "Staging"},
+			wantContain: []string{"No active sessions match the filters"},
+		},
+		{
+			name:        "dry run revokes nothing",
+			args:        []string{"--all", "--target", "Prod", "--dry-run"},
+			wantContain: []string{"Would revoke 2 sessions", "aws-prod", "aws-prod-ro", "Prod (111111111111)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lister := &mockSessionLister{sessions: &scamodels.SessionsResponse{Response: filterFixtureSessions()}}
+			revoker := &mockSessionRevoker{revokeFunc: func(_ context.Context, req *scamodels.RevokeRequest) (*scamodels.RevokeResponse, error) {
+				resp := &scamodels.RevokeResponse{}
+				for _, id := range req.SessionIDs {
+					resp.Response = append(resp.Response, scamodels.RevocationResult{SessionID: id, RevocationStatus: scamodels.RevocationSuccessful})
+				}
+				return resp, nil
+			}}
+			auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+
+			cmd := NewRevokeCommandWithDeps(auth, lister, elig, revoker, &mockSessionSelector{}, &mockConfirmPrompter{})
+			output, err := executeCommand(cmd, tt.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, output)
+			}
+
+			var revoked []string
+			for _, call := range revoker.calls {
+				revoked = append(revoked, call...)
+			}
+			if !reflect.DeepEqual(revoked, tt.wantRevoked) {
+				t.Errorf("revoked %v, want %v", revoked, tt.wantRevoked)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q\ngot:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestRevokeCommand_FiltersNarrowInteractiveSelection(t *testing.T) {
+	lister := &mockSessionLister{sessions: &scamodels.SessionsResponse{Response: filterFixtureSessions()}}
+	var offered []string
+	selector := &mockSessionSelector{selectFunc: func(sessions []scamodels.SessionInfo, _ map[string]string) ([]scamodels.SessionInfo, error) {
+		for _, s := range sessions {
+			offered = append(offered, s.SessionID)
+		}
+		return nil, nil
+	}}
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+
+	cmd := NewRevokeCommandWithDeps(auth, lister, &mockEligibilityLister{}, &mockSessionRevoker{}, selector, &mockConfirmPrompter{})
+	if _, err := executeCommand(cmd, "--group", "group-id-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"grp-1"}; !reflect.DeepEqual(offered, want) {
+		t.Errorf("selector offered %v, want %v", offered, want)
+	}
+}
+
+func TestRevokeCommand_DryRunJSON(t *testing.T) {
+	lister := &mockSessionLister{sessions: &scamodels.SessionsResponse{Response: filterFixtureSessions()}}
+	revoker := &mockSessionRevoker{}
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+
+	cmd := NewRevokeCommandWithDeps(auth, lister, &mockEligibilityLister{}, revoker, &mockSessionSelector{}, &mockConfirmPrompter{})
+	root := newTestRootCommand()
+	root.AddCommand(cmd)
+
+	stdout, _, err := executeCommandStreams(root, "revoke", "--all", "--role", "ReadOnlyAccess", "--dry-run", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revoker.calls) != 0 {
+		t.Errorf("dry run revoked %v", revoker.calls)
+	}
+
+	var parsed []sessionOutput
+	if err := json.Unmarshal([]byte(stdout), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if len(parsed) != 1 || parsed[0].SessionID != "aws-prod-ro" || parsed[0].Provider != "aws" {
+		t.Errorf("dry run JSON = %+v, want the single aws-prod-ro session", parsed)
+	}
+}
+
+func TestRevokeCommand_FiltersRejectedWithSessionIDs(t *testing.T) {
+	for _, flag := range [][]string{{"--role", "Reader"}, {"--mine-only"}, {"--except", "x"}, {"--dry-run"}} {
+		t.Run(flag[0], func(t *testing.T) {
+			auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt"}}
+			revoker := &mockSessionRevoker{}
+			cmd := NewRevokeCommandWithDeps(auth, &mockSessionLister{}, &mockEligibilityLister{}, revoker, &mockSessionSelector{}, &mockConfirmPrompter{})
+
+			_, err := executeCommand(cmd, append([]string{"session-1"}, flag...)...)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if len(revoker.calls) != 0 {
+				t.Errorf("revoked %v despite the usage error", revoker.calls)
+			}
+		})
+	}
+}
@@ -1,20 +1,146 @@
 package cmd
 
 import (
+	"strings"
 	"time"
 
 	"github.com/aaearon/grant-cli/internal/cache"
+	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
 )
 
+// sessionStoreTTL is the TTL of the session timestamps, which the store shares
+// with the last-known session list. It exceeds the 24h timestamp retention so
+// retention, not the store, decides what is dropped; the session list has the
+// much shorter TTL of the sessions cache policy.
+const sessionStoreTTL = 25 * time.Hour
+
+// newSessionStore opens the store holding session timestamps and the
+// last-known session list, which follows the sessions cache policy.
+func newSessionStore(dir string) *cache.Store {
+	return newCacheStore(dir, sessionStoreTTL).SetPolicy(cache.KindSessions, sessionCachePolicy())
+}
+
+// newOfflineSessionStore opens the session store for 'grant prompt', which
+// must never block: encrypted entries read as misses without a keyring call,
+// and nothing waits for the lock.
+func newOfflineSessionStore(dir string) *cache.Store {
+	return newSessionStore(dir).Offline()
+}
+
 // sessionTimestampRecorder records elevation timestamps. Package-level var for test injection.
 var recordSessionTimestamp = func(sessionID string) {
-	dir, err := cache.CacheDir()
+	dir, err := cacheDir()
 	if err != nil {
 		log.Info("failed to record session timestamp: %v", err)
 		return
 	}
-	store := cache.NewStore(dir, 25*time.Hour)
+	store := newSessionStore(dir)
 	if err := cache.RecordSession(store, sessionID, time.Now()); err != nil {
 		log.Info("failed to record session timestamp: %v", err)
 	}
 }
+
+// loadSessionTimestamps returns the locally recorded elevation timestamps
+// (sessionID -> elevatedAt). Best-effort: an unresolvable cache directory
+// yields an empty map. Package-level var for test injection.
+var loadSessionTimestamps = func() map[string]time.Time {
+	dir, err := cacheDir()
+	if err != nil {
+		log.Info("failed to read session timestamps: %v", err)
+		return map[string]time.Time{}
+	}
+	return cache.SessionTimestamps(newSessionStore(dir))
+}
+
+// loadSessionStarts returns the best locally known start of every tracked
+// session, exact or first observed, for 'grant prompt'. Best-effort: an unresolvable cache
+// directory yields an empty map. Package-level var for test injection.
+var loadSessionStarts = func() map[string]cache.SessionStart {
+	dir, err := cacheDir()
+	if err != nil {
+		log.Info("failed to read session timestamps: %v", err)
+		return map[string]cache.SessionStart{}
+	}
+	return cache.SessionStarts(newOfflineSessionStore(dir))
+}
+
+// withSessionObservation wraps lister so every session it lists gets a
+// first-observed timestamp. Best-effort: lister is returned unwrapped when the
+// cache directory cannot be resolved.
+func withSessionObservation(lister sessionLister) sessionLister {
+	dir, err := cacheDir()
+	if err != nil {
+		log.Info("failed to resolve cache directory: %v", err)
+		return lister
+	}
+	return cache.NewObservingSessionLister(lister, newSessionStore(dir), log)
+}
+
+// rememberSession adds a just-elevated session to the last-known session list
+// read by 'grant prompt'. Best-effort. Package-level var for test injection.
+var rememberSession = func(ks cache.KnownSession) {
+	dir, err := cacheDir()
+	if err != nil {
+		log.Info("failed to record known session: %v", err)
+		return
+	}
+	if err := cache.AddKnownSession(newSessionStore(dir), ks); err != nil {
+		log.Info("failed to record known session: %v", err)
+	}
+}
+
+// forgetSessions removes revoked sessions from the last-known session list.
+// Best-effort. Package-level var for test injection.
+var forgetSessions = func(sessionIDs []string) {
+	dir, err := cacheDir()
+	if err != nil {
+		log.Info("failed to update known sessions: %v", err)
+		return
+	}
+	if err := cache.ForgetSessions(newSessionStore(dir), sessionIDs); err != nil {
+		log.Info("failed to update known sessions: %v", err)
+	}
+}
+
+// loadKnownSessions returns the last-known session list for 'grant prompt'.
+// Best-effort: an
+// unresolvable cache directory yields nil. Package-level var for test injection.
+var loadKnownSessions = func() []cache.KnownSession {
+	dir, err := cacheDir()
+	if err != nil {
+		log.Info("failed to read known sessions: %v", err)
+		return nil
+	}
+	return cache.KnownSessions(newOfflineSessionStore(dir))
+}
+
+// knownSessionsFrom converts listed sessions to last-known session rows,
+// resolving names through the maps where possible.
+func knownSessionsFrom(sessions []scamodels.SessionInfo, nameMap, groupNameMap map[string]string) []cache.KnownSession {
+	known := make([]cache.KnownSession, 0, len(sessions))
+	for _, s := range sessions {
+		ks := cache.KnownSession{
+			SessionID:       s.SessionID,
+			Provider:        strings.ToLower(string(s.CSP)),
+			DurationSeconds: s.SessionDuration,
+		}
+		if s.IsGroupSession() {
+			ks.Type = "group"
+			ks.Target = nameOrID(groupNameMap, s.Target.ID)
+		} else {
+			ks.Type = "cloud"
+			ks.Target = nameOrID(nameMap, s.WorkspaceID)
+			ks.Role = s.RoleID
+		}
+		known = append(known, ks)
+	}
+	return known
+}
+
+// nameOrID returns the resolved name for id, or id itself when unresolved.
+func nameOrID(names map[string]string, id string) string {
+	if name, ok := names[id]; ok && name != "" {
+		return name
+	}
+	return id
+}
@@ -20,11 +20,21 @@ func newStatusCommand(runFn func(*cobra.Command, []string) error) *cobra.Command
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show authentication state and active SCA sessions",
-		Long:  "Display the current authentication state and list all active elevated sessions.",
-		RunE:  runFn,
+		Long: `Display the current authentication state and list all active elevated sessions.
+
+With --require, status instead asserts that a matching live session exists and
+exits non-zero with a machine-readable reason when it does not. Keys are
+provider, target, role and group; --min-remaining additionally requires that
+much time left on the session.`,
+		Example: `  grant status
+  grant status --require provider=aws,target=Prod,role=Admin --min-remaining 15m
+  grant status --require group="Cloud Admins" --output json`,
+		RunE: runFn,
 	}
 
-	cmd.Flags().StringP("provider", "p", "", "filter sessions by provider (azure, aws, gcp)")
+	cmd.Flags().StringP("provider", "p", "", "filter sessions by provider (azure, aws, gcp), or all to resolve names across every provider")
+	cmd.Flags().StringSlice("require", nil, "assert a matching live session exists (key=value: provider, target, role, group)")
+	cmd.Flags().Duration("min-remaining", 0, "with --require, also require at least this much remaining time (e.g. 15m)")
 
 	return cmd
 }
@@ -49,8 +59,8 @@ func NewStatusCommand() *cobra.Command {
 
 		// Build session timestamp tracker (best-effort)
 		var tracker *cache.Store
-		if cacheDir, err := cache.CacheDir(); err == nil {
-			tracker = cache.NewStore(cacheDir, 25*time.Hour)
+		if dir, err := cacheDir(); err == nil {
+			tracker = newSessionStore(dir)
 		}
 
 		return runStatus(cmd, ispAuth, svc, cachedLister, cachedLister, tracker, profile)
@@ -79,11 +89,20 @@ func runStatus(
 	tracker *cache.Store,
 	profile *models.IdsecProfile,
 ) error {
+	req, err := parseStatusRequirement(cmd)
+	if err != nil {
+		return withCode(codeUsage, err)
+	}
+
 	// Load authentication state
 	token, err := authLoader.LoadAuthentication(profile, true)
 	if err != nil {
-		if isJSONOutput() {
-			return writeJSON(cmd.OutOrStdout(), statusOutput{Authenticated: false, Sessions: []sessionOutput{}})
+		if req != nil {
+			out := requirementOutput{Reason: requireReasonNotAuthenticated, Message: "not authenticated; run 'grant login' first"}
+			return reportRequirement(cmd, out, scamodels.SessionInfo{}, nil)
+		}
+		if isStructuredOutput() {
+			return writeOutput(cmd.OutOrStdout(), statusOutput{Authenticated: false, Sessions: []sessionOutput{}})
 		}
 		fmt.Fprintf(cmd.OutOrStdout(), "Not authenticated. Run 'grant login' first.\n")
 		return nil
@@ -91,8 +110,11 @@ func runStatus(
 
 	// Parse provider filter if specified
 	provider, _ := cmd.Flags().GetString("provider")
+	if req != nil && req.provider != "" {
+		provider = req.provider
+	}
 	var cspFilter *scamodels.CSP
-	if provider != "" {
+	if isProviderFilter(provider) {
 		csp, err := parseProvider(provider)
 		if err != nil {
 			return err
@@ -100,10 +122,16 @@ func runStatus(
 		cspFilter = &csp
 	}
 
+	// Record every listed session, so sessions elevated elsewhere get a
+	// first-observed timestamp (best-effort)
+	if tracker != nil {
+		sessionLister = cache.NewObservingSessionLister(sessionLister, tracker, log)
+	}
+
 	// Fetch sessions and eligibility concurrently
 	ctx, cancel := context.WithTimeout(context.Background(), apiTimeout)
 	defer cancel()
-	data, err := fetchStatusData(ctx, sessionLister, eligLister, cspFilter)
+	data, err := fetchStatusData(ctx, sessionLister, eligLister, cspFilter, providerScope(provider))
 	if err != nil {
 		return err
 	}
@@ -121,18 +149,37 @@ func runStatus(
 
 	// Compute remaining time from local session timestamps (best-effort)
 	if tracker != nil {
-		timestamps := cache.SessionTimestamps(tracker)
-		data.remainingMap = computeRemainingTime(data.sessions.Response, timestamps)
+		starts := cache.SessionStarts(tracker)
+		data.remainingMap, data.upperBounds = computeRemainingTime(data.sessions.Response, starts)
+
+		// Lazy cleanup of stale session timestamps. A provider-filtered
+		// listing does not show the other providers' sessions, so only an
+		// unfiltered one can prove a session gone.
+		if cspFilter == nil {
+			activeIDs := make([]string, len(data.sessions.Response))
+			for i, s := range data.sessions.Response {
+				activeIDs[i] = s.SessionID
+			}
+			_ = cache.CleanupSessions(tracker, activeIDs)
+		}
 
-		// Lazy cleanup of stale session timestamps
-		activeIDs := make([]string, len(data.sessions.Response))
-		for i, s := range data.sessions.Response {
-			activeIDs[i] = s.SessionID
+		// Refresh the last-known session list read offline by 'grant prompt'
+		var listedProvider string
+		if cspFilter != nil {
+			listedProvider = strings.ToLower(string(*cspFilter))
 		}
-		_ = cache.CleanupSessions(tracker, activeIDs)
+		known := knownSessionsFrom(data.sessions.Response, data.nameMap, data.groupNameMap)
+		if err := cache.SaveSessionSnapshot(tracker, listedProvider, known); err != nil {
+			log.Info("failed to save known sessions: %v", err)
+		}
+	}
+
+	if req != nil {
+		out, session := evaluateRequirement(req, data)
+		return reportRequirement(cmd, out, session, data)
 	}
 
-	if isJSONOutput() {
+	if isStructuredOutput() {
 		return writeStatusJSON(cmd, token.Username, data)
 	}
 
@@ -163,7 +210,7 @@ func runStatus(
 		for _, p := range sortedProviders(sessionsByProvider) {
 			fmt.Fprintf(cmd.OutOrStdout(), "%s sessions:\n", formatProviderName(p))
 			for _, session := range sessionsByProvider[p] {
-				fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", ui.FormatSessionOption(session, data.nameMap, data.groupNameMap, data.remainingMap))
+				fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", ui.FormatSessionOption(session, data.nameMap, data.groupNameMap, data.remainingMap, data.upperBounds))
 			}
 		}
 	}
@@ -172,33 +219,42 @@ func runStatus(
 	if len(groupSessions) > 0 {
 		fmt.Fprintf(cmd.OutOrStdout(), "Groups sessions:\n")
 		for _, session := range groupSessions {
-			fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", ui.FormatSessionOption(session, data.nameMap, data.groupNameMap, data.remainingMap))
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", ui.FormatSessionOption(session, data.nameMap, data.groupNameMap, data.remainingMap, data.upperBounds))
 		}
 	}
 
 	return nil
 }
 
-// computeRemainingTime builds a sessionID -> remaining duration map from local timestamps.
-func computeRemainingTime(sessions []scamodels.SessionInfo, timestamps map[string]time.Time) map[string]time.Duration {
-	if len(timestamps) == 0 {
-		return nil
+// computeRemainingTime builds a sessionID -> remaining duration map from local
+// session starts. Remaining time counted from a first observation rather than
+// a local elevation is an upper bound, and is marked in the second map.
+func computeRemainingTime(sessions []scamodels.SessionInfo, starts map[string]cache.SessionStart) (map[string]time.Duration, map[string]bool) {
+	if len(starts) == 0 {
+		return nil, nil
 	}
 
 	now := time.Now()
 	remaining := make(map[string]time.Duration)
+	upperBounds := make(map[string]bool)
 	for _, s := range sessions {
-		if elevatedAt, ok := timestamps[s.SessionID]; ok {
-			elapsed := now.Sub(elevatedAt)
+		if start, ok := starts[s.SessionID]; ok {
+			elapsed := now.Sub(start.At)
 			totalDuration := time.Duration(s.SessionDuration) * time.Second
 			remaining[s.SessionID] = totalDuration - elapsed
+			if !start.Exact {
+				upperBounds[s.SessionID] = true
+			}
 		}
 	}
 
 	if len(remaining) == 0 {
-		return nil
+		return nil, nil
 	}
-	return remaining
+	if len(upperBounds) == 0 {
+		upperBounds = nil
+	}
+	return remaining, upperBounds
 }
 
 // writeStatusJSON outputs the status as JSON.
@@ -210,37 +266,49 @@ func writeStatusJSON(cmd *cobra.Command, username string, data *statusData) erro
 	}
 
 	for _, s := range data.sessions.Response {
-		so := sessionOutput{
-			SessionID:   s.SessionID,
-			Provider:    strings.ToLower(string(s.CSP)),
-			WorkspaceID: s.WorkspaceID,
-			Duration:    s.SessionDuration,
-			RoleID:      s.RoleID,
-		}
-		if name, ok := data.nameMap[s.WorkspaceID]; ok {
-			so.WorkspaceName = name
-		}
-		if s.IsGroupSession() {
-			so.Type = "group"
-			so.GroupID = s.Target.ID
-			if data.groupNameMap != nil {
-				if name, ok := data.groupNameMap[s.Target.ID]; ok {
-					so.GroupName = name
-				}
-			}
-		} else {
-			so.Type = "cloud"
+		out.Sessions = append(out.Sessions, buildSessionOutput(s, data.nameMap, data.groupNameMap, data.remainingMap, data.upperBounds))
+	}
+
+	return writeOutput(cmd.OutOrStdout(), out)
+}
+
+// buildSessionOutput converts one session to its JSON representation. The
+// maps are nil-safe; an unresolved name or remaining time is omitted.
+func buildSessionOutput(
+	s scamodels.SessionInfo,
+	nameMap map[string]string,
+	groupNameMap map[string]string,
+	remainingMap map[string]time.Duration,
+	upperBounds map[string]bool,
+) sessionOutput {
+	so := sessionOutput{
+		SessionID:   s.SessionID,
+		Provider:    strings.ToLower(string(s.CSP)),
+		WorkspaceID: s.WorkspaceID,
+		Duration:    s.SessionDuration,
+		RoleID:      s.RoleID,
+	}
+	if name, ok := nameMap[s.WorkspaceID]; ok {
+		so.WorkspaceName = name
+	}
+	if s.IsGroupSession() {
+		so.Type = "group"
+		so.GroupID = s.Target.ID
+		if name, ok := groupNameMap[s.Target.ID]; ok {
+			so.GroupName = name
 		}
-		if data.remainingMap != nil {
-			if rem, ok := data.remainingMap[s.SessionID]; ok {
-				secs := int(rem.Seconds())
-				so.RemainingSeconds = &secs
-			}
+	} else {
+		so.Type = "cloud"
+	}
+	if rem, ok := remainingMap[s.SessionID]; ok {
+		secs := int(rem.Seconds())
+		so.RemainingSeconds = &secs
+		so.RemainingBasis = remainingBasisElevated
+		if upperBounds[s.SessionID] {
+			so.RemainingBasis = remainingBasisFirstObserved
 		}
-		out.Sessions = append(out.Sessions, so)
 	}
-
-	return writeJSON(cmd.OutOrStdout(), out)
+	return so
 }
 
 // parseProvider converts a provider string to a CSP enum
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/aaearon/grant-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// errRequirementNotMet is returned by 'grant status --require' when no live
+// session satisfies the requirement. The reason is carried in the message and,
+// with --output json, in the requirementOutput written to stdout.
+var errRequirementNotMet = errors.New("session requirement not met")
+
+// Machine-readable reasons a requirement was not met.
+const (
+	requireReasonNotAuthenticated      = "not_authenticated"
+	requireReasonNoMatchingSession     = "no_matching_session"
+	requireReasonInsufficientRemaining = "insufficient_remaining"
+	requireReasonRemainingUnknown      = "remaining_unknown"
+)
+
+// statusRequirement is a parsed --require / --min-remaining assertion.
+type statusRequirement struct {
+	provider     string
+	filter       sessionFilter
+	minRemaining time.Duration
+}
+
+// parseStatusRequirement reads --require and --min-remaining. It returns nil
+// when neither flag was given.
+func parseStatusRequirement(cmd *cobra.Command) (*statusRequirement, error) {
+	pairs, _ := cmd.Flags().GetStringSlice("require")
+	minRemaining, _ := cmd.Flags().GetDuration("min-remaining")
+	if len(pairs) == 0 && !cmd.Flags().Changed("min-remaining") {
+		return nil, nil
+	}
+	if minRemaining < 0 {
+		return nil, fmt.Errorf("invalid --min-remaining %s: must not be negative", minRemaining)
+	}
+
+	req := &statusRequirement{minRemaining: minRemaining}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid --require %q: must be key=value", pair)
+		}
+		switch key {
+		case "provider":
+			if _, err := parseProvider(value); err != nil {
+				return nil, err
+			}
+			req.provider = value
+		case "target":
+			req.filter.target = value
+		case "role":
+			req.filter.role = value
+		case "group":
+			req.filter.group = value
+		default:
+			return nil, fmt.Errorf("invalid --require key %q: must be one of: provider, target, role, group", key)
+		}
+	}
+
+	if (req.filter.target != "" || req.filter.role != "") && req.filter.group != "" {
+		return nil, errors.New("--require group cannot be combined with target or role")
+	}
+
+	if provider, _ := cmd.Flags().GetString("provider"); provider != "" && req.provider != "" && !strings.EqualFold(provider, req.provider) {
+		return nil, fmt.Errorf("--provider %q conflicts with --require provider=%s", provider, req.provider)
+	}
+
+	return req, nil
+}
+
+// evaluateRequirement picks the matching session with the most remaining time
+// and reports whether it satisfies the requirement. The returned session is the
+// one that satisfied it, or the zero value on failure.
+//
+// Remaining time is only exact for sessions grant elevated locally; for a
+// session first observed in a listing it is an upper bound. When
+// --min-remaining is set, a match whose remaining time is unknown, or an upper
+// bound that is not below the minimum, cannot prove it has enough left, so it
+// fails with remaining_unknown rather than passing.
+func evaluateRequirement(req *statusRequirement, data *statusData) (requirementOutput, scamodels.SessionInfo) {
+	fc := sessionFilterContext{nameMap: data.nameMap, groupNameMap: data.groupNameMap}
+	matched := filterSessions(data.sessions.Response, &req.filter, fc)
+	if len(matched) == 0 {
+		return requirementOutput{
+			Reason:  requireReasonNoMatchingSession,
+			Message: "no active session matches the requirement",
+		}, scamodels.SessionInfo{}
+	}
+
+	if req.minRemaining == 0 {
+		best := matched[0]
+		for _, s := range matched[1:] {
+			if remainingOf(s, data.remainingMap) > remainingOf(best, data.remainingMap) {
+				best = s
+			}
+		}
+		return satisfiedRequirement(best, data), best
+	}
+
+	var (
+		best        *scamodels.SessionInfo
+		bestLeft    time.Duration
+		unknownSeen bool
+	)
+	for i, s := range matched {
+		left, ok := data.remainingMap[s.SessionID]
+		if !ok || (data.upperBounds[s.SessionID] && left >= req.minRemaining) {
+			unknownSeen = true
+			continue
+		}
+		if best == nil || left > bestLeft {
+			best, bestLeft = &matched[i], left
+		}
+	}
+
+	if best != nil && bestLeft >= req.minRemaining {
+		return satisfiedRequirement(*best, data), *best
+	}
+	if unknownSeen {
+		return requirementOutput{
+			Reason: requireReasonRemainingUnknown,
+			Message: fmt.Sprintf("a matching session exists but its remaining time cannot be confirmed (it was not elevated from this machine); need %s",
+				req.minRemaining),
+		}, scamodels.SessionInfo{}
+	}
+	so := buildSessionOutput(*best, data.nameMap, data.groupNameMap, data.remainingMap, data.upperBounds)
+	return requirementOutput{
+		Reason:  requireReasonInsufficientRemaining,
+		Message: fmt.Sprintf("best matching session has %s left, need %s", bestLeft.Truncate(time.Minute), req.minRemaining),
+		Session: &so,
+	}, scamodels.SessionInfo{}
+}
+
+// remainingOf returns a session's remaining time, or -1 when it is unknown.
+func remainingOf(s scamodels.SessionInfo, remainingMap map[string]time.Duration) time.Duration {
+	if left, ok := remainingMap[s.SessionID]; ok {
+		return left
+	}
+	return -1
+}
+
+func satisfiedRequirement(s scamodels.SessionInfo, data *statusData) requirementOutput {
+	so := buildSessionOutput(s, data.nameMap, data.groupNameMap, data.remainingMap, data.upperBounds)
+	return requirementOutput{Satisfied: true, Session: &so}
+}
+
+// reportRequirement writes the requirement result and returns
+// errRequirementNotMet when it failed. JSON output goes to stdout even on
+// failure so scripts can read the reason.
+func reportRequirement(cmd *cobra.Command, out requirementOutput, session scamodels.SessionInfo, data *statusData) error {
+	if isStructuredOutput() {
+		if err := writeOutput(cmd.OutOrStdout(), out); err != nil {
+			return err
+		}
+	} else if out.Satisfied {
+		fmt.Fprintf(cmd.OutOrStdout(), "Requirement met: %s\n", ui.FormatSessionOption(session, data.nameMap, data.groupNameMap, data.remainingMap, data.upperBounds))
+	}
+
+	if out.Satisfied {
+		return nil
+	}
+	return fmt.Errorf("%w (%s): %s", errRequirementNotMet, out.Reason, out.Message)
+}
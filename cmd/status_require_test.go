@@ -0,0 +1,197 @@
+// NOTE: Do not use t.Parallel() in cmd/ tests due to package-level state
+// (verbose, passedArgValidation) that is mutated during test execution.
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/cache"
+	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
+	authmodels "github.com/cyberark/idsec-sdk-golang/pkg/models/auth"
+)
+
+type statusRequireFixture struct {
+	auth     *mockAuthLoader
+	sessions *mockSessionLister
+	elig     *mockEligibilityLister
+	tracker  *cache.Store
+}
+
+// requireFixture returns a status command over two AWS sessions on "Prod":
+// "tracked" was elevated locally 10m into a 1h session, "untracked" was not,
+// so status first observes it now and knows only an upper bound (1h).
+func requireFixture(t *testing.T, auth *mockAuthLoader) *statusRequireFixture {
+	t.Helper()
+	sessions := &mockSessionLister{sessions: &scamodels.SessionsResponse{
+		Response: []scamodels.SessionInfo{
+			{SessionID: "tracked", CSP: scamodels.CSPAWS, WorkspaceID: "111111111111", RoleID: "Admin", SessionDuration: 3600},
+			{SessionID: "untracked", CSP: scamodels.CSPAWS, WorkspaceID: "111111111111", RoleID: "ReadOnly", SessionDuration: 3600},
+		},
+	}}
+	elig := &mockEligibilityLister{response: &scamodels.EligibilityResponse{
+		Response: []scamodels.EligibleTarget{{WorkspaceID: "111111111111", WorkspaceName: "Prod", CSP: scamodels.CSPAWS}},
+	}}
+	tracker := cache.NewStore(t.TempDir(), 25*time.Hour)
+	if err := cache.RecordSession(tracker, "tracked", time.Now().Add(-10*time.Minute)); err != nil {
+		t.Fatalf("RecordSession() error = %v", err)
+	}
+	return &statusRequireFixture{auth: auth, sessions: sessions, elig: elig, tracker: tracker}
+}
+
+func (f *statusRequireFixture) run(args ...string) (string, error) {
+	root := newTestRootCommand()
+	root.AddCommand(NewStatusCommandWithDeps(f.auth, f.sessions, f.elig, nil, f.tracker))
+	stdout, _, err := executeCommandStreams(root, append([]string{"status"}, args...)...)
+	return stdout, err
+}
+
+func TestStatusCommand_Require(t *testing.T) {
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt", Username: "user@test.com"}}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantReason  string // "" = satisfied
+		wantSession string
+	}{
+		{
+			name:        "target name and role match",
+			args:        []string{"--require", "provider=aws,target=prod,role=admin"},
+			wantSession: "tracked",
+		},
+		{
+			name:        "enough remaining time",
+			args:        []string{"--require", "target=Prod,role=Admin", "--min-remaining", "45m"},
+			wantSession: "tracked",
+		},
+		{
+			name:        "min-remaining alone picks any tracked session",
+			args:        []string{"--min-remaining", "15m"},
+			wantSession: "tracked",
+		},
+		{
+			name:       "not enough remaining time",
+			args:       []string{"--require", "target=Prod,role=Admin", "--min-remaining", "55m"},
+			wantReason: requireReasonInsufficientRemaining,
+		},
+		{
+			name:       "upper bound below the minimum proves too little",
+			args:       []string{"--require", "role=ReadOnly", "--min-remaining", "2h"},
+			wantReason: requireReasonInsufficientRemaining,
+		},
+		{
+			name:       "upper bound above the minimum cannot prove enough",
+			args:       []string{"--require", "role=ReadOnly", "--min-remaining", "1m"},
+			wantReason: requireReasonRemainingUnknown,
+		},
+		{
+			name:       "no matching role",
+			args:       []string{"--require", "target=Prod,role=Owner"},
+			wantReason: requireReasonNoMatchingSession,
+		},
+		{
+			name:       "group never matches cloud sessions",
+			args:       []string{"--require", "group=Prod"},
+			wantReason: requireReasonNoMatchingSession,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := requireFixture(t, auth)
+			stdout, err := f.run(append(tt.args, "--output", "json")...)
+
+			var out requirementOutput
+			if jerr := json.Unmarshal([]byte(stdout), &out); jerr != nil {
+				t.Fatalf("invalid JSON: %v\n%s", jerr, stdout)
+			}
+
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !out.Satisfied || out.Session == nil || out.Session.SessionID != tt.wantSession {
+					t.Errorf("got %+v, want satisfied by %s", out, tt.wantSession)
+				}
+				return
+			}
+
+			if !errors.Is(err, errRequirementNotMet) {
+				t.Fatalf("error = %v, want errRequirementNotMet", err)
+			}
+			if out.Satisfied || out.Reason != tt.wantReason {
+				t.Errorf("got satisfied=%v reason=%q, want reason %q", out.Satisfied, out.Reason, tt.wantReason)
+			}
+			if !strings.Contains(err.Error(), tt.wantReason) {
+				t.Errorf("error %q should name the reason %q", err, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestStatusCommand_RequireText(t *testing.T) {
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt", Username: "user@test.com"}}
+	f := requireFixture(t, auth)
+
+	stdout, err := f.run("--require", "target=Prod,role=Admin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(stdout, "Requirement met: ") || !strings.Contains(stdout, "Prod") {
+		t.Errorf("unexpected output:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "Authenticated as") {
+		t.Errorf("--require should print only the assertion result, got:\n%s", stdout)
+	}
+}
+
+func TestStatusCommand_RequireNotAuthenticated(t *testing.T) {
+	auth := &mockAuthLoader{loadErr: errors.New("no cached authentication")}
+	f := requireFixture(t, auth)
+
+	stdout, err := f.run("--require", "target=Prod", "--output", "json")
+	if !errors.Is(err, errRequirementNotMet) {
+		t.Fatalf("error = %v, want errRequirementNotMet", err)
+	}
+	var out requirementOutput
+	if jerr := json.Unmarshal([]byte(stdout), &out); jerr != nil {
+		t.Fatalf("invalid JSON: %v\n%s", jerr, stdout)
+	}
+	if out.Reason != requireReasonNotAuthenticated {
+		t.Errorf("reason = %q, want %q", out.Reason, requireReasonNotAuthenticated)
+	}
+}
+
+func TestStatusCommand_RequireErrors(t *testing.T) {
+	auth := &mockAuthLoader{token: &authmodels.IdsecToken{Token: "jwt", Username: "user@test.com"}}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"unknown key", []string{"--require", "account=Prod"}, `invalid --require key "account"`},
+		{"missing value", []string{"--require", "target"}, `invalid --require "target"`},
+		{"invalid provider", []string{"--require", "provider=oci"}, `invalid provider "oci"`},
+		{"group with role", []string{"--require", "group=Admins,role=Admin"}, "--require group cannot be combined"},
+		{"conflicting provider", []string{"--provider", "azure", "--require", "provider=aws"}, "conflicts with --require provider=aws"},
+		{"negative min-remaining", []string{"--min-remaining", "-5m"}, "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := requireFixture(t, auth)
+			_, err := f.run(tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if errors.Is(err, errRequirementNotMet) {
+				t.Errorf("flag errors must not be reported as an unmet requirement")
+			}
+		})
+	}
+}
@@ -1031,9 +1031,10 @@ func TestStatusCommand_RemainingTime(t *testing.T) {
 		if !strings.Contains(output, "remaining: 45m") {
 			t.Errorf("output should show 'remaining: 45m', got:\n%s", output)
 		}
-		// Untracked session should show duration
-		if !strings.Contains(output, "duration: 30m") {
-			t.Errorf("untracked session should show duration, got:\n%s", output)
+		// A session elevated elsewhere is first observed by this listing, so
+		// its full duration is shown as an upper bound
+		if !strings.Contains(output, "remaining: at most 29m") {
+			t.Errorf("untracked session should show an upper bound, got:\n%s", output)
 		}
 	})
 
@@ -1074,12 +1075,18 @@ func TestStatusCommand_RemainingTime(t *testing.T) {
 		} else if *tracked.RemainingSeconds < 2500 || *tracked.RemainingSeconds > 2800 {
 			t.Errorf("remainingSeconds = %d, expected ~2700 (45 min)", *tracked.RemainingSeconds)
 		}
+		if tracked.RemainingBasis != remainingBasisElevated {
+			t.Errorf("tracked remainingBasis = %q, want %q", tracked.RemainingBasis, remainingBasisElevated)
+		}
 
 		if untracked == nil {
 			t.Fatal("untracked session not found in output")
 		}
-		if untracked.RemainingSeconds != nil {
-			t.Errorf("untracked session should not have remainingSeconds, got %d", *untracked.RemainingSeconds)
+		if untracked.RemainingSeconds == nil || *untracked.RemainingSeconds > 1800 {
+			t.Errorf("untracked session should have an upper-bound remainingSeconds <= 1800, got %v", untracked.RemainingSeconds)
+		}
+		if untracked.RemainingBasis != remainingBasisFirstObserved {
+			t.Errorf("untracked remainingBasis = %q, want %q", untracked.RemainingBasis, remainingBasisFirstObserved)
 		}
 	})
 
@@ -1104,40 +1111,54 @@ func TestStatusCommand_RemainingTime(t *testing.T) {
 
 func TestComputeRemainingTime(t *testing.T) {
 	tests := []struct {
-		name       string
-		sessions   []scamodels.SessionInfo
-		timestamps map[string]time.Time
-		wantNil    bool
-		wantKeys   []string
+		name      string
+		sessions  []scamodels.SessionInfo
+		starts    map[string]cache.SessionStart
+		wantNil   bool
+		wantKeys  []string
+		wantUpper []string
 	}{
 		{
-			name:       "empty timestamps returns nil",
-			sessions:   []scamodels.SessionInfo{{SessionID: "s1"}},
-			timestamps: map[string]time.Time{},
-			wantNil:    true,
+			name:     "empty starts returns nil",
+			sessions: []scamodels.SessionInfo{{SessionID: "s1"}},
+			starts:   map[string]cache.SessionStart{},
+			wantNil:  true,
 		},
 		{
-			name:       "no matching sessions returns nil",
-			sessions:   []scamodels.SessionInfo{{SessionID: "s1"}},
-			timestamps: map[string]time.Time{"other": time.Now()},
-			wantNil:    true,
+			name:     "no matching sessions returns nil",
+			sessions: []scamodels.SessionInfo{{SessionID: "s1"}},
+			starts:   map[string]cache.SessionStart{"other": {At: time.Now(), Exact: true}},
+			wantNil:  true,
 		},
 		{
 			name: "matching session computes remaining",
 			sessions: []scamodels.SessionInfo{
 				{SessionID: "s1", SessionDuration: 3600},
 			},
-			timestamps: map[string]time.Time{"s1": time.Now().Add(-15 * time.Minute)},
-			wantKeys:   []string{"s1"},
+			starts:   map[string]cache.SessionStart{"s1": {At: time.Now().Add(-15 * time.Minute), Exact: true}},
+			wantKeys: []string{"s1"},
+		},
+		{
+			name: "first-observed session is an upper bound",
+			sessions: []scamodels.SessionInfo{
+				{SessionID: "local", SessionDuration: 3600},
+				{SessionID: "remote", SessionDuration: 3600},
+			},
+			starts: map[string]cache.SessionStart{
+				"local":  {At: time.Now().Add(-15 * time.Minute), Exact: true},
+				"remote": {At: time.Now().Add(-5 * time.Minute)},
+			},
+			wantKeys:  []string{"local", "remote"},
+			wantUpper: []string{"remote"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := computeRemainingTime(tt.sessions, tt.timestamps)
+			result, upperBounds := computeRemainingTime(tt.sessions, tt.starts)
 			if tt.wantNil {
-				if result != nil {
-					t.Errorf("expected nil, got %v", result)
+				if result != nil || upperBounds != nil {
+					t.Errorf("expected nil, got %v, %v", result, upperBounds)
 				}
 				return
 			}
@@ -1146,6 +1167,14 @@ func TestComputeRemainingTime(t *testing.T) {
 					t.Errorf("expected key %q in result", key)
 				}
 			}
+			if len(upperBounds) != len(tt.wantUpper) {
+				t.Errorf("upperBounds = %v, want %v", upperBounds, tt.wantUpper)
+			}
+			for _, key := range tt.wantUpper {
+				if !upperBounds[key] {
+					t.Errorf("expected %q to be an upper bound", key)
+				}
+			}
 		})
 	}
 }
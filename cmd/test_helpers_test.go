@@ -125,6 +125,10 @@ func executeCommand(cmd *cobra.Command, args ...string) (string, error) {
 func restoreCommandGlobals(savedOutput string, savedVerbose bool) {
 	outputFormat = savedOutput
 	verbose = savedVerbose
+	// --profile is a persistent flag bound to a package-level variable.
+	profileFlag = ""
+	// --output ndjson opens the event stream on the command's stdout buffer.
+	startEvents(nil)
 }
 
 // executeCommandStreams executes a command keeping stdout and stderr apart and
@@ -155,11 +159,9 @@ func executeWithHint(cmd *cobra.Command, args []string) string {
 	if err == nil {
 		return ""
 	}
-	out := err.Error() + "\n"
-	// Call the production predicate rather than restating it, so this helper
-	// cannot drift away from Execute().
-	if shouldShowVerboseHint(verbose, passedArgValidation) {
-		out += "Hint: re-run with --verbose for more details\n"
-	}
-	return out
+	// Call the production functions rather than restating them, so this
+	// helper cannot drift away from Execute().
+	var buf bytes.Buffer
+	reportError(&buf, classifyError(err, passedArgValidation), shouldShowVerboseHint(verbose, passedArgValidation))
+	return buf.String()
 }
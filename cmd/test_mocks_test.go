@@ -58,6 +58,31 @@ func (m *mockEligibilityLister) ListEligibility(ctx context.Context, csp models.
 	return m.response, m.listErr
 }
 
+// targetsLister answers each CSP with those of targets that have it, as
+// `favorites add --target/--role` needs to resolve its flags.
+func targetsLister(targets ...models.EligibleTarget) *mockEligibilityLister {
+	return &mockEligibilityLister{listFunc: func(_ context.Context, csp models.CSP) (*models.EligibilityResponse, error) {
+		resp := &models.EligibilityResponse{}
+		for _, t := range targets {
+			if t.CSP == csp {
+				resp.Response = append(resp.Response, t)
+			}
+		}
+		resp.Total = len(resp.Response)
+		return resp, nil
+	}}
+}
+
+// eligibleTarget is a target of csp whose IDs derive from its names.
+func eligibleTarget(csp models.CSP, workspace, role string) models.EligibleTarget {
+	return models.EligibleTarget{
+		CSP:           csp,
+		WorkspaceID:   "ws-" + workspace,
+		WorkspaceName: workspace,
+		RoleInfo:      models.RoleInfo{ID: "role-" + role, Name: role},
+	}
+}
+
 // mockElevateService implements the elevateService interface for testing.
 //
 // Capture convention (see mockSessionRevoker): the request is recorded in the
@@ -202,9 +227,11 @@ func (m *mockProfileSaver) SaveProfile(profile *sdkmodels.IdsecProfile) error {
 type mockKeyringClearer struct {
 	clearFunc func() error
 	clearErr  error
+	cleared   int
 }
 
 func (m *mockKeyringClearer) ClearAllPasswords() error {
+	m.cleared++
 	if m.clearFunc != nil {
 		return m.clearFunc()
 	}
@@ -18,9 +18,19 @@ type entry[T any] struct {
 
 // Store manages a directory of JSON cache files with TTL expiry.
 type Store struct {
-	dir string
-	ttl time.Duration
-	now func() time.Time // injectable clock for testing
+	dir      string
+	ttl      time.Duration
+	now      func() time.Time  // injectable clock for testing
+	sealer   *sealer           // nil unless Encrypt was called
+	offline  bool              // see Offline
+	policies map[string]Policy // by kind; see SetPolicy
+}
+
+// Policy is how a Store treats the entries of one kind.
+type Policy struct {
+	TTL      time.Duration
+	MaxStale time.Duration // how long past TTL a decorator may serve an entry when the API is down
+	Disabled bool          // entries are neither read nor written
 }
 
 // NewStore creates a Store with the given directory and TTL.
@@ -28,43 +38,192 @@ func NewStore(dir string, ttl time.Duration) *Store {
 	return &Store{dir: dir, ttl: ttl, now: time.Now}
 }
 
+// TTL returns how long the Store's entries stay fresh.
+func (s *Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// SetPolicy applies p to the entries of kind in place of the Store's TTL and
+// the decorators' stale setting. Session timestamps are local records rather
+// than cached API data and keep the Store's TTL whatever the sessions policy.
+func (s *Store) SetPolicy(kind string, p Policy) *Store {
+	if s.policies == nil {
+		s.policies = make(map[string]Policy)
+	}
+	s.policies[kind] = p
+	return s
+}
+
+// policy returns the policy of the entry under key, and whether one was set
+// for its kind. Without one, the entry has the Store's TTL.
+func (s *Store) policy(key string) (Policy, bool) {
+	if key != sessionTimestampsKey {
+		if p, ok := s.policies[KindOf(key)]; ok {
+			return p, true
+		}
+	}
+	return Policy{TTL: s.ttl}, false
+}
+
+// TTLOf returns how long the entry under key stays fresh.
+func (s *Store) TTLOf(key string) time.Duration {
+	p, _ := s.policy(key)
+	return p.TTL
+}
+
 // Get reads a cached value for key into dst. Returns true on hit, false on miss/expiry/error.
 func Get[T any](s *Store, key string, dst *T) bool {
-	data, err := os.ReadFile(filepath.Join(s.dir, key+".json"))
-	if err != nil {
+	p, _ := s.policy(key)
+	if p.Disabled {
 		return false
 	}
-
-	var e entry[T]
-	if err := json.Unmarshal(data, &e); err != nil {
+	e, ok := read[T](s, key)
+	if !ok || s.now().Sub(e.CachedAt) > p.TTL {
 		return false
 	}
 
-	if s.now().Sub(e.CachedAt) > s.ttl {
-		return false
+	*dst = e.Response
+	return true
+}
+
+// GetStale reads a cached value for key into dst even if it has expired, as
+// long as it is no more than maxStale past the TTL. It returns the entry's
+// age. Callers use it only when fresh data cannot be had.
+func GetStale[T any](s *Store, key string, dst *T, maxStale time.Duration) (time.Duration, bool) {
+	p, _ := s.policy(key)
+	if p.Disabled {
+		return 0, false
+	}
+	e, ok := read[T](s, key)
+	if !ok {
+		return 0, false
+	}
+	age := s.now().Sub(e.CachedAt)
+	if age > p.TTL+maxStale {
+		return 0, false
 	}
 
 	*dst = e.Response
-	return true
+	return age, true
+}
+
+// read decodes the entry under key, whatever its age.
+func read[T any](s *Store, key string) (entry[T], bool) {
+	var e entry[T]
+	data, err := os.ReadFile(filepath.Join(s.dir, key+".json"))
+	if err != nil {
+		return e, false
+	}
+	data, err = s.open(key, data)
+	if err != nil {
+		return e, false
+	}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return e, false
+	}
+	return e, true
 }
 
 // Set writes a value to the cache under key. Creates the directory if needed.
+// The write is atomic: readers see either the previous entry or the new one,
+// never a partial file. Entries of a disabled kind are not written.
 func Set[T any](s *Store, key string, value T) error {
-	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+	if p, _ := s.policy(key); p.Disabled {
+		return nil
+	}
+	unlock, err := s.lock()
+	if err != nil {
 		return err
 	}
+	defer unlock()
 
-	e := entry[T]{
-		CachedAt: s.now(),
-		Response: value,
+	sealOnFirstWrite(s)
+	return write(s, key, value)
+}
+
+// Update performs a read-modify-write of the entry under key while holding
+// the Store's lock, so concurrent grant processes cannot lose each other's
+// changes. fn receives the current value (the zero value on miss or expiry)
+// and returns the value to store, or false to leave the entry untouched. fn is
+// not called for a disabled kind.
+func Update[T any](s *Store, key string, fn func(current T) (T, bool)) error {
+	if p, _ := s.policy(key); p.Disabled {
+		return nil
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	data, err := json.Marshal(e)
+	sealOnFirstWrite(s)
+	var current T
+	Get(s, key, &current)
+	next, changed := fn(current)
+	if !changed {
+		return nil
+	}
+	return write(s, key, next)
+}
+
+// write stores value under key through a temporary file renamed over the
+// target. The caller must hold the Store's lock.
+func write[T any](s *Store, key string, value T) error {
+	data, err := encode(s, key, value)
 	if err != nil {
 		return err
 	}
+	return writeFile(s, key, data)
+}
 
-	return os.WriteFile(filepath.Join(s.dir, key+".json"), data, 0o600)
+// writeUnsynced is write without the fsync, for entries whose loss in a crash
+// does not matter. The rename still keeps a reader from a partial file.
+func writeUnsynced[T any](s *Store, key string, value T) error {
+	data, err := encode(s, key, value)
+	if err != nil {
+		return err
+	}
+	return replaceWith(s, key, data, false)
+}
+
+// encode marshals value as the entry under key, sealed if s encrypts.
+func encode[T any](s *Store, key string, value T) ([]byte, error) {
+	data, err := json.Marshal(entry[T]{CachedAt: s.now(), Response: value})
+	if err != nil {
+		return nil, err
+	}
+	return s.seal(key, data)
+}
+
+// writeFile atomically and durably replaces the file of key with data. The
+// caller must hold the Store's lock.
+func writeFile(s *Store, key string, data []byte) error {
+	return replaceWith(s, key, data, true)
+}
+
+// replaceWith replaces the file of key with data through a temporary file,
+// synced first when sync is set.
+func replaceWith(s *Store, key string, data []byte, sync bool) error {
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if sync {
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return replaceFile(tmp.Name(), filepath.Join(s.dir, key+".json"))
 }
 
 // Invalidate removes a cached entry by key.
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -118,6 +120,146 @@ func TestSet_FilePermissions(t *testing.T) {
 	}
 }
 
+func TestSet_ReplacesAtomically(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	s := NewStore(dir, 4*time.Hour)
+
+	big := strings.Repeat("x", 1<<20)
+	if err := Set(s, "big", big); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// A reader racing a writer must always see a complete entry: the old one
+	// or the new one, never a truncated file that fails to parse.
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 20 {
+			if err := Set(s, "big", big[:len(big)-i]); err != nil {
+				t.Errorf("Set() error = %v", err)
+			}
+		}
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			wg.Wait()
+			entries, _ := os.ReadDir(dir)
+			for _, e := range entries {
+				if strings.HasSuffix(e.Name(), ".tmp") {
+					t.Errorf("temporary file %s left behind", e.Name())
+				}
+			}
+			return
+		default:
+			var out string
+			if !Get(s, "big", &out) {
+				t.Fatal("reader saw a missing or partial entry during a write")
+			}
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), 4*time.Hour)
+
+	increment := func(n int) (int, bool) { return n + 1, true }
+	for range 3 {
+		if err := Update(s, "counter", increment); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	var got int
+	if !Get(s, "counter", &got) || got != 3 {
+		t.Fatalf("counter = %d, want 3", got)
+	}
+
+	// fn reporting no change leaves the entry, including its age, untouched.
+	s.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if err := Update(s, "counter", func(n int) (int, bool) { return 100, false }); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	s.now = func() time.Time { return time.Now().Add(3*time.Hour + 59*time.Minute) }
+	if !Get(s, "counter", &got) || got != 3 {
+		t.Errorf("counter = %d after unchanged Update, want 3 with its original age", got)
+	}
+}
+
+func TestSetPolicy(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	start := time.Now()
+	s := NewStore(dir, time.Hour).
+		SetPolicy(KindEligibility, Policy{TTL: 10 * time.Minute}).
+		SetPolicy(KindGroups, Policy{TTL: 7 * 24 * time.Hour}).
+		SetPolicy(KindSessions, Policy{Disabled: true})
+	s.now = func() time.Time { return start }
+
+	for _, key := range []string{"eligibility_azure", "groups_eligibility_azure", sessionTimestampsKey, "other"} {
+		if err := Set(s, key, "x"); err != nil {
+			t.Fatalf("Set(%s) error = %v", key, err)
+		}
+	}
+	if err := Update(s, sessionSnapshotKey, func(string) (string, bool) {
+		t.Error("Update() called fn for a disabled kind")
+		return "x", true
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, sessionSnapshotKey+".json")); !os.IsNotExist(err) {
+		t.Errorf("disabled kind was written: %v", err)
+	}
+
+	s.now = func() time.Time { return start.Add(30 * time.Minute) }
+	want := map[string]bool{
+		"eligibility_azure":        false, // past its 10m TTL
+		"groups_eligibility_azure": true,
+		sessionTimestampsKey:       true, // keeps the Store's TTL despite the sessions policy
+		"other":                    true,
+	}
+	for key, hit := range want {
+		var out string
+		if got := Get(s, key, &out); got != hit {
+			t.Errorf("Get(%s) = %v, want %v", key, got, hit)
+		}
+	}
+	if got := s.TTLOf("groups_eligibility_aws"); got != 7*24*time.Hour {
+		t.Errorf("TTLOf(groups) = %v, want 168h", got)
+	}
+}
+
+func TestUpdate_ConcurrentNoLostWrites(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	// Separate Stores on one directory, like separate grant processes.
+	const workers, increments = 8, 25
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := NewStore(dir, 4*time.Hour)
+			for range increments {
+				if err := Update(s, "counter", func(n int) (int, bool) { return n + 1, true }); err != nil {
+					t.Errorf("Update() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var got int
+	if !Get(NewStore(dir, 4*time.Hour), "counter", &got) || got != workers*increments {
+		t.Errorf("counter = %d, want %d", got, workers*increments)
+	}
+}
+
 type testStruct struct {
 	Name  string `json:"name"`
 	Count int    `json:"count"`
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/aaearon/grant-cli/internal/sca/models"
 )
@@ -28,6 +29,10 @@ type nopLogger struct{}
 
 func (nopLogger) Info(string, ...interface{}) {}
 
+// LookupHook is told the outcome of every cache lookup a decorator makes. A
+// lookup bypassed by refresh is reported as a miss.
+type LookupHook func(key string, hit bool)
+
 // CachedEligibilityLister decorates eligibility listers with file-based caching.
 // It implements both EligibilityLister and GroupsEligibilityLister.
 type CachedEligibilityLister struct {
@@ -36,6 +41,8 @@ type CachedEligibilityLister struct {
 	store       *Store
 	refresh     bool
 	log         Logger
+	onLookup    LookupHook
+	stale       staleFallback
 }
 
 // NewCachedEligibilityLister creates a new caching decorator.
@@ -61,23 +68,58 @@ func NewCachedEligibilityLister(
 	}
 }
 
+// OnLookup registers hook to be told the outcome of each cache lookup.
+func (c *CachedEligibilityLister) OnLookup(hook LookupHook) {
+	c.onLookup = hook
+}
+
+// ServeStale makes the decorator answer from an entry up to maxStale past its
+// TTL when the API call fails with an outage, telling hook. It does not apply
+// with refresh, which asks for live data.
+func (c *CachedEligibilityLister) ServeStale(maxStale time.Duration, hook StaleHook) {
+	c.stale.maxStale, c.stale.hook = maxStale, hook
+}
+
+// RefreshInBackground makes the decorator answer from an entry within the
+// ServeStale bound without waiting for the API, having r refresh it.
+func (c *CachedEligibilityLister) RefreshInBackground(r *Revalidator) {
+	c.stale.revalidator = r
+}
+
+// lookup reads key from the cache unless refresh is set, and reports the
+// outcome to the lookup hook.
+func lookup[T any](store *Store, refresh bool, hook LookupHook, key string, dst *T) bool {
+	hit := !refresh && Get(store, key, dst)
+	if hook != nil {
+		hook(key, hit)
+	}
+	return hit
+}
+
 // ListEligibility checks the cache first, then falls through to the inner lister.
 func (c *CachedEligibilityLister) ListEligibility(ctx context.Context, csp models.CSP) (*models.EligibilityResponse, error) {
 	key := eligibilityCacheKey(csp)
 
-	if c.refresh {
+	var cached models.EligibilityResponse
+	switch {
+	case lookup(c.store, c.refresh, c.onLookup, key, &cached):
+		c.log.Info("Cache hit for %s eligibility (%d targets)", csp, len(cached.Response))
+		return &cached, nil
+	case c.refresh:
 		c.log.Info("Cache refresh requested for %s eligibility, bypassing cache", csp)
-	} else {
-		var cached models.EligibilityResponse
-		if Get(c.store, key, &cached) {
-			c.log.Info("Cache hit for %s eligibility (%d targets)", csp, len(cached.Response))
-			return &cached, nil
-		}
+	case serveRevalidating(c.store, c.stale, key, &cached, func(ctx context.Context) error { return c.refreshEligibility(ctx, csp, key) }):
+		c.log.Info("Cache expired for %s eligibility, serving it (%d targets) while refreshing in the background", csp, len(cached.Response))
+		return &cached, nil
+	default:
 		c.log.Info("Cache miss for %s eligibility, fetching from API", csp)
 	}
 
 	resp, err := c.cloudInner.ListEligibility(ctx, csp)
 	if err != nil {
+		if !c.refresh && serveStale(c.store, c.stale, key, err, &cached) {
+			c.log.Info("API failed for %s eligibility, serving stale cache (%d targets): %v", csp, len(cached.Response), err)
+			return &cached, nil
+		}
 		return nil, err
 	}
 
@@ -98,19 +140,26 @@ func (c *CachedEligibilityLister) ListGroupsEligibility(ctx context.Context, csp
 
 	key := groupsEligibilityCacheKey(csp)
 
-	if c.refresh {
+	var cached models.GroupsEligibilityResponse
+	switch {
+	case lookup(c.store, c.refresh, c.onLookup, key, &cached):
+		c.log.Info("Cache hit for %s groups eligibility (%d groups)", csp, len(cached.Response))
+		return &cached, nil
+	case c.refresh:
 		c.log.Info("Cache refresh requested for %s groups eligibility, bypassing cache", csp)
-	} else {
-		var cached models.GroupsEligibilityResponse
-		if Get(c.store, key, &cached) {
-			c.log.Info("Cache hit for %s groups eligibility (%d groups)", csp, len(cached.Response))
-			return &cached, nil
-		}
+	case serveRevalidating(c.store, c.stale, key, &cached, func(ctx context.Context) error { return c.refreshGroupsEligibility(ctx, csp, key) }):
+		c.log.Info("Cache expired for %s groups eligibility, serving it (%d groups) while refreshing in the background", csp, len(cached.Response))
+		return &cached, nil
+	default:
 		c.log.Info("Cache miss for %s groups eligibility, fetching from API", csp)
 	}
 
 	resp, err := c.groupsInner.ListGroupsEligibility(ctx, csp)
 	if err != nil {
+		if !c.refresh && serveStale(c.store, c.stale, key, err, &cached) {
+			c.log.Info("API failed for %s groups eligibility, serving stale cache (%d groups): %v", csp, len(cached.Response), err)
+			return &cached, nil
+		}
 		return nil, err
 	}
 
@@ -123,6 +172,38 @@ func (c *CachedEligibilityLister) ListGroupsEligibility(ctx context.Context, csp
 	return resp, nil
 }
 
+// refreshEligibility replaces the expired entry of csp's eligibility in the
+// background, returning the API's error. A failed cache write is only logged.
+func (c *CachedEligibilityLister) refreshEligibility(ctx context.Context, csp models.CSP, key string) error {
+	resp, err := c.cloudInner.ListEligibility(ctx, csp)
+	if err != nil {
+		c.log.Info("Background refresh of %s eligibility failed: %v", csp, err)
+		return err
+	}
+	if err := Set(c.store, key, *resp); err != nil {
+		c.log.Info("Cache write failed for %s eligibility: %v", csp, err)
+		return nil
+	}
+	c.log.Info("Refreshed %s eligibility in the background (%d targets)", csp, len(resp.Response))
+	return nil
+}
+
+// refreshGroupsEligibility replaces the expired entry of csp's groups
+// eligibility in the background, like refreshEligibility.
+func (c *CachedEligibilityLister) refreshGroupsEligibility(ctx context.Context, csp models.CSP, key string) error {
+	resp, err := c.groupsInner.ListGroupsEligibility(ctx, csp)
+	if err != nil {
+		c.log.Info("Background refresh of %s groups eligibility failed: %v", csp, err)
+		return err
+	}
+	if err := Set(c.store, key, *resp); err != nil {
+		c.log.Info("Cache write failed for %s groups eligibility: %v", csp, err)
+		return nil
+	}
+	c.log.Info("Refreshed %s groups eligibility in the background (%d groups)", csp, len(resp.Response))
+	return nil
+}
+
 func eligibilityCacheKey(csp models.CSP) string {
 	return "eligibility_" + strings.ToLower(string(csp))
 }
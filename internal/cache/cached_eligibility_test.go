@@ -513,3 +513,29 @@ func TestCacheKeys_LowercaseCSP(t *testing.T) {
 		t.Errorf("groupsEligibilityCacheKey = %q, want %q", got, want)
 	}
 }
+
+func TestCachedEligibilityLister_OnLookup(t *testing.T) {
+	t.Parallel()
+	inner := &mockEligibilityLister{response: &models.EligibilityResponse{Response: []models.EligibleTarget{{WorkspaceID: "ws-1"}}}}
+	groupsInner := &mockGroupsEligibilityLister{response: &models.GroupsEligibilityResponse{}}
+
+	for _, refresh := range []bool{false, true} {
+		cached := NewCachedEligibilityLister(inner, groupsInner, NewStore(t.TempDir(), 4*time.Hour), refresh, nil)
+		var got []string
+		cached.OnLookup(func(key string, hit bool) { got = append(got, fmt.Sprintf("%s=%v", key, hit)) })
+
+		ctx := t.Context()
+		_, _ = cached.ListEligibility(ctx, models.CSPAWS)
+		_, _ = cached.ListEligibility(ctx, models.CSPAWS)
+		_, _ = cached.ListGroupsEligibility(ctx, models.CSPAzure)
+
+		want := []string{"eligibility_aws=false", "eligibility_aws=true", "groups_eligibility_azure=false"}
+		if refresh {
+			// A bypassed lookup is a miss, even with a fresh entry on disk.
+			want[1] = "eligibility_aws=false"
+		}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("refresh=%v: lookups = %v, want %v", refresh, got, want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"time"
 
 	scamodels "github.com/aaearon/grant-cli/internal/sca/models"
 )
@@ -15,10 +16,12 @@ type OnDemandRolesLister interface {
 
 // CachedRolesLister decorates an OnDemandRolesLister with file-based caching.
 type CachedRolesLister struct {
-	inner   OnDemandRolesLister
-	store   *Store
-	refresh bool
-	log     Logger
+	inner    OnDemandRolesLister
+	store    *Store
+	refresh  bool
+	log      Logger
+	onLookup LookupHook
+	stale    staleFallback
 }
 
 // NewCachedRolesLister creates a caching decorator for on-demand role discovery.
@@ -30,23 +33,48 @@ func NewCachedRolesLister(inner OnDemandRolesLister, store *Store, refresh bool,
 	return &CachedRolesLister{inner: inner, store: store, refresh: refresh, log: log}
 }
 
+// OnLookup registers hook to be told the outcome of each cache lookup.
+func (c *CachedRolesLister) OnLookup(hook LookupHook) {
+	c.onLookup = hook
+}
+
+// ServeStale makes the decorator answer from an entry up to maxStale past its
+// TTL when the API call fails with an outage, telling hook. It does not apply
+// with refresh, which asks for live data.
+func (c *CachedRolesLister) ServeStale(maxStale time.Duration, hook StaleHook) {
+	c.stale.maxStale, c.stale.hook = maxStale, hook
+}
+
+// RefreshInBackground makes the decorator answer from an entry within the
+// ServeStale bound without waiting for the API, having r refresh it.
+func (c *CachedRolesLister) RefreshInBackground(r *Revalidator) {
+	c.stale.revalidator = r
+}
+
 // ListOnDemandResources checks the cache first, then falls through to the inner lister.
 func (c *CachedRolesLister) ListOnDemandResources(ctx context.Context, req scamodels.OnDemandRequest) ([]scamodels.OnDemandResource, error) {
 	key := onDemandRolesCacheKey(req.PlatformName, req.WorkspaceID)
 
-	if c.refresh {
+	var cached []scamodels.OnDemandResource
+	switch {
+	case lookup(c.store, c.refresh, c.onLookup, key, &cached):
+		c.log.Info("Cache hit for on-demand roles (%s, %d roles)", req.PlatformName, len(cached))
+		return cached, nil
+	case c.refresh:
 		c.log.Info("Cache refresh requested for on-demand roles (%s), bypassing cache", req.PlatformName)
-	} else {
-		var cached []scamodels.OnDemandResource
-		if Get(c.store, key, &cached) {
-			c.log.Info("Cache hit for on-demand roles (%s, %d roles)", req.PlatformName, len(cached))
-			return cached, nil
-		}
+	case serveRevalidating(c.store, c.stale, key, &cached, func(ctx context.Context) error { return c.refreshRoles(ctx, req, key) }):
+		c.log.Info("Cache expired for on-demand roles (%s), serving it (%d roles) while refreshing in the background", req.PlatformName, len(cached))
+		return cached, nil
+	default:
 		c.log.Info("Cache miss for on-demand roles (%s), fetching from API", req.PlatformName)
 	}
 
 	roles, err := c.inner.ListOnDemandResources(ctx, req)
 	if err != nil {
+		if !c.refresh && serveStale(c.store, c.stale, key, err, &cached) {
+			c.log.Info("API failed for on-demand roles (%s), serving stale cache (%d roles): %v", req.PlatformName, len(cached), err)
+			return cached, nil
+		}
 		return nil, err
 	}
 
@@ -58,6 +86,22 @@ func (c *CachedRolesLister) ListOnDemandResources(ctx context.Context, req scamo
 	return roles, nil
 }
 
+// refreshRoles replaces the expired entry of req's on-demand roles in the
+// background, returning the API's error. A failed cache write is only logged.
+func (c *CachedRolesLister) refreshRoles(ctx context.Context, req scamodels.OnDemandRequest, key string) error {
+	roles, err := c.inner.ListOnDemandResources(ctx, req)
+	if err != nil {
+		c.log.Info("Background refresh of on-demand roles (%s) failed: %v", req.PlatformName, err)
+		return err
+	}
+	if err := Set(c.store, key, roles); err != nil {
+		c.log.Info("Cache write failed for on-demand roles (%s): %v", req.PlatformName, err)
+		return nil
+	}
+	c.log.Info("Refreshed on-demand roles in the background (%s, %d roles)", req.PlatformName, len(roles))
+	return nil
+}
+
 // onDemandRolesCacheKey builds a cache key from platformName and a sha256 hash of workspaceID.
 // Hashing eliminates unsafe filesystem characters from workspace identifiers like
 // "/providers/Microsoft.Management/managementGroups/...".
@@ -0,0 +1,241 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/99designs/keyring"
+)
+
+// ErrKeyUnavailable is returned by writes to an encrypted Store whose key
+// cannot be loaded. Reads from such a Store miss.
+var ErrKeyUnavailable = errors.New("cache encryption key unavailable")
+
+// KeyFunc returns the 32-byte AES-256 key that seals a Store's entries.
+type KeyFunc func() ([]byte, error)
+
+// KeyStore holds the cache key between runs. The SDK keyring satisfies it.
+type KeyStore interface {
+	GetPassword(service, user string) (string, error)
+	SetPassword(service, user, password string) error
+}
+
+// keyringUser is the keyring entry holding the cache key.
+const keyringUser = "cache_encryption_key"
+
+// KeyringKey returns a KeyFunc that loads the cache key stored in ks under
+// service, generating and saving one if there is none. Losing the key, for
+// instance to a keyring reset, only costs the cache: entries sealed with the
+// old key read as misses and are overwritten. Any other keyring error, such
+// as a locked keychain, fails with ErrKeyUnavailable and leaves the stored key
+// alone.
+func KeyringKey(ks KeyStore, service string) KeyFunc {
+	return func() ([]byte, error) {
+		encoded, err := ks.GetPassword(service, keyringUser)
+		if err != nil && !isKeyNotFound(err) {
+			return nil, fmt.Errorf("%w: %v", ErrKeyUnavailable, err)
+		}
+		if err == nil && encoded != "" {
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cache key in keyring: %w", err)
+			}
+			return key, nil
+		}
+
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := ks.SetPassword(service, keyringUser, base64.StdEncoding.EncodeToString(key)); err != nil {
+			return nil, fmt.Errorf("failed to save cache key to keyring: %w", err)
+		}
+		return key, nil
+	}
+}
+
+// isKeyNotFound reports whether err is a keyring backend's error for an
+// entry that does not exist: keyring.ErrKeyNotFound, which every backend of
+// the SDK's keyring library returns, or a file's ENOENT. The message of
+// ErrKeyNotFound also counts when a wrapper re-created the error rather than
+// wrapping it. Nothing else does: an unavailable backend, such as a missing
+// Secret Service, must not pass for a missing key and get it replaced.
+func isKeyNotFound(err error) bool {
+	return errors.Is(err, keyring.ErrKeyNotFound) || errors.Is(err, os.ErrNotExist) ||
+		err.Error() == keyring.ErrKeyNotFound.Error()
+}
+
+// sealedEntry is the on-disk form of an encrypted entry: the JSON of the
+// plaintext entry sealed with AES-256-GCM, nonce first. The entry's key is
+// authenticated with it, so one file cannot be passed off as another.
+type sealedEntry struct {
+	Sealed []byte `json:"sealed"`
+}
+
+// sealedPrefix starts every sealed file and no plaintext entry.
+var sealedPrefix = []byte(`{"sealed":`)
+
+// sealer holds an encrypted Store's cipher, set up on first use. It is shared
+// with views of the Store such as statsStore.
+type sealer struct {
+	key   KeyFunc
+	once  sync.Once
+	aead  cipher.AEAD
+	err   error
+	swept sync.Once // plaintext entries sealed; see sealOnFirstWrite
+}
+
+// Encrypt makes s seal every entry it writes with the key returned by key,
+// which is loaded on first use: the first write, or the first read of a sealed
+// entry. If the key cannot be loaded, s behaves as an empty cache: reads miss
+// and writes fail with ErrKeyUnavailable, so callers fall back to the API.
+// Entries s finds in plaintext are still read, without loading the key; the
+// first write seals them.
+func (s *Store) Encrypt(key KeyFunc) *Store {
+	s.sealer = &sealer{key: key}
+	return s
+}
+
+// Offline makes s safe for callers that must never block, such as a shell
+// prompt: sealed entries read as misses without loading the key, and writes
+// fail with ErrLocked rather than wait for another process's lock.
+func (s *Store) Offline() *Store {
+	s.offline = true
+	return s
+}
+
+// cipher returns the Store's AEAD, or nil for a plaintext Store.
+func (s *Store) cipher() (cipher.AEAD, error) {
+	z := s.sealer
+	if z == nil {
+		return nil, nil
+	}
+	if s.offline {
+		return nil, ErrKeyUnavailable
+	}
+	z.once.Do(func() {
+		key, err := z.key()
+		if err != nil {
+			z.err = fmt.Errorf("%w: %v", ErrKeyUnavailable, err)
+			return
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			z.err = fmt.Errorf("%w: %v", ErrKeyUnavailable, err)
+			return
+		}
+		z.aead, z.err = cipher.NewGCM(block)
+	})
+	return z.aead, z.err
+}
+
+// seal returns what to write to disk for the plaintext of the entry under key.
+func (s *Store) seal(key string, plaintext []byte) ([]byte, error) {
+	aead, err := s.cipher()
+	if err != nil || aead == nil {
+		return plaintext, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.Marshal(sealedEntry{Sealed: aead.Seal(nonce, nonce, plaintext, []byte(key))})
+}
+
+// open returns the plaintext of the entry under key read from disk as data.
+func (s *Store) open(key string, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, sealedPrefix) {
+		return data, nil
+	}
+	aead, err := s.cipher()
+	if err != nil {
+		return nil, err
+	}
+	if aead == nil {
+		return nil, errors.New("entry is encrypted and encryption is off")
+	}
+
+	var se sealedEntry
+	if err := json.Unmarshal(data, &se); err != nil {
+		return nil, err
+	}
+	n := aead.NonceSize()
+	if len(se.Sealed) < n {
+		return nil, errors.New("sealed entry is truncated")
+	}
+	return aead.Open(nil, se.Sealed[:n], se.Sealed[n:], []byte(key))
+}
+
+// SealPlaintext encrypts the entries of an encrypted Store that are still
+// stored in plaintext, written before encryption was turned on, and returns
+// how many it sealed. Their cached_at is kept, so they expire as before.
+func SealPlaintext(s *Store) (int, error) {
+	if s.sealer == nil {
+		return 0, nil
+	}
+	if _, err := s.cipher(); err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(s.dir); errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	return sealPlaintext(s)
+}
+
+// sealOnFirstWrite seals the plaintext entries of an encrypted Store the
+// first time it writes, so turning encryption on costs readers neither the
+// keyring nor the lock. Best-effort: an entry left in plaintext is still read,
+// and sealed by the next process that writes. The caller must hold the
+// Store's lock.
+func sealOnFirstWrite(s *Store) {
+	if z := s.sealer; z != nil && !s.offline {
+		z.swept.Do(func() { _, _ = sealPlaintext(s) })
+	}
+}
+
+// sealPlaintext is SealPlaintext for a caller holding the Store's lock.
+func sealPlaintext(s *Store) (int, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	sealed := 0
+	var errs []error
+	for _, f := range files {
+		key, ok := strings.CutSuffix(f.Name(), ".json")
+		if !ok || f.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, f.Name()))
+		if err != nil || bytes.HasPrefix(data, sealedPrefix) {
+			continue
+		}
+		if data, err = s.seal(key, data); err == nil {
+			err = writeFile(s, key, data)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sealed++
+	}
+	return sealed, errors.Join(errs...)
+}
@@ -0,0 +1,280 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/99designs/keyring"
+)
+
+// memKeyStore is an in-memory KeyStore.
+type memKeyStore struct {
+	passwords map[string]string
+	getErr    error
+	setErr    error
+}
+
+func (m *memKeyStore) GetPassword(service, user string) (string, error) {
+	if m.getErr != nil {
+		return "", m.getErr
+	}
+	return m.passwords[service+"/"+user], nil
+}
+
+func (m *memKeyStore) SetPassword(service, user, password string) error {
+	if m.setErr != nil {
+		return m.setErr
+	}
+	if m.passwords == nil {
+		m.passwords = make(map[string]string)
+	}
+	m.passwords[service+"/"+user] = password
+	return nil
+}
+
+func fixedKey(b byte) KeyFunc {
+	return func() ([]byte, error) { return bytes.Repeat([]byte{b}, 32), nil }
+}
+
+func TestEncrypt_RoundTrip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	s := NewStore(dir, time.Hour).Encrypt(fixedKey(1))
+
+	if err := Set(s, "eligibility_azure", []string{"Prod-Subscription"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "eligibility_azure.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("Prod-Subscription")) || !bytes.HasPrefix(data, sealedPrefix) {
+		t.Errorf("file is not sealed: %s", data)
+	}
+
+	var out []string
+	if !Get(NewStore(dir, time.Hour).Encrypt(fixedKey(1)), "eligibility_azure", &out) || out[0] != "Prod-Subscription" {
+		t.Errorf("Get() = %v, want the sealed value", out)
+	}
+	entries, err := Entries(s)
+	if err != nil || len(entries) != 1 || entries[0].Items != 1 || entries[0].CachedAt.IsZero() {
+		t.Errorf("Entries() = %+v, %v; want the decrypted entry", entries, err)
+	}
+}
+
+func TestEncrypt_UnreadableEntriesMiss(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := Set(NewStore(dir, time.Hour).Encrypt(fixedKey(1)), "a", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	var out string
+	if Get(NewStore(dir, time.Hour).Encrypt(fixedKey(2)), "a", &out) {
+		t.Error("Get() with another key hit")
+	}
+	if Get(NewStore(dir, time.Hour), "a", &out) {
+		t.Error("Get() without encryption hit a sealed entry")
+	}
+
+	// The key is authenticated: a sealed file renamed to another key is rejected.
+	if err := os.Rename(filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")); err != nil {
+		t.Fatal(err)
+	}
+	if Get(NewStore(dir, time.Hour).Encrypt(fixedKey(1)), "b", &out) {
+		t.Error("Get() accepted an entry sealed under another key")
+	}
+}
+
+func TestEncrypt_KeyUnavailable(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := Set(NewStore(dir, time.Hour), "plain", "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore(dir, time.Hour).Encrypt(func() ([]byte, error) { return nil, errors.New("keyring locked") })
+	var out string
+	if !Get(s, "plain", &out) {
+		t.Error("Get() missed a plaintext entry, which needs no key")
+	}
+	if err := Set(s, "b", "x"); !errors.Is(err, ErrKeyUnavailable) {
+		t.Errorf("Set() error = %v, want ErrKeyUnavailable", err)
+	}
+	if _, err := SealPlaintext(s); !errors.Is(err, ErrKeyUnavailable) {
+		t.Errorf("SealPlaintext() error = %v, want ErrKeyUnavailable", err)
+	}
+
+	short := NewStore(dir, time.Hour).Encrypt(func() ([]byte, error) { return []byte("short"), nil })
+	if err := Set(short, "b", "x"); !errors.Is(err, ErrKeyUnavailable) {
+		t.Errorf("Set() with a bad key error = %v, want ErrKeyUnavailable", err)
+	}
+}
+
+func TestEncrypt_KeyLoadedOnlyWhenNeeded(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := Set(NewStore(dir, time.Hour), "plain", "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	loads := 0
+	s := NewStore(dir, time.Hour).Encrypt(func() ([]byte, error) {
+		loads++
+		return bytes.Repeat([]byte{1}, 32), nil
+	})
+	var out string
+	if !Get(s, "plain", &out) || Get(s, "missing", &out) {
+		t.Fatal("Get() on a plaintext entry and a missing one")
+	}
+	if loads != 0 {
+		t.Fatalf("reads of unsealed entries loaded the key %d times", loads)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "plain.json")); bytes.HasPrefix(data, sealedPrefix) {
+		t.Fatal("a read sealed the plaintext entry")
+	}
+
+	// The first write loads the key once and seals what was left in plaintext.
+	if err := Set(s, "b", "y"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(s, "c", "z"); err != nil {
+		t.Fatal(err)
+	}
+	if loads != 1 {
+		t.Errorf("key loaded %d times, want once", loads)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "plain.json")); !bytes.HasPrefix(data, sealedPrefix) {
+		t.Errorf("first write did not seal the plaintext entry: %s", data)
+	}
+}
+
+func TestOffline(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := Set(NewStore(dir, time.Hour).Encrypt(fixedKey(1)), "sealed", "y"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(NewStore(dir, time.Hour), "plain", "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewStore(dir, time.Hour).Encrypt(func() ([]byte, error) {
+		t.Error("an Offline Store loaded the key")
+		return nil, errors.New("unreachable")
+	}).Offline()
+	var out string
+	if !Get(s, "plain", &out) || out != "x" {
+		t.Errorf("Get(plain) = %q, want the plaintext entry", out)
+	}
+	if Get(s, "sealed", &out) {
+		t.Error("Get(sealed) hit on an Offline Store")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "plain.json")); bytes.HasPrefix(data, sealedPrefix) {
+		t.Error("an Offline Store sealed a plaintext entry")
+	}
+
+	unlock, err := NewStore(dir, time.Hour).lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+	if err := Set(NewStore(dir, time.Hour).Offline(), "plain", "z"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Set() on a locked Offline Store error = %v, want ErrLocked", err)
+	}
+}
+
+func TestSealPlaintext(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cachedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	s := NewStore(dir, time.Hour).Encrypt(fixedKey(1))
+	s.now = func() time.Time { return cachedAt.Add(time.Minute) }
+	if err := Set(s, "already_sealed", "x"); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := NewStore(dir, time.Hour)
+	plain.now = func() time.Time { return cachedAt }
+	for _, key := range []string{"eligibility_azure", "session_timestamps"} {
+		if err := Set(plain, key, []string{"value"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := SealPlaintext(s)
+	if err != nil || n != 2 {
+		t.Fatalf("SealPlaintext() = %d, %v; want 2", n, err)
+	}
+	for _, key := range []string{"eligibility_azure", "session_timestamps", "already_sealed"} {
+		data, _ := os.ReadFile(filepath.Join(dir, key+".json"))
+		if !bytes.HasPrefix(data, sealedPrefix) {
+			t.Errorf("%s is not sealed: %s", key, data)
+		}
+	}
+
+	var out []string
+	if !Get(s, "eligibility_azure", &out) || out[0] != "value" {
+		t.Errorf("Get() after sealing = %v", out)
+	}
+	if e, ok := read[[]string](s, "eligibility_azure"); !ok || !e.CachedAt.Equal(cachedAt) {
+		t.Errorf("cached_at = %v, want %v kept", e.CachedAt, cachedAt)
+	}
+
+	if n, err := SealPlaintext(NewStore(dir, time.Hour)); n != 0 || err != nil {
+		t.Errorf("SealPlaintext(plaintext store) = %d, %v; want a no-op", n, err)
+	}
+}
+
+func TestKeyringKey(t *testing.T) {
+	t.Parallel()
+	ks := &memKeyStore{}
+
+	first, err := KeyringKey(ks, "grant")()
+	if err != nil || len(first) != 32 {
+		t.Fatalf("KeyringKey() = %x, %v; want a new 32-byte key", first, err)
+	}
+	again, err := KeyringKey(ks, "grant")()
+	if err != nil || !bytes.Equal(first, again) {
+		t.Errorf("KeyringKey() = %x, %v; want the saved key %x", again, err, first)
+	}
+
+	if _, err := KeyringKey(&memKeyStore{getErr: keyring.ErrKeyNotFound, setErr: errors.New("read-only")}, "grant")(); err == nil {
+		t.Error("KeyringKey() with an unwritable keyring succeeded")
+	}
+	for _, getErr := range []error{
+		keyring.ErrKeyNotFound,
+		fmt.Errorf("failed to get password: %w", keyring.ErrKeyNotFound),
+		errors.New("The specified item could not be found in the keyring"),
+		os.ErrNotExist,
+	} {
+		if key, err := KeyringKey(&memKeyStore{getErr: getErr}, "grant")(); err != nil || len(key) != 32 {
+			t.Errorf("KeyringKey() after %q = %x, %v; want a new key", getErr, key, err)
+		}
+	}
+
+	// Any other error, such as a locked keychain or an unavailable backend
+	// whose message mentions "not found", must not replace the key.
+	for _, getErr := range []error{
+		errors.New("keychain is locked"),
+		errors.New("The name org.freedesktop.secrets was not provided by any .service files: org.freedesktop.secrets not found"),
+		errors.New("keyring provider not found"),
+	} {
+		unavailable := &memKeyStore{getErr: getErr}
+		if _, err := KeyringKey(unavailable, "grant")(); !errors.Is(err, ErrKeyUnavailable) {
+			t.Errorf("KeyringKey() after %q error = %v, want ErrKeyUnavailable", getErr, err)
+		}
+		if len(unavailable.passwords) != 0 {
+			t.Errorf("KeyringKey() after %q saved a new key: %v", getErr, unavailable.passwords)
+		}
+	}
+	bad := &memKeyStore{passwords: map[string]string{"grant/" + keyringUser: "not base64!"}}
+	if _, err := KeyringKey(bad, "grant")(); err == nil {
+		t.Error("KeyringKey() accepted a corrupt key")
+	}
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/config"
+)
+
+// Cache entry kinds, derived from the key. They are the unit of
+// 'grant cache clear --kind' and of the cache: block in config.yaml.
+const (
+	KindEligibility = config.CacheKindEligibility
+	KindGroups      = config.CacheKindGroups
+	KindOnDemand    = config.CacheKindOnDemand
+	KindSessions    = config.CacheKindSessions
+	KindOther       = "other"
+)
+
+// Kinds lists the entry kinds a user can select, in display order.
+var Kinds = config.CacheKinds
+
+// KindOf returns the kind of the entry stored under key.
+func KindOf(key string) string {
+	switch {
+	case strings.HasPrefix(key, "groups_eligibility_"):
+		return KindGroups
+	case strings.HasPrefix(key, "eligibility_"):
+		return KindEligibility
+	case strings.HasPrefix(key, "ondemand_roles_"):
+		return KindOnDemand
+	case key == sessionTimestampsKey, key == sessionSnapshotKey:
+		return KindSessions
+	}
+	return KindOther
+}
+
+// EntryInfo describes one cache entry without decoding its payload type.
+type EntryInfo struct {
+	Key      string
+	Kind     string
+	CachedAt time.Time // zero when the file cannot be decoded
+	Size     int64     // bytes on disk
+	Items    int       // elements of the cached list or map
+}
+
+// Entries lists the entries in the Store's directory, sorted by key. Files
+// that cannot be decoded are listed with a zero CachedAt so they can still be
+// found and cleared. A missing directory has no entries.
+func Entries(s *Store) ([]EntryInfo, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var out []EntryInfo
+	for _, f := range files {
+		key, ok := strings.CutSuffix(f.Name(), ".json")
+		if !ok || f.IsDir() || key == statsKey {
+			continue
+		}
+		info := EntryInfo{Key: key, Kind: KindOf(key)}
+		if fi, err := f.Info(); err == nil {
+			info.Size = fi.Size()
+		}
+		if e, ok := read[json.RawMessage](s, key); ok {
+			info.CachedAt = e.CachedAt
+			info.Items = countItems(e.Response)
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+// countItems counts the elements of a cached payload: a list, a list wrapped
+// in a {"response": [...]} API envelope, or a map.
+func countItems(raw json.RawMessage) int {
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		return len(list)
+	}
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(raw, &obj) != nil {
+		return 0
+	}
+	if inner, ok := obj["response"]; ok && json.Unmarshal(inner, &list) == nil {
+		return len(list)
+	}
+	return len(obj)
+}
+
+// Clear removes the entries of the given kinds, or every entry when no kind
+// is given, and returns the removed keys. Lookup statistics are kept.
+func Clear(s *Store, kinds ...string) ([]string, error) {
+	entries, err := Entries(s)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var removed []string
+	var errs []error
+	for _, e := range entries {
+		if len(kinds) > 0 && !slices.Contains(kinds, e.Kind) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, e.Key+".json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, e.Key)
+	}
+	return removed, errors.Join(errs...)
+}
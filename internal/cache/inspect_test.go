@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+func TestKindOf(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		eligibilityCacheKey(models.CSPAzure):       KindEligibility,
+		groupsEligibilityCacheKey(models.CSPAzure): KindGroups,
+		onDemandRolesCacheKey("aws", "/ws"):        KindOnDemand,
+		sessionTimestampsKey:                       KindSessions,
+		sessionSnapshotKey:                         KindSessions,
+		"something_else":                           KindOther,
+	}
+	for key, want := range tests {
+		if got := KindOf(key); got != want {
+			t.Errorf("KindOf(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestEntries(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	s := NewStore(dir, time.Hour)
+
+	mustSet := func(key string, v any) {
+		t.Helper()
+		if err := Set(s, key, v); err != nil {
+			t.Fatalf("Set(%s) error = %v", key, err)
+		}
+	}
+	mustSet("eligibility_azure", models.EligibilityResponse{Response: []models.EligibleTarget{{}, {}}, Total: 2})
+	mustSet("ondemand_roles_aws_x", []models.OnDemandResource{{}, {}, {}})
+	mustSet(sessionTimestampsKey, map[string]SessionRecord{"a": {}})
+	r := NewStatsRecorder()
+	r.RecordLookup(s, "eligibility_azure", true)
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Entries(s)
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+
+	got := map[string]EntryInfo{}
+	for _, e := range entries {
+		got[e.Key] = e
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Entries() = %+v, want 4 entries and no statistics", entries)
+	}
+	for key, items := range map[string]int{"eligibility_azure": 2, "ondemand_roles_aws_x": 3, sessionTimestampsKey: 1} {
+		e := got[key]
+		if e.Items != items || e.CachedAt.IsZero() || e.Size == 0 {
+			t.Errorf("%s = %+v, want %d items, a timestamp and a size", key, e, items)
+		}
+	}
+	if b := got["broken"]; b.Kind != KindOther || !b.CachedAt.IsZero() {
+		t.Errorf("broken = %+v, want an undecodable other entry", b)
+	}
+
+	if entries, err := Entries(NewStore(filepath.Join(dir, "missing"), time.Hour)); err != nil || len(entries) != 0 {
+		t.Errorf("Entries(missing dir) = %v, %v; want none", entries, err)
+	}
+}
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), time.Hour)
+	for _, key := range []string{"eligibility_azure", "eligibility_aws", "groups_eligibility_azure", sessionSnapshotKey} {
+		if err := Set(s, key, "x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewStatsRecorder()
+	r.RecordLookup(s, "eligibility_azure", false)
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := Clear(s, KindEligibility)
+	if err != nil {
+		t.Fatalf("Clear(eligibility) error = %v", err)
+	}
+	if !slices.Equal(removed, []string{"eligibility_aws", "eligibility_azure"}) {
+		t.Errorf("Clear(eligibility) removed %v", removed)
+	}
+
+	removed, err = Clear(s)
+	if err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if !slices.Equal(removed, []string{"groups_eligibility_azure", sessionSnapshotKey}) {
+		t.Errorf("Clear() removed %v", removed)
+	}
+	if LoadStats(s).Kinds[KindEligibility].Misses != 1 {
+		t.Error("Clear() dropped the lookup statistics")
+	}
+}
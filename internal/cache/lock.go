@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ErrLocked is returned by writes to an Offline Store while another process
+// holds its lock.
+var ErrLocked = errors.New("cache is locked by another process")
+
+// lockFileName is the advisory lock file in a Store's directory. It is never
+// removed: deleting a lock file another process has open would let a third
+// process lock a new file of the same name while the first still holds the old.
+const lockFileName = ".lock"
+
+// lock takes the Store's exclusive advisory lock, creating the directory if
+// needed, and blocks until it is available; an Offline Store fails with
+// ErrLocked instead. The lock is held by the open file,
+// so the OS releases it if the process dies; callers must call unlock.
+//
+// It serializes writers only. Readers never lock: writes replace files by
+// rename, so a reader always sees a complete entry.
+func (s *Store) lock() (unlock func(), err error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.dir, lockFileName), os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	take := lockFile
+	if s.offline {
+		take = tryLockFile
+	}
+	if err := take(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
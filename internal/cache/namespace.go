@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Namespace identifies whose data a cache directory holds: eligibility and
+// session state belong to one user of one tenant, reached through one profile.
+type Namespace struct {
+	Profile   string
+	TenantURL string // empty when the SDK discovers it from the username
+	Username  string
+}
+
+// Name returns the namespace's directory name: the profile, for humans, plus
+// a hash of tenant and user, so neither leaks into the file system and any
+// change to them selects a different directory. Tenant URL and username are
+// compared case-insensitively.
+func (n Namespace) Name() string {
+	identity := strings.ToLower(strings.TrimRight(strings.TrimSpace(n.TenantURL), "/")) +
+		"\x00" + strings.ToLower(strings.TrimSpace(n.Username))
+	sum := sha256.Sum256([]byte(identity))
+	return safeName(n.Profile) + "-" + hex.EncodeToString(sum[:8])
+}
+
+// Dir returns the namespace's directory under root.
+func (n Namespace) Dir(root string) string {
+	return filepath.Join(root, n.Name())
+}
+
+// safeName reduces a profile name to characters valid in a file name on every
+// platform.
+func safeName(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// RemoveNamespace deletes everything cached for n under root.
+func RemoveNamespace(root string, n Namespace) error {
+	return os.RemoveAll(n.Dir(root))
+}
+
+// DiscardLegacy removes cache entries written directly under root by versions
+// of grant that did not namespace the cache. Their owner is unknown, so they
+// cannot be migrated into a namespace, only dropped; the next command
+// refetches what it needs. Namespace directories are left alone.
+func DiscardLegacy(root string) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var errs []error
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !(strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".tmp")) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(root, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
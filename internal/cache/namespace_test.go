@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNamespace_Dir(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	base := Namespace{Profile: "grant", TenantURL: "https://abc.id.cyberark.cloud", Username: "alice@example.com"}
+
+	dir := base.Dir(root)
+	if filepath.Dir(dir) != root || !strings.HasPrefix(filepath.Base(dir), "grant-") {
+		t.Errorf("Dir() = %q, want grant-<hash> under %q", dir, root)
+	}
+	if strings.Contains(dir, "alice") || strings.Contains(dir, "abc.id") {
+		t.Errorf("Dir() = %q leaks the identity", dir)
+	}
+
+	same := Namespace{Profile: "grant", TenantURL: "HTTPS://ABC.id.cyberark.cloud/", Username: " Alice@Example.com"}
+	if same.Dir(root) != dir {
+		t.Errorf("case and trailing slash changed the namespace: %q vs %q", same.Dir(root), dir)
+	}
+
+	others := []Namespace{
+		{Profile: "grant", TenantURL: "https://other.id.cyberark.cloud", Username: base.Username},
+		{Profile: "grant", TenantURL: base.TenantURL, Username: "bob@example.com"},
+		{Profile: "work", TenantURL: base.TenantURL, Username: base.Username},
+	}
+	for _, o := range others {
+		if o.Dir(root) == dir {
+			t.Errorf("%+v shares the namespace of %+v", o, base)
+		}
+	}
+
+	if got := filepath.Base(Namespace{Profile: "a/b:c"}.Dir(root)); !strings.HasPrefix(got, "a_b_c-") {
+		t.Errorf("unsafe profile name produced %q", got)
+	}
+}
+
+func TestNamespace_IsolatesStores(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	alice := Namespace{Profile: "grant", Username: "alice"}
+	bob := Namespace{Profile: "grant", Username: "bob"}
+
+	if err := Set(NewStore(alice.Dir(root), time.Hour), "eligibility_azure", "alice's"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	var got string
+	if Get(NewStore(bob.Dir(root), time.Hour), "eligibility_azure", &got) {
+		t.Errorf("bob's store served %q", got)
+	}
+
+	if err := RemoveNamespace(root, alice); err != nil {
+		t.Fatalf("RemoveNamespace() error = %v", err)
+	}
+	if Get(NewStore(alice.Dir(root), time.Hour), "eligibility_azure", &got) {
+		t.Error("entry survived RemoveNamespace")
+	}
+}
+
+func TestDiscardLegacy(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	ns := Namespace{Profile: "grant", Username: "alice"}
+
+	for _, key := range []string{"eligibility_azure", "session_timestamps"} {
+		if err := Set(NewStore(root, time.Hour), key, "legacy"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "session_snapshot.123.tmp"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(NewStore(ns.Dir(root), time.Hour), "eligibility_azure", "current"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if err := DiscardLegacy(root); err != nil {
+		t.Fatalf("DiscardLegacy() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() && e.Name() != lockFileName {
+			t.Errorf("legacy file %s survived", e.Name())
+		}
+	}
+	var got string
+	if !Get(NewStore(ns.Dir(root), time.Hour), "eligibility_azure", &got) || got != "current" {
+		t.Errorf("namespaced entry = %q, want it untouched", got)
+	}
+
+	if err := DiscardLegacy(filepath.Join(root, "missing")); err != nil {
+		t.Errorf("DiscardLegacy(missing dir) error = %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+// SessionLister mirrors cmd.sessionLister to avoid import cycles.
+type SessionLister interface {
+	ListSessions(ctx context.Context, csp *models.CSP) (*models.SessionsResponse, error)
+}
+
+// ObservingSessionLister decorates a session lister so that every session a
+// successful listing returns is recorded with ObserveSessions. This gives
+// sessions elevated elsewhere (another machine, the portal) a first-observed
+// timestamp, and keeps live sessions' timestamps from aging out.
+type ObservingSessionLister struct {
+	inner SessionLister
+	store *Store
+	log   Logger
+}
+
+// NewObservingSessionLister creates a new observing decorator.
+// Logger is optional — pass nil for silent operation.
+func NewObservingSessionLister(inner SessionLister, store *Store, log Logger) *ObservingSessionLister {
+	if log == nil {
+		log = nopLogger{}
+	}
+	return &ObservingSessionLister{inner: inner, store: store, log: log}
+}
+
+// ListSessions lists sessions through the inner lister and records them.
+// Recording is best-effort: a failure is logged and never fails the listing.
+func (l *ObservingSessionLister) ListSessions(ctx context.Context, csp *models.CSP) (*models.SessionsResponse, error) {
+	resp, err := l.inner.ListSessions(ctx, csp)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	ids := make([]string, len(resp.Response))
+	for i, s := range resp.Response {
+		ids[i] = s.SessionID
+	}
+	if err := ObserveSessions(l.store, ids, l.store.now()); err != nil {
+		l.log.Info("failed to record observed sessions: %v", err)
+	}
+	return resp, nil
+}
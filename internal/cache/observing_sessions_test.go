@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/sca/models"
+)
+
+type stubSessionLister struct {
+	resp *models.SessionsResponse
+	err  error
+}
+
+func (s *stubSessionLister) ListSessions(context.Context, *models.CSP) (*models.SessionsResponse, error) {
+	return s.resp, s.err
+}
+
+func TestObservingSessionLister_RecordsListedSessions(t *testing.T) {
+	t.Parallel()
+	store := NewStore(t.TempDir(), 25*time.Hour)
+	now := time.Date(2026, 2, 21, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	inner := &stubSessionLister{resp: &models.SessionsResponse{
+		Response: []models.SessionInfo{{SessionID: "s1"}, {SessionID: "s2"}},
+	}}
+	lister := NewObservingSessionLister(inner, store, nil)
+
+	resp, err := lister.ListSessions(context.Background(), nil)
+	if err != nil || resp != inner.resp {
+		t.Fatalf("ListSessions() = %v, %v; want the inner response", resp, err)
+	}
+
+	starts := SessionStarts(store)
+	for _, id := range []string{"s1", "s2"} {
+		if got := starts[id]; got.Exact || !got.At.Equal(now) {
+			t.Errorf("%s = %+v, want first observed at %v", id, got, now)
+		}
+	}
+}
+
+func TestObservingSessionLister_ErrorRecordsNothing(t *testing.T) {
+	t.Parallel()
+	store := NewStore(t.TempDir(), 25*time.Hour)
+	wantErr := errors.New("boom")
+	lister := NewObservingSessionLister(&stubSessionLister{err: wantErr}, store, nil)
+
+	if _, err := lister.ListSessions(context.Background(), nil); !errors.Is(err, wantErr) {
+		t.Fatalf("error = %v, want %v", err, wantErr)
+	}
+	if starts := SessionStarts(store); len(starts) != 0 {
+		t.Errorf("expected nothing recorded, got %v", starts)
+	}
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"strings"
+	"time"
+)
+
+// KnownSession is one row of the last-known session list. It is denormalized
+// (names already resolved) so offline readers such as 'grant prompt' can render
+// it without eligibility lookups or a network call.
+type KnownSession struct {
+	SessionID       string    `json:"session_id"`
+	Provider        string    `json:"provider"` // lowercase: aws, azure, gcp
+	Type            string    `json:"type"`     // cloud | group
+	Target          string    `json:"target"`   // workspace or group name; the ID when unresolved
+	Role            string    `json:"role,omitempty"`
+	DurationSeconds int       `json:"duration_seconds"` // 0 = unknown (recorded at elevation)
+	ObservedAt      time.Time `json:"observed_at"`      // when grant last saw the session live
+}
+
+const sessionSnapshotKey = "session_snapshot"
+
+// SaveSessionSnapshot replaces the last-known session list with sessions. When
+// provider is non-empty the listing was filtered server-side, so only that
+// provider's entries are replaced and the others are kept.
+func SaveSessionSnapshot(s *Store, provider string, sessions []KnownSession) error {
+	return updateSnapshot(s, func(known []KnownSession) ([]KnownSession, bool) {
+		var kept []KnownSession
+		if provider != "" {
+			for _, ks := range known {
+				if !strings.EqualFold(ks.Provider, provider) {
+					kept = append(kept, ks)
+				}
+			}
+		}
+
+		now := s.now()
+		for _, ks := range sessions {
+			ks.ObservedAt = now
+			kept = append(kept, ks)
+		}
+		return kept, true
+	})
+}
+
+// AddKnownSession inserts or replaces one session in the last-known list,
+// typically right after elevating.
+func AddKnownSession(s *Store, session KnownSession) error {
+	return updateSnapshot(s, func(known []KnownSession) ([]KnownSession, bool) {
+		session.ObservedAt = s.now()
+		for i, ks := range known {
+			if ks.SessionID == session.SessionID {
+				known[i] = session
+				return known, true
+			}
+		}
+		return append(known, session), true
+	})
+}
+
+// ForgetSessions removes sessions from the last-known list, typically after
+// revoking them.
+func ForgetSessions(s *Store, sessionIDs []string) error {
+	drop := make(map[string]bool, len(sessionIDs))
+	for _, id := range sessionIDs {
+		drop[id] = true
+	}
+
+	return updateSnapshot(s, func(known []KnownSession) ([]KnownSession, bool) {
+		kept := make([]KnownSession, 0, len(known))
+		for _, ks := range known {
+			if !drop[ks.SessionID] {
+				kept = append(kept, ks)
+			}
+		}
+		return kept, len(kept) != len(known)
+	})
+}
+
+// KnownSessions returns the last-known session list, dropping entries that
+// must have ended by now: a session observed at T with duration D cannot
+// outlive T+D. Entries with an unknown duration are kept until the next
+// listing replaces them. Returns nil on miss, expiry or error.
+func KnownSessions(s *Store) []KnownSession {
+	var known []KnownSession
+	if !Get(s, sessionSnapshotKey, &known) {
+		return nil
+	}
+	return liveSessions(s, known)
+}
+
+// updateSnapshot applies fn to the live last-known sessions under the Store's
+// lock.
+func updateSnapshot(s *Store, fn func(known []KnownSession) ([]KnownSession, bool)) error {
+	return Update(s, sessionSnapshotKey, func(known []KnownSession) ([]KnownSession, bool) {
+		return fn(liveSessions(s, known))
+	})
+}
+
+// liveSessions filters out the entries of known that must have ended by now.
+func liveSessions(s *Store, known []KnownSession) []KnownSession {
+	now := s.now()
+	live := known[:0]
+	for _, ks := range known {
+		if ks.DurationSeconds == 0 || now.Before(ks.ObservedAt.Add(time.Duration(ks.DurationSeconds)*time.Second)) {
+			live = append(live, ks)
+		}
+	}
+	return live
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func snapshotIDs(known []KnownSession) []string {
+	ids := make([]string, len(known))
+	for i, ks := range known {
+		ids[i] = ks.SessionID
+	}
+	return ids
+}
+
+func TestSessionSnapshot_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), 25*time.Hour)
+	now := time.Date(2026, 2, 21, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	err := SaveSessionSnapshot(s, "", []KnownSession{
+		{SessionID: "aws-1", Provider: "aws", Target: "Prod", Role: "Admin", DurationSeconds: 3600},
+		{SessionID: "az-1", Provider: "azure", Target: "Sub", Role: "Reader", DurationSeconds: 3600},
+	})
+	if err != nil {
+		t.Fatalf("SaveSessionSnapshot() error = %v", err)
+	}
+
+	known := KnownSessions(s)
+	if got := snapshotIDs(known); len(got) != 2 || got[0] != "aws-1" || got[1] != "az-1" {
+		t.Fatalf("KnownSessions() = %v, want [aws-1 az-1]", got)
+	}
+	if !known[0].ObservedAt.Equal(now) {
+		t.Errorf("ObservedAt = %v, want %v", known[0].ObservedAt, now)
+	}
+}
+
+func TestSessionSnapshot_ProviderScopedSave(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), 25*time.Hour)
+
+	_ = SaveSessionSnapshot(s, "", []KnownSession{
+		{SessionID: "aws-1", Provider: "aws", DurationSeconds: 3600},
+		{SessionID: "az-1", Provider: "azure", DurationSeconds: 3600},
+	})
+	// An AWS-only listing must not wipe the Azure entry.
+	_ = SaveSessionSnapshot(s, "AWS", []KnownSession{
+		{SessionID: "aws-2", Provider: "aws", DurationSeconds: 3600},
+	})
+
+	got := snapshotIDs(KnownSessions(s))
+	if len(got) != 2 || got[0] != "az-1" || got[1] != "aws-2" {
+		t.Errorf("KnownSessions() = %v, want [az-1 aws-2]", got)
+	}
+}
+
+func TestSessionSnapshot_AddAndForget(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), 25*time.Hour)
+
+	_ = AddKnownSession(s, KnownSession{SessionID: "s1", Role: "Reader", DurationSeconds: 3600})
+	_ = AddKnownSession(s, KnownSession{SessionID: "s2", DurationSeconds: 3600})
+	_ = AddKnownSession(s, KnownSession{SessionID: "s1", Role: "Admin", DurationSeconds: 3600})
+
+	known := KnownSessions(s)
+	if got := snapshotIDs(known); len(got) != 2 || known[0].Role != "Admin" {
+		t.Fatalf("after re-adding s1: %+v", known)
+	}
+
+	if err := ForgetSessions(s, []string{"s1", "missing"}); err != nil {
+		t.Fatalf("ForgetSessions() error = %v", err)
+	}
+	if got := snapshotIDs(KnownSessions(s)); len(got) != 1 || got[0] != "s2" {
+		t.Errorf("after forgetting s1: %v, want [s2]", got)
+	}
+}
+
+func TestKnownSessions_DropsEndedSessions(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), 25*time.Hour)
+	now := time.Date(2026, 2, 21, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	_ = SaveSessionSnapshot(s, "", []KnownSession{
+		{SessionID: "short", DurationSeconds: 1800},
+		{SessionID: "long", DurationSeconds: 7200},
+	})
+
+	// 1h later the 30m session cannot still be live.
+	s.now = func() time.Time { return now.Add(time.Hour) }
+	if got := snapshotIDs(KnownSessions(s)); len(got) != 1 || got[0] != "long" {
+		t.Errorf("KnownSessions() = %v, want [long]", got)
+	}
+}
+
+func TestKnownSessions_Empty(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), 25*time.Hour)
+
+	if known := KnownSessions(s); known != nil {
+		t.Errorf("expected nil, got %v", known)
+	}
+	if err := ForgetSessions(s, []string{"s1"}); err != nil {
+		t.Errorf("ForgetSessions() on empty store error = %v", err)
+	}
+}
@@ -2,15 +2,37 @@ package cache
 
 import "time"
 
-// SessionRecord stores the timestamp when a session was elevated.
+// SessionRecord stores what grant knows locally about when a session started.
 type SessionRecord struct {
-	ElevatedAt time.Time `json:"elevated_at"`
+	ElevatedAt      time.Time `json:"elevated_at,omitzero"`       // grant elevated the session on this machine
+	FirstObservedAt time.Time `json:"first_observed_at,omitzero"` // a session listing first showed it
+	LastSeenAt      time.Time `json:"last_seen_at,omitzero"`      // a session listing most recently showed it
+}
+
+// lastActivity is the latest time the session was known to exist.
+func (r SessionRecord) lastActivity() time.Time {
+	if r.LastSeenAt.After(r.ElevatedAt) {
+		return r.LastSeenAt
+	}
+	return r.ElevatedAt
+}
+
+// SessionStart is the best locally known start of a session.
+//
+// When Exact is false, At is the first time grant saw the session listed, which
+// is no earlier than its real start: remaining time computed from it is an
+// upper bound.
+type SessionStart struct {
+	At    time.Time
+	Exact bool
 }
 
 const sessionTimestampsKey = "session_timestamps"
 
-// sessionTimestampRetention is how long a locally recorded elevation timestamp
-// stays useful. Entries older than this are filtered out by SessionTimestamps.
+// sessionTimestampRetention is how long a locally recorded timestamp stays
+// useful after the session was last known to exist: its elevation, or the
+// last listing that showed it live. Older entries are filtered out on read, so
+// a session that is still being listed keeps its timestamps past 24h.
 //
 // It is purely local retention for the remaining-time DISPLAY. It is not a
 // session lifetime, not a session limit, and not an access-control boundary:
@@ -20,22 +42,68 @@ const sessionTimestampsKey = "session_timestamps"
 const sessionTimestampRetention = 24 * time.Hour
 
 // RecordSession stores the elevation timestamp for a session ID.
-// It performs a read-modify-write on the session timestamps cache entry.
+// It performs a locked read-modify-write on the session timestamps cache entry.
 func RecordSession(s *Store, sessionID string, now time.Time) error {
-	records := readRecords(s)
-	records[sessionID] = SessionRecord{ElevatedAt: now}
-	return Set(s, sessionTimestampsKey, records)
+	return updateRecords(s, func(records map[string]SessionRecord) bool {
+		rec := records[sessionID]
+		rec.ElevatedAt = now
+		records[sessionID] = rec
+		return true
+	})
+}
+
+// ObserveSessions records that a session listing showed sessionIDs live at
+// now. The first observation of a session is kept; later ones only refresh
+// LastSeenAt.
+func ObserveSessions(s *Store, sessionIDs []string, now time.Time) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+
+	return updateRecords(s, func(records map[string]SessionRecord) bool {
+		for _, id := range sessionIDs {
+			rec := records[id]
+			if rec.FirstObservedAt.IsZero() {
+				rec.FirstObservedAt = now
+			}
+			rec.LastSeenAt = now
+			records[id] = rec
+		}
+		return true
+	})
 }
 
-// SessionTimestamps returns a map of sessionID -> elevatedAt for all tracked sessions.
-// Entries older than sessionTimestampRetention are filtered out. Returns an empty map on error.
+// SessionTimestamps returns a map of sessionID -> elevatedAt for sessions
+// elevated on this machine. Sessions only ever observed in a listing are not
+// included. Entries past sessionTimestampRetention are filtered out. Returns
+// an empty map on error.
 func SessionTimestamps(s *Store) map[string]time.Time {
+	result := make(map[string]time.Time)
+	for id, start := range SessionStarts(s) {
+		if start.Exact {
+			result[id] = start.At
+		}
+	}
+	return result
+}
+
+// SessionStarts returns the best known start of every tracked session: the
+// local elevation time when there is one, otherwise the first observation.
+// Entries past sessionTimestampRetention are filtered out. Returns an empty
+// map on error.
+func SessionStarts(s *Store) map[string]SessionStart {
 	records := readRecords(s)
 	now := s.now()
-	result := make(map[string]time.Time, len(records))
+	result := make(map[string]SessionStart, len(records))
 	for id, rec := range records {
-		if now.Sub(rec.ElevatedAt) <= sessionTimestampRetention {
-			result[id] = rec.ElevatedAt
+		if now.Sub(rec.lastActivity()) > sessionTimestampRetention {
+			continue
+		}
+		switch {
+		case !rec.ElevatedAt.IsZero():
+			result[id] = SessionStart{At: rec.ElevatedAt, Exact: true}
+		case !rec.FirstObservedAt.IsZero():
+			result[id] = SessionStart{At: rec.FirstObservedAt}
 		}
 	}
 	return result
@@ -43,29 +111,21 @@ func SessionTimestamps(s *Store) map[string]time.Time {
 
 // CleanupSessions removes entries for sessions not in the activeIDs list.
 func CleanupSessions(s *Store, activeIDs []string) error {
-	records := readRecords(s)
-	if len(records) == 0 {
-		return nil
-	}
-
 	active := make(map[string]bool, len(activeIDs))
 	for _, id := range activeIDs {
 		active[id] = true
 	}
 
-	changed := false
-	for id := range records {
-		if !active[id] {
-			delete(records, id)
-			changed = true
+	return updateRecords(s, func(records map[string]SessionRecord) bool {
+		changed := false
+		for id := range records {
+			if !active[id] {
+				delete(records, id)
+				changed = true
+			}
 		}
-	}
-
-	if !changed {
-		return nil
-	}
-
-	return Set(s, sessionTimestampsKey, records)
+		return changed
+	})
 }
 
 // readRecords reads the session timestamps cache entry. Returns an empty map on miss/error.
@@ -76,3 +136,14 @@ func readRecords(s *Store) map[string]SessionRecord {
 	}
 	return make(map[string]SessionRecord)
 }
+
+// updateRecords applies fn to the session timestamps under the Store's lock;
+// fn reports whether it changed them.
+func updateRecords(s *Store, fn func(records map[string]SessionRecord) bool) error {
+	return Update(s, sessionTimestampsKey, func(records map[string]SessionRecord) (map[string]SessionRecord, bool) {
+		if records == nil {
+			records = make(map[string]SessionRecord)
+		}
+		return records, fn(records)
+	})
+}
@@ -1,8 +1,11 @@
 package cache
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -198,3 +201,158 @@ func TestRecordSession_Append(t *testing.T) {
 		t.Error("expected sess-2 in timestamps")
 	}
 }
+
+func TestObserveSessions_KeepsFirstObservation(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), 25*time.Hour)
+	first := time.Date(2026, 2, 21, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return first }
+
+	if err := ObserveSessions(s, []string{"remote"}, first); err != nil {
+		t.Fatalf("ObserveSessions() error = %v", err)
+	}
+	if err := ObserveSessions(s, []string{"remote"}, first.Add(time.Hour)); err != nil {
+		t.Fatalf("ObserveSessions() error = %v", err)
+	}
+
+	rec := readRecords(s)["remote"]
+	if !rec.FirstObservedAt.Equal(first) {
+		t.Errorf("FirstObservedAt = %v, want %v", rec.FirstObservedAt, first)
+	}
+	if !rec.LastSeenAt.Equal(first.Add(time.Hour)) {
+		t.Errorf("LastSeenAt = %v, want %v", rec.LastSeenAt, first.Add(time.Hour))
+	}
+}
+
+func TestSessionStarts_ExactAndObserved(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), 25*time.Hour)
+	now := time.Date(2026, 2, 21, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	elevatedAt := now.Add(-30 * time.Minute)
+	_ = RecordSession(s, "local", elevatedAt)
+	// A later observation of a locally elevated session must not demote it.
+	_ = ObserveSessions(s, []string{"local", "remote"}, now)
+
+	starts := SessionStarts(s)
+	if got := starts["local"]; !got.Exact || !got.At.Equal(elevatedAt) {
+		t.Errorf("local = %+v, want exact %v", got, elevatedAt)
+	}
+	if got := starts["remote"]; got.Exact || !got.At.Equal(now) {
+		t.Errorf("remote = %+v, want first observed %v", got, now)
+	}
+
+	timestamps := SessionTimestamps(s)
+	if _, ok := timestamps["remote"]; ok {
+		t.Error("SessionTimestamps must only return locally elevated sessions")
+	}
+	if _, ok := timestamps["local"]; !ok {
+		t.Error("expected local in SessionTimestamps")
+	}
+}
+
+// TestSessionStarts_LiveSessionOutlivesRetention pins that retention is
+// measured from the last time the session was seen live, not its start.
+func TestSessionStarts_LiveSessionOutlivesRetention(t *testing.T) {
+	t.Parallel()
+	elevatedAt := time.Date(2026, 2, 21, 12, 0, 0, 0, time.UTC)
+	now := elevatedAt
+	s := &Store{dir: t.TempDir(), ttl: 10000 * time.Hour, now: func() time.Time { return now }}
+
+	_ = RecordSession(s, "long-lived", elevatedAt)
+	_ = RecordSession(s, "gone", elevatedAt)
+
+	now = elevatedAt.Add(sessionTimestampRetention - time.Hour)
+	_ = ObserveSessions(s, []string{"long-lived"}, now)
+
+	now = elevatedAt.Add(sessionTimestampRetention + time.Hour)
+	starts := SessionStarts(s)
+	if got, ok := starts["long-lived"]; !ok || !got.At.Equal(elevatedAt) {
+		t.Errorf("long-lived = %+v (present %v), want its original elevation time", got, ok)
+	}
+	if _, ok := starts["gone"]; ok {
+		t.Error("expected a session not seen within retention to be filtered")
+	}
+}
+
+// Stress test parameters: stressProcesses copies of the test binary each run
+// stressGoroutines goroutines recording stressRecords sessions apiece.
+const (
+	stressProcesses  = 4
+	stressGoroutines = 8
+	stressRecords    = 10
+	stressDirEnv     = "GRANT_CACHE_STRESS_DIR"
+	stressWorkerEnv  = "GRANT_CACHE_STRESS_WORKER"
+)
+
+// recordSessionsConcurrently records stressGoroutines*stressRecords distinct
+// sessions in dir from parallel goroutines, each with its own Store.
+func recordSessionsConcurrently(t *testing.T, dir, worker string) {
+	now := time.Now()
+	var wg sync.WaitGroup
+	for g := range stressGoroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := NewStore(dir, 25*time.Hour)
+			for r := range stressRecords {
+				id := fmt.Sprintf("%s-%d-%d", worker, g, r)
+				if err := RecordSession(s, id, now); err != nil {
+					t.Errorf("RecordSession(%s) error = %v", id, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestRecordSession_StressHelperProcess is the body of each child process
+// started by TestRecordSession_ConcurrentProcesses; run directly it does
+// nothing.
+func TestRecordSession_StressHelperProcess(t *testing.T) {
+	dir := os.Getenv(stressDirEnv)
+	if dir == "" {
+		return
+	}
+	recordSessionsConcurrently(t, dir, os.Getenv(stressWorkerEnv))
+}
+
+// TestRecordSession_ConcurrentProcesses hammers one session timestamps file
+// from several processes and many goroutines, as parallel grant invocations
+// and credential_process calls do, and asserts no record is lost.
+func TestRecordSession_ConcurrentProcesses(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("starts child processes")
+	}
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	for p := range stressProcesses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestRecordSession_StressHelperProcess$")
+			cmd.Env = append(os.Environ(), stressDirEnv+"="+dir, fmt.Sprintf("%s=proc%d", stressWorkerEnv, p))
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("helper process %d failed: %v\n%s", p, err, out)
+			}
+		}()
+	}
+	// This process joins in too, so in-process and cross-process writers race.
+	recordSessionsConcurrently(t, dir, "parent")
+	wg.Wait()
+
+	records := readRecords(NewStore(dir, 25*time.Hour))
+	want := (stressProcesses + 1) * stressGoroutines * stressRecords
+	if len(records) != want {
+		t.Fatalf("recorded %d sessions, want %d: concurrent writes were lost", len(records), want)
+	}
+	for p := range stressProcesses {
+		id := fmt.Sprintf("proc%d-%d-%d", p, stressGoroutines-1, stressRecords-1)
+		if _, ok := records[id]; !ok {
+			t.Errorf("missing %s", id)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StaleHook is told when a decorator answers from an expired entry: the
+// entry's key and age, and the failure of the API call, or nil when the API is
+// being asked in the background instead.
+type StaleHook func(key string, age time.Duration, err error)
+
+// staleFallback is a decorator's stale-while-error and stale-while-revalidate
+// setting. The zero value never serves stale data.
+type staleFallback struct {
+	maxStale    time.Duration
+	hook        StaleHook
+	revalidator *Revalidator
+}
+
+// maxStaleOf returns how long past its TTL the entry under key may be served.
+// The policy of the entry's kind, if the store has one, overrides maxStale.
+func (f staleFallback) maxStaleOf(store *Store, key string) time.Duration {
+	if p, ok := store.policy(key); ok {
+		return p.MaxStale
+	}
+	return f.maxStale
+}
+
+// serveStale reads the expired entry under key into dst when err is an outage and
+// the entry is within max stale of its TTL, and reports it to the hook.
+func serveStale[T any](store *Store, f staleFallback, key string, err error, dst *T) bool {
+	maxStale := f.maxStaleOf(store, key)
+	if maxStale <= 0 || !isOutage(err) {
+		return false
+	}
+	age, ok := GetStale(store, key, dst, maxStale)
+	if ok && f.hook != nil {
+		f.hook(key, age, err)
+	}
+	return ok
+}
+
+// serveRevalidating reads the expired entry under key into dst when it is
+// within max stale of its TTL and f has a revalidator, reports it to the hook
+// and has the revalidator run fetch to replace it. Without a servable entry
+// the caller fetches in the foreground as usual.
+func serveRevalidating[T any](store *Store, f staleFallback, key string, dst *T, fetch func(ctx context.Context) error) bool {
+	if f.revalidator == nil {
+		return false
+	}
+	maxStale := f.maxStaleOf(store, key)
+	if maxStale <= 0 {
+		return false
+	}
+	age, ok := GetStale(store, key, dst, maxStale)
+	if !ok {
+		return false
+	}
+	if f.hook != nil {
+		f.hook(key, age, nil)
+	}
+	f.revalidator.refresh(key, age, fetch)
+	return true
+}
+
+// Revalidator runs the background refreshes of entries that decorators served
+// stale. A command waits for it, briefly, before exiting, so the refreshed
+// entries are there for the next one, and reports the refreshes that failed.
+type Revalidator struct {
+	timeout  time.Duration
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	running  map[string]bool
+	failures []RefreshFailure
+}
+
+// RefreshFailure is a background refresh that failed, leaving the entry that
+// was served in place.
+type RefreshFailure struct {
+	Key    string
+	Age    time.Duration // of the entry that was served
+	Err    error
+	Outage bool // the API could not answer, as opposed to refusing
+}
+
+// NewRevalidator creates a Revalidator whose refreshes each give up after
+// timeout.
+func NewRevalidator(timeout time.Duration) *Revalidator {
+	return &Revalidator{timeout: timeout, running: make(map[string]bool)}
+}
+
+// refresh runs fetch for the entry under key, served at age, in the background
+// unless a refresh of key is already running.
+func (r *Revalidator) refresh(key string, age time.Duration, fetch func(ctx context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running[key] {
+		return
+	}
+	r.running[key] = true
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		defer cancel()
+		err := fetch(ctx)
+		r.mu.Lock()
+		delete(r.running, key)
+		if err != nil {
+			r.failures = append(r.failures, RefreshFailure{Key: key, Age: age, Err: err, Outage: isOutage(err)})
+		}
+		r.mu.Unlock()
+	}()
+}
+
+// Wait blocks until every background refresh has finished and returns the
+// failures since the last Wait, by key.
+func (r *Revalidator) Wait() []RefreshFailure {
+	r.wg.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	failures := r.failures
+	r.failures = nil
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Key < failures[j].Key })
+	return failures
+}
+
+// WaitTimeout is Wait bounded by timeout. It also returns the keys whose
+// refresh is still running then; they are abandoned if the process exits,
+// which leaves their stale entries for the next command to refresh.
+func (r *Revalidator) WaitTimeout(timeout time.Duration) (failures []RefreshFailure, pending []string) {
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	failures = r.failures
+	r.failures = nil
+	sort.Slice(failures, func(i, j int) bool { return failures[i].Key < failures[j].Key })
+	for key := range r.running {
+		pending = append(pending, key)
+	}
+	sort.Strings(pending)
+	return failures, pending
+}
+
+// isOutage reports whether err means the API could not answer, as opposed to
+// answering no. A 4xx status other than 429 is the service's verdict on the
+// request and must not be papered over with old data; neither is a canceled
+// command.
+func isOutage(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var se httpStatusError
+	if errors.As(err, &se) {
+		status := se.HTTPStatus()
+		return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// httpStatusError is implemented by *sdkclient.StatusError. Matching the method
+// rather than the type keeps this package from importing sdkclient, whose
+// tests import it through testenv.
+type httpStatusError interface {
+	error
+	HTTPStatus() int
+}
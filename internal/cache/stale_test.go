@@ -0,0 +1,310 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/aaearon/grant-cli/internal/sdkclient"
+)
+
+// staleStore returns a Store with a 1h TTL holding value under key, written
+// age ago.
+func staleStore[T any](t *testing.T, key string, value T, age time.Duration) *Store {
+	t.Helper()
+	dir := t.TempDir()
+	past := &Store{dir: dir, ttl: time.Hour, now: func() time.Time { return time.Now().Add(-age) }}
+	if err := Set(past, key, value); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	return NewStore(dir, time.Hour)
+}
+
+func TestGetStale(t *testing.T) {
+	t.Parallel()
+	s := staleStore(t, "k", "old", 5*time.Hour)
+
+	var got string
+	if Get(s, "k", &got) {
+		t.Fatal("Get() served an expired entry")
+	}
+	age, ok := GetStale(s, "k", &got, 6*time.Hour)
+	if !ok || got != "old" || age < 5*time.Hour || age > 5*time.Hour+time.Minute {
+		t.Errorf("GetStale() = %q, %v, %v; want the entry, about 5h old", got, age, ok)
+	}
+	if _, ok := GetStale(s, "k", &got, 3*time.Hour); ok {
+		t.Error("GetStale() served an entry further past its TTL than maxStale")
+	}
+	if _, ok := GetStale(s, "missing", &got, 6*time.Hour); ok {
+		t.Error("GetStale() hit on a missing key")
+	}
+}
+
+func TestIsOutage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("dial tcp: connection refused"), true},
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("list: %w", &sdkclient.StatusError{StatusCode: 503}), true},
+		{&sdkclient.StatusError{StatusCode: 429}, true},
+		{&sdkclient.StatusError{StatusCode: 403}, false},
+		{&sdkclient.StatusError{StatusCode: 401}, false},
+		{fmt.Errorf("list: %w", context.Canceled), false},
+	}
+	for _, tt := range tests {
+		if got := isOutage(tt.err); got != tt.want {
+			t.Errorf("isOutage(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestCachedEligibilityLister_ServeStale(t *testing.T) {
+	t.Parallel()
+	stale := models.EligibilityResponse{Response: []models.EligibleTarget{{WorkspaceID: "ws-1"}}, Total: 1}
+	outage := errors.New("dial tcp: i/o timeout")
+
+	tests := []struct {
+		name      string
+		age       time.Duration
+		err       error
+		refresh   bool
+		maxStale  time.Duration // 0: ServeStale not called
+		wantStale bool
+	}{
+		{name: "outage within max stale", age: 6 * time.Hour, err: outage, maxStale: 24 * time.Hour, wantStale: true},
+		{name: "outage beyond max stale", age: 30 * time.Hour, err: outage, maxStale: 24 * time.Hour},
+		{name: "API refused the request", age: 6 * time.Hour, err: &sdkclient.StatusError{StatusCode: 403}, maxStale: 24 * time.Hour},
+		{name: "refresh asks for live data", age: 6 * time.Hour, err: outage, refresh: true, maxStale: 24 * time.Hour},
+		{name: "not enabled", age: 6 * time.Hour, err: outage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := staleStore(t, eligibilityCacheKey(models.CSPAzure), stale, tt.age)
+			cached := NewCachedEligibilityLister(&mockEligibilityLister{err: tt.err}, nil, store, tt.refresh, nil)
+
+			var hookKey string
+			var hookAge time.Duration
+			if tt.maxStale > 0 {
+				cached.ServeStale(tt.maxStale, func(key string, age time.Duration, err error) {
+					hookKey, hookAge = key, age
+					if !errors.Is(err, tt.err) {
+						t.Errorf("hook err = %v, want %v", err, tt.err)
+					}
+				})
+			}
+
+			resp, err := cached.ListEligibility(t.Context(), models.CSPAzure)
+			if !tt.wantStale {
+				if !errors.Is(err, tt.err) || hookKey != "" {
+					t.Errorf("ListEligibility() = %v, %v (hook %q); want the API error and no stale data", resp, err, hookKey)
+				}
+				return
+			}
+			if err != nil || len(resp.Response) != 1 || resp.Response[0].WorkspaceID != "ws-1" {
+				t.Fatalf("ListEligibility() = %+v, %v; want the stale entry", resp, err)
+			}
+			if hookKey != "eligibility_azure" || hookAge < tt.age {
+				t.Errorf("hook got key %q age %v, want eligibility_azure at least %v", hookKey, hookAge, tt.age)
+			}
+		})
+	}
+}
+
+func TestCachedGroupsEligibilityLister_ServeStale(t *testing.T) {
+	t.Parallel()
+	stale := models.GroupsEligibilityResponse{Response: []models.GroupsEligibleTarget{{GroupID: "g1"}}, Total: 1}
+	store := staleStore(t, groupsEligibilityCacheKey(models.CSPAzure), stale, 90*time.Minute)
+	cached := NewCachedEligibilityLister(nil, &mockGroupsEligibilityLister{err: errors.New("connection reset")}, store, false, nil)
+	cached.ServeStale(time.Hour, nil)
+
+	resp, err := cached.ListGroupsEligibility(t.Context(), models.CSPAzure)
+	if err != nil || len(resp.Response) != 1 || resp.Response[0].GroupID != "g1" {
+		t.Errorf("ListGroupsEligibility() = %+v, %v; want the stale entry", resp, err)
+	}
+}
+
+func TestCachedRolesLister_ServeStale(t *testing.T) {
+	t.Parallel()
+	req := models.OnDemandRequest{PlatformName: "azure_resource", WorkspaceID: "/subscriptions/1"}
+	stale := []models.OnDemandResource{{ResourceID: "r1", ResourceName: "Reader"}}
+	store := staleStore(t, onDemandRolesCacheKey(req.PlatformName, req.WorkspaceID), stale, 3*time.Hour)
+
+	var served bool
+	cached := NewCachedRolesLister(&fakeRolesLister{err: &sdkclient.StatusError{StatusCode: 502}}, store, false, nil)
+	cached.ServeStale(24*time.Hour, func(string, time.Duration, error) { served = true })
+
+	roles, err := cached.ListOnDemandResources(t.Context(), req)
+	if err != nil || len(roles) != 1 || roles[0].ResourceID != "r1" || !served {
+		t.Errorf("ListOnDemandResources() = %+v, %v (hook called: %v); want the stale roles", roles, err, served)
+	}
+}
+
+func TestServeStale_PolicyOverridesMaxStale(t *testing.T) {
+	t.Parallel()
+	stale := models.EligibilityResponse{Response: []models.EligibleTarget{{WorkspaceID: "ws-1"}}, Total: 1}
+	outage := errors.New("dial tcp: i/o timeout")
+
+	tests := []struct {
+		name      string
+		policy    Policy
+		wantStale bool
+	}{
+		{name: "kind allows stale data", policy: Policy{TTL: time.Hour, MaxStale: 12 * time.Hour}, wantStale: true},
+		{name: "kind never serves stale data", policy: Policy{TTL: time.Hour}},
+		{name: "kind disabled", policy: Policy{TTL: time.Hour, MaxStale: 12 * time.Hour, Disabled: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := staleStore(t, eligibilityCacheKey(models.CSPAzure), stale, 6*time.Hour).SetPolicy(KindEligibility, tt.policy)
+			cached := NewCachedEligibilityLister(&mockEligibilityLister{err: outage}, nil, store, false, nil)
+			// The decorator's own setting would serve the entry; the policy decides.
+			cached.ServeStale(24*time.Hour, nil)
+
+			_, err := cached.ListEligibility(t.Context(), models.CSPAzure)
+			if gotStale := err == nil; gotStale != tt.wantStale {
+				t.Errorf("ListEligibility() error = %v, want stale data %v", err, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestCachedEligibilityLister_RefreshInBackground(t *testing.T) {
+	t.Parallel()
+	stale := models.EligibilityResponse{Response: []models.EligibleTarget{{WorkspaceID: "ws-old"}}, Total: 1}
+	fresh := &models.EligibilityResponse{Response: []models.EligibleTarget{{WorkspaceID: "ws-new"}}, Total: 1}
+	key := eligibilityCacheKey(models.CSPAzure)
+
+	tests := []struct {
+		name        string
+		age         time.Duration
+		err         error
+		wantServed  string // the workspace ListEligibility answers with
+		wantCached  string // the workspace cached once the refresh finished
+		wantFailure bool
+	}{
+		{name: "expired entry served, then refreshed", age: 6 * time.Hour, wantServed: "ws-old", wantCached: "ws-new"},
+		{name: "failed refresh keeps the entry", age: 6 * time.Hour, err: errors.New("dial tcp: i/o timeout"), wantServed: "ws-old", wantFailure: true},
+		{name: "beyond max stale is fetched in the foreground", age: 30 * time.Hour, wantServed: "ws-new", wantCached: "ws-new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := staleStore(t, key, stale, tt.age)
+			inner := &mockEligibilityLister{response: fresh, err: tt.err}
+			cached := NewCachedEligibilityLister(inner, nil, store, false, nil)
+			var hookErrs []error
+			cached.ServeStale(24*time.Hour, func(_ string, _ time.Duration, err error) { hookErrs = append(hookErrs, err) })
+			r := NewRevalidator(time.Minute)
+			cached.RefreshInBackground(r)
+
+			resp, err := cached.ListEligibility(t.Context(), models.CSPAzure)
+			if err != nil || resp.Response[0].WorkspaceID != tt.wantServed {
+				t.Fatalf("ListEligibility() = %+v, %v; want %s", resp, err, tt.wantServed)
+			}
+			failures := r.Wait()
+			if inner.calls != 1 {
+				t.Errorf("inner lister called %d times, want once", inner.calls)
+			}
+			if tt.wantServed == "ws-old" && (len(hookErrs) != 1 || hookErrs[0] != nil) {
+				t.Errorf("stale hook errors = %v, want one call with nil", hookErrs)
+			}
+
+			if tt.wantFailure {
+				if len(failures) != 1 || failures[0].Key != key || !failures[0].Outage || failures[0].Age < tt.age {
+					t.Errorf("Wait() = %+v, want the outage of %s", failures, key)
+				}
+				return
+			}
+			if len(failures) != 0 {
+				t.Errorf("Wait() = %+v, want no failures", failures)
+			}
+			var got models.EligibilityResponse
+			if !Get(store, key, &got) || got.Response[0].WorkspaceID != tt.wantCached {
+				t.Errorf("cached entry = %+v, want %s", got, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestRevalidator_OneRefreshPerKey(t *testing.T) {
+	t.Parallel()
+	r := NewRevalidator(time.Minute)
+	release := make(chan struct{})
+	var calls int
+	fetch := func(context.Context) error {
+		calls++
+		<-release
+		return nil
+	}
+
+	r.refresh("k", time.Hour, fetch)
+	r.refresh("k", time.Hour, fetch)
+	close(release)
+	r.Wait()
+
+	if calls != 1 {
+		t.Errorf("fetch ran %d times for one key, want once", calls)
+	}
+	r.refresh("k", time.Hour, fetch)
+	if got := r.Wait(); calls != 2 || len(got) != 0 {
+		t.Errorf("after the first refresh finished: %d calls, failures %+v; want a second refresh", calls, got)
+	}
+}
+
+func TestCachedRolesLister_RefreshInBackground(t *testing.T) {
+	t.Parallel()
+	req := models.OnDemandRequest{PlatformName: "azure_resource", WorkspaceID: "/subscriptions/1"}
+	key := onDemandRolesCacheKey(req.PlatformName, req.WorkspaceID)
+	store := staleStore(t, key, []models.OnDemandResource{{ResourceID: "old"}}, 3*time.Hour)
+	inner := &fakeRolesLister{err: &sdkclient.StatusError{StatusCode: 403}}
+	cached := NewCachedRolesLister(inner, store, false, nil)
+	cached.ServeStale(24*time.Hour, nil)
+	r := NewRevalidator(time.Minute)
+	cached.RefreshInBackground(r)
+
+	roles, err := cached.ListOnDemandResources(t.Context(), req)
+	if err != nil || roles[0].ResourceID != "old" {
+		t.Fatalf("ListOnDemandResources() = %+v, %v; want the expired roles", roles, err)
+	}
+	if failures := r.Wait(); len(failures) != 1 || failures[0].Outage {
+		t.Errorf("Wait() = %+v, want one failure that is not an outage", failures)
+	}
+}
+
+func TestRevalidator_WaitTimeout(t *testing.T) {
+	t.Parallel()
+	r := NewRevalidator(time.Minute)
+	release := make(chan struct{})
+	defer close(release)
+	r.refresh("slow", time.Hour, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	r.refresh("failed", time.Hour, func(context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	start := time.Now()
+	failures, pending := r.WaitTimeout(200 * time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("WaitTimeout() took %s, want it bounded", elapsed)
+	}
+	if len(failures) != 1 || failures[0].Key != "failed" {
+		t.Errorf("failures = %+v, want the failed refresh", failures)
+	}
+	if !slices.Equal(pending, []string{"slow"}) {
+		t.Errorf("pending = %v, want the slow refresh", pending)
+	}
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+const statsKey = "cache_stats"
+
+// LookupCounts are the lookup outcomes of one kind of cache entry.
+type LookupCounts struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+	Stale  int `json:"stale"` // expired entries served instead of a fresh answer
+}
+
+// add returns the sum of c and o.
+func (c LookupCounts) add(o LookupCounts) LookupCounts {
+	return LookupCounts{Hits: c.Hits + o.Hits, Misses: c.Misses + o.Misses, Stale: c.Stale + o.Stale}
+}
+
+// Stats are the lookup counters persisted in a Store's directory.
+type Stats struct {
+	Since time.Time               `json:"since"` // first recorded lookup
+	Kinds map[string]LookupCounts `json:"kinds"`
+}
+
+// statsStore is a view of s whose entries never expire: counters outlive the
+// TTL of the data they count.
+func statsStore(s *Store) *Store {
+	return &Store{dir: s.dir, ttl: math.MaxInt64, now: s.now, sealer: s.sealer, offline: s.offline, policies: s.policies}
+}
+
+// StatsRecorder counts lookups in memory, so a lookup costs no disk I/O.
+// Flush adds the counts to the persisted counters of each store they were
+// recorded against, one write per store directory; a process flushes once,
+// before it exits. It is safe for concurrent use.
+type StatsRecorder struct {
+	mu      sync.Mutex
+	pending map[string]*pendingStats // by store directory
+}
+
+// pendingStats are the unflushed counts of one store directory.
+type pendingStats struct {
+	store *Store
+	kinds map[string]LookupCounts
+}
+
+// NewStatsRecorder creates an empty StatsRecorder.
+func NewStatsRecorder() *StatsRecorder {
+	return &StatsRecorder{pending: make(map[string]*pendingStats)}
+}
+
+// RecordLookup counts a hit or miss of the entry under key in s.
+func (r *StatsRecorder) RecordLookup(s *Store, key string, hit bool) {
+	if hit {
+		r.record(s, key, LookupCounts{Hits: 1})
+	} else {
+		r.record(s, key, LookupCounts{Misses: 1})
+	}
+}
+
+// RecordStale counts an expired entry under key in s served in place of a
+// fresh answer.
+func (r *StatsRecorder) RecordStale(s *Store, key string) {
+	r.record(s, key, LookupCounts{Stale: 1})
+}
+
+func (r *StatsRecorder) record(s *Store, key string, c LookupCounts) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pending[s.dir]
+	if !ok {
+		p = &pendingStats{store: s, kinds: make(map[string]LookupCounts)}
+		r.pending[s.dir] = p
+	}
+	kind := KindOf(key)
+	p.kinds[kind] = p.kinds[kind].add(c)
+}
+
+// Flush adds the recorded counts to the persisted counters and forgets them.
+// The write is not synced to disk: the counters are informational, and losing
+// the last ones in a crash is cheaper than an fsync per command.
+func (r *StatsRecorder) Flush() error {
+	r.mu.Lock()
+	pending := r.pending
+	r.pending = make(map[string]*pendingStats)
+	r.mu.Unlock()
+
+	var errs []error
+	for _, p := range pending {
+		errs = append(errs, addStats(p.store, p.kinds))
+	}
+	return errors.Join(errs...)
+}
+
+// addStats adds counts to the persisted counters of s.
+func addStats(s *Store, counts map[string]LookupCounts) error {
+	st := statsStore(s)
+	unlock, err := st.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var stats Stats
+	Get(st, statsKey, &stats)
+	if stats.Kinds == nil {
+		stats = Stats{Since: s.now(), Kinds: make(map[string]LookupCounts)}
+	}
+	for kind, c := range counts {
+		stats.Kinds[kind] = stats.Kinds[kind].add(c)
+	}
+	return writeUnsynced(st, statsKey, stats)
+}
+
+// LoadStats returns the persisted lookup counters; zero Stats when none have
+// been recorded.
+func LoadStats(s *Store) Stats {
+	var st Stats
+	Get(statsStore(s), statsKey, &st)
+	return st
+}
+
+// ResetStats discards the persisted lookup counters.
+func ResetStats(s *Store) {
+	Invalidate(s, statsKey)
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), time.Hour)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return start }
+
+	if st := LoadStats(s); st.Kinds != nil || !st.Since.IsZero() {
+		t.Errorf("LoadStats() on an empty store = %+v, want zero", st)
+	}
+
+	r := NewStatsRecorder()
+	for _, rec := range []struct {
+		key string
+		hit bool
+	}{{"eligibility_azure", true}, {"eligibility_aws", true}, {"groups_eligibility_azure", false}} {
+		r.RecordLookup(s, rec.key, rec.hit)
+	}
+	r.RecordStale(s, "ondemand_roles_aws_x")
+	if st := LoadStats(s); st.Kinds != nil {
+		t.Fatalf("LoadStats() before Flush = %+v, want nothing written yet", st)
+	}
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	// A later flush adds to the persisted counters.
+	r.RecordLookup(s, "eligibility_gcp", false)
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush() with nothing recorded error = %v", err)
+	}
+
+	// Counters must outlive the TTL of the data they count.
+	s.now = func() time.Time { return start.Add(30 * 24 * time.Hour) }
+	st := LoadStats(s)
+	if !st.Since.Equal(start) {
+		t.Errorf("Since = %v, want %v", st.Since, start)
+	}
+	want := map[string]LookupCounts{
+		KindEligibility: {Hits: 2, Misses: 1},
+		KindGroups:      {Misses: 1},
+		KindOnDemand:    {Stale: 1},
+	}
+	if len(st.Kinds) != len(want) {
+		t.Errorf("Kinds = %+v, want %+v", st.Kinds, want)
+	}
+	for kind, c := range want {
+		if st.Kinds[kind] != c {
+			t.Errorf("Kinds[%s] = %+v, want %+v", kind, st.Kinds[kind], c)
+		}
+	}
+
+	ResetStats(s)
+	if st := LoadStats(s); st.Kinds != nil {
+		t.Errorf("LoadStats() after reset = %+v, want zero", st)
+	}
+}
+
+func TestStatsRecorder_Concurrent(t *testing.T) {
+	t.Parallel()
+	s := NewStore(t.TempDir(), time.Hour)
+	r := NewStatsRecorder()
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.RecordLookup(s, "eligibility_azure", true)
+		}()
+	}
+	wg.Wait()
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := LoadStats(s).Kinds[KindEligibility].Hits; got != 50 {
+		t.Errorf("hits = %d, want 50", got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -16,9 +18,52 @@ const (
 	FavoriteTypeGroups = "groups"
 )
 
+// DefaultProfile is the SDK profile grant uses when none is selected.
+const DefaultProfile = "grant"
+
+// DefaultFavoriteProvider is the provider of a favorite added without one when
+// default_provider is unset.
+const DefaultFavoriteProvider = "azure"
+
 // DefaultCacheTTL is the default eligibility cache TTL.
 const DefaultCacheTTL = 4 * time.Hour
 
+// DefaultCacheMaxStale is how long past its TTL a cache entry may still be
+// served, while it is refreshed or when the API is unreachable, unless
+// cache_max_stale says otherwise.
+const DefaultCacheMaxStale = 24 * time.Hour
+
+// DefaultSessionCacheTTL is how long the last-known session list is trusted
+// unless cache.sessions.ttl says otherwise: minutes, since sessions end and
+// start all the time. cache_ttl does not apply to it.
+const DefaultSessionCacheTTL = 5 * time.Minute
+
+// Cache entry kinds, the keys of the cache: block.
+const (
+	CacheKindEligibility = "eligibility"
+	CacheKindGroups      = "groups"
+	CacheKindOnDemand    = "ondemand"
+	CacheKindSessions    = "sessions"
+)
+
+// CacheKinds lists the cache entry kinds in display order.
+var CacheKinds = []string{CacheKindEligibility, CacheKindGroups, CacheKindOnDemand, CacheKindSessions}
+
+// CacheKindConfig overrides the cache settings of one kind of entry. Empty
+// fields fall back to cache_ttl and cache_max_stale.
+type CacheKindConfig struct {
+	TTL      string `yaml:"ttl,omitempty"`
+	MaxStale string `yaml:"max_stale,omitempty"`
+	Disabled bool   `yaml:"disabled,omitempty"`
+}
+
+// CachePolicy is the effective cache setting of one kind of entry.
+type CachePolicy struct {
+	TTL      time.Duration
+	MaxStale time.Duration
+	Disabled bool
+}
+
 // Favorite represents a saved elevation target.
 type Favorite struct {
 	Type        string `yaml:"type,omitempty"         json:"type,omitempty"`
@@ -27,76 +72,168 @@ type Favorite struct {
 	Role        string `yaml:"role"                   json:"role"`
 	Group       string `yaml:"group,omitempty"        json:"group,omitempty"`
 	DirectoryID string `yaml:"directory_id,omitempty" json:"directoryId,omitempty"`
+	// GroupID pins a group favorite to its group, so a renamed group still
+	// matches.
+	GroupID string `yaml:"group_id,omitempty" json:"groupId,omitempty"`
+	Profile string `yaml:"profile,omitempty"  json:"profile,omitempty"`
+	// WorkspaceID, RoleID and OrganizationID pin a cloud favorite to the
+	// eligible target it was picked from, so a renamed workspace or role still
+	// matches and a name shared by two workspaces does not.
+	WorkspaceID    string `yaml:"workspace_id,omitempty"    json:"workspaceId,omitempty"`
+	RoleID         string `yaml:"role_id,omitempty"         json:"roleId,omitempty"`
+	OrganizationID string `yaml:"organization_id,omitempty" json:"organizationId,omitempty"`
+	// Description, Tags and DefaultReason annotate a favorite: the selector
+	// shows the description, favorites list filters by tag, and request
+	// submit uses the default reason when --reason is not given.
+	Description   string   `yaml:"description,omitempty"    json:"description,omitempty"`
+	Tags          []string `yaml:"tags,omitempty"           json:"tags,omitempty"`
+	DefaultReason string   `yaml:"default_reason,omitempty" json:"defaultReason,omitempty"`
 }
 
 // Config holds the grant application configuration.
 type Config struct {
-	Profile         string              `yaml:"profile"`
-	DefaultProvider string              `yaml:"default_provider"`
-	CacheTTL        string              `yaml:"cache_ttl,omitempty"`
-	Favorites       map[string]Favorite `yaml:"favorites"`
+	// Version is the schema version of the file; see CurrentVersion. Older
+	// files are migrated on load.
+	Version         int                        `yaml:"version"`
+	Profile         string                     `yaml:"profile"`
+	DefaultProvider string                     `yaml:"default_provider,omitempty"`
+	Providers       []string                   `yaml:"providers,omitempty"`
+	CacheTTL        string                     `yaml:"cache_ttl,omitempty"`
+	CacheMaxStale   string                     `yaml:"cache_max_stale,omitempty"`
+	CacheEncrypt    bool                       `yaml:"cache_encrypt,omitempty"`
+	Cache           map[string]CacheKindConfig `yaml:"cache,omitempty"`
+	TeamConfig      string                     `yaml:"team_config,omitempty"`
+	Favorites       map[string]Favorite        `yaml:"favorites"`
 }
 
 // DefaultConfig returns a Config with default values.
 func DefaultConfig() *Config {
 	return &Config{
-		Profile:         "grant",
-		DefaultProvider: "azure",
-		Favorites:       make(map[string]Favorite),
+		Version:   CurrentVersion,
+		Profile:   DefaultProfile,
+		Favorites: make(map[string]Favorite),
 	}
 }
 
 // Load reads a config file from the given path. If the file does not exist,
 // it returns the default config.
 func Load(path string) (*Config, error) {
+	cfg, _, err := loadDocument(path)
+	return cfg, err
+}
+
+// loadDocument loads the config at path as Load does, and also returns the
+// migrated document its values were decoded from; nil for a missing file.
+func loadDocument(path string) (*Config, *yaml.Node, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return DefaultConfig(), nil
+			return DefaultConfig(), nil, nil
 		}
-		return nil, fmt.Errorf("failed to read config: %w", err)
+		return nil, nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	cfg := DefaultConfig()
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, err
+	// Older files are upgraded in memory; UpgradeFile rewrites the user
+	// file.
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, notices, err := migrate(doc)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if cfg.Favorites == nil {
-		cfg.Favorites = make(map[string]Favorite)
+	// decodeDocument validates so an unusable cache_ttl or providers list
+	// surfaces at load rather than later, when some command happens to use
+	// it.
+	cfg, err := decodeDocument(doc)
+	if err != nil {
+		return nil, nil, err
 	}
+	notify(path, notices)
+	return cfg, doc, nil
+}
 
-	// Validate here so an unusable cache_ttl surfaces at load rather than
-	// later, when some command happens to build a cache.
+// validateSettings checks the cache and providers settings Load refuses to
+// start with.
+func validateSettings(cfg *Config) error {
+	if err := checkProviders(cfg.Providers); err != nil {
+		return err
+	}
 	if _, err := ParseCacheTTL(cfg); err != nil {
-		return nil, err
+		return err
 	}
-
-	return cfg, nil
+	if _, err := ParseCacheMaxStale(cfg); err != nil {
+		return err
+	}
+	for kind := range cfg.Cache {
+		if _, err := ParseCachePolicy(cfg, kind); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// Save writes a config to the given path, creating parent directories as needed.
+// Save writes a config to the given path through WriteFile, creating parent
+// directories as needed.
 func Save(cfg *Config, path string) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, data)
+}
+
+// WriteFile replaces the config file at path with data through a temporary
+// file renamed over it, so a failed write never leaves a truncated config.
+func WriteFile(path string, data []byte) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return err
 	}
-
-	data, err := yaml.Marshal(cfg)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
 
-	return os.WriteFile(path, data, 0o600)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
-// LoadDefaultWithPath resolves the config path via ConfigPath() and loads the config.
-// Returns the config, the resolved path, and any error.
+// LoadDefaultWithPath loads the effective config merged from every layer (see
+// LoadLayers). Returns the config, the user config path, and any error. The
+// config is for reading: to change the config, use LoadUserWithPath.
 func LoadDefaultWithPath() (*Config, string, error) {
+	l, err := LoadLayers()
+	if err != nil {
+		return nil, "", err
+	}
+	return l.Config, l.UserPath, nil
+}
+
+// LoadUserWithPath resolves the user config path via ConfigPath() and loads
+// that file alone, ready to be changed and saved back.
+// Returns the config, the resolved path, and any error.
+func LoadUserWithPath() (*Config, string, error) {
 	cfgPath, err := ConfigPath()
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to determine config path: %w", err)
 	}
+	if _, err := UpgradeFile(cfgPath); err != nil {
+		return nil, "", err
+	}
 	cfg, err := Load(cfgPath)
 	if err != nil {
 		// Name the file: with GRANT_CONFIG set, the value alone leaves the
@@ -125,12 +262,79 @@ func ParseCacheTTL(cfg *Config) (time.Duration, error) {
 	if cfg.CacheTTL == "" {
 		return DefaultCacheTTL, nil
 	}
-	d, err := time.ParseDuration(cfg.CacheTTL)
+	return parseTTL("cache_ttl", cfg.CacheTTL)
+}
+
+// ParseCacheMaxStale returns how long past its TTL a cache entry may be served,
+// while it is refreshed or when the API is unreachable. An absent value means the default; zero turns
+// stale serving off. Unlike cache_ttl, zero is therefore meaningful, and only
+// unparseable or negative values are errors.
+func ParseCacheMaxStale(cfg *Config) (time.Duration, error) {
+	if cfg.CacheMaxStale == "" {
+		return DefaultCacheMaxStale, nil
+	}
+	return parseMaxStale("cache_max_stale", cfg.CacheMaxStale)
+}
+
+// ParseCachePolicy returns the effective cache setting of kind: its entry in
+// the cache: block, with unset fields taken from cache_ttl and cache_max_stale.
+// The session list is the exception: it has its own default TTL and is never
+// served stale, so max_stale is an error there.
+func ParseCachePolicy(cfg *Config, kind string) (CachePolicy, error) {
+	if !slices.Contains(CacheKinds, kind) {
+		return CachePolicy{}, fmt.Errorf("unknown cache kind %q under cache: must be one of %s", kind, strings.Join(CacheKinds, ", "))
+	}
+	kc := cfg.Cache[kind]
+	p := CachePolicy{Disabled: kc.Disabled}
+
+	var err error
+	switch {
+	case kc.TTL != "":
+		p.TTL, err = parseTTL("cache."+kind+".ttl", kc.TTL)
+	case kind == CacheKindSessions:
+		p.TTL = DefaultSessionCacheTTL
+	default:
+		p.TTL, err = ParseCacheTTL(cfg)
+	}
 	if err != nil {
-		return 0, fmt.Errorf("invalid cache_ttl %q: must be a positive Go duration such as 4h or 30m: %w", cfg.CacheTTL, err)
+		return CachePolicy{}, err
+	}
+
+	switch {
+	case kind == CacheKindSessions && kc.MaxStale != "":
+		return CachePolicy{}, fmt.Errorf("invalid cache.sessions.max_stale %q: the session list is never served stale", kc.MaxStale)
+	case kind == CacheKindSessions:
+	case kc.MaxStale != "":
+		p.MaxStale, err = parseMaxStale("cache."+kind+".max_stale", kc.MaxStale)
+	default:
+		p.MaxStale, err = ParseCacheMaxStale(cfg)
+	}
+	if err != nil {
+		return CachePolicy{}, err
+	}
+	return p, nil
+}
+
+// parseTTL parses the TTL setting named field.
+func parseTTL(field, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive Go duration such as 4h or 30m: %w", field, value, err)
 	}
 	if d <= 0 {
-		return 0, fmt.Errorf("invalid cache_ttl %q: must be greater than zero; use --refresh to bypass the cache for a single command", cfg.CacheTTL)
+		return 0, fmt.Errorf("invalid %s %q: must be greater than zero; use --refresh to bypass the cache for a single command", field, value)
+	}
+	return d, nil
+}
+
+// parseMaxStale parses the max-stale setting named field.
+func parseMaxStale(field, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be a Go duration such as 24h, or 0 to never serve stale data: %w", field, value, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must not be negative; use 0 to never serve stale data", field, value)
 	}
 	return d, nil
 }
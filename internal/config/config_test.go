@@ -110,6 +110,38 @@ func TestSaveConfig_CreatesDirectory(t *testing.T) {
 	}
 }
 
+// TestSaveConfig_ReplacesByRename pins that Save never truncates the file in
+// place: the old file is replaced by a new one, and no temporary is left.
+func TestSaveConfig_ReplacesByRename(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("profile: old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Save(DefaultConfig(), path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.SameFile(before, after) {
+		t.Error("Save rewrote the config in place, want it replaced by rename")
+	}
+	if mode := after.Mode().Perm(); mode != 0o600 {
+		t.Errorf("mode = %v, want 0600", mode)
+	}
+	if tmps, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmps) != 0 {
+		t.Errorf("temporary files left: %v", tmps)
+	}
+}
+
 func TestSaveConfig_RoundTrip(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "config.yaml")
@@ -167,8 +199,8 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Profile != "grant" {
 		t.Errorf("profile = %q, want %q", cfg.Profile, "grant")
 	}
-	if cfg.DefaultProvider != "azure" {
-		t.Errorf("default_provider = %q, want %q", cfg.DefaultProvider, "azure")
+	if cfg.DefaultProvider != "" {
+		t.Errorf("default_provider = %q, want it unset", cfg.DefaultProvider)
 	}
 	if cfg.Favorites == nil {
 		t.Fatal("favorites should not be nil")
@@ -347,8 +379,8 @@ favorites:
 	if !ok {
 		t.Fatal("expected favorite 'old-fav' to exist")
 	}
-	if fav.Type != "" {
-		t.Errorf("type = %q, want empty string for legacy favorite", fav.Type)
+	if fav.Type != FavoriteTypeCloud {
+		t.Errorf("type = %q, want the legacy favorite migrated to %q", fav.Type, FavoriteTypeCloud)
 	}
 	if fav.ResolvedType() != FavoriteTypeCloud {
 		t.Errorf("ResolvedType() = %q, want %q", fav.ResolvedType(), FavoriteTypeCloud)
@@ -422,8 +454,8 @@ favorites:
 	}
 
 	legacy := cfg.Favorites["legacy-fav"]
-	if legacy.Type != "" {
-		t.Errorf("legacy-fav type = %q, want empty", legacy.Type)
+	if legacy.Type != FavoriteTypeCloud {
+		t.Errorf("legacy-fav type = %q, want it migrated to %q", legacy.Type, FavoriteTypeCloud)
 	}
 	if legacy.ResolvedType() != FavoriteTypeCloud {
 		t.Errorf("legacy-fav ResolvedType() = %q, want %q", legacy.ResolvedType(), FavoriteTypeCloud)
@@ -604,6 +636,164 @@ func TestLoad_InvalidCacheTTLErrors(t *testing.T) {
 	}
 }
 
+func TestLoad_Providers(t *testing.T) {
+	t.Parallel()
+	for content, wantErr := range map[string]string{
+		"providers: [aws, gcp]\n": "",
+		"providers: [aws, oci]\n": `invalid providers entry "oci"`,
+		"providers: [aws, aws]\n": "listed twice",
+	} {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		cfg, err := Load(path)
+		if wantErr == "" {
+			if err != nil || len(cfg.Providers) != 2 {
+				t.Errorf("Load(%q) = %+v, %v; want providers aws and gcp", content, cfg, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("Load(%q) error = %v, want it to contain %q", content, err, wantErr)
+		}
+	}
+}
+
+func TestParseCacheMaxStale(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		value           string
+		want            time.Duration
+		wantErrContains []string
+	}{
+		{name: "empty uses the 24h default", value: "", want: 24 * time.Hour},
+		{name: "custom 6h", value: "6h", want: 6 * time.Hour},
+		{name: "zero turns stale serving off", value: "0", want: 0},
+		{
+			name:            "unparseable is rejected with the expected syntax",
+			value:           "forever",
+			wantErrContains: []string{`invalid cache_max_stale "forever"`, "such as 24h, or 0", `time: invalid duration "forever"`},
+		},
+		{
+			name:            "negative is rejected",
+			value:           "-1h",
+			wantErrContains: []string{`invalid cache_max_stale "-1h"`, "must not be negative"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseCacheMaxStale(&Config{CacheMaxStale: tt.value})
+
+			if len(tt.wantErrContains) > 0 {
+				if err == nil {
+					t.Fatalf("ParseCacheMaxStale(%q) = %v, want an error", tt.value, got)
+				}
+				for _, want := range tt.wantErrContains {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("ParseCacheMaxStale(%q) error = %q, want it to contain %q", tt.value, err, want)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseCacheMaxStale(%q) unexpected error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCacheMaxStale(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_InvalidCacheMaxStaleErrors(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("profile: p\ncache_max_stale: -5m\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "cache_max_stale") {
+		t.Errorf("Load() error = %v, want it to name cache_max_stale", err)
+	}
+}
+
+func TestParseCachePolicy(t *testing.T) {
+	t.Parallel()
+	cfg := &Config{
+		CacheTTL:      "2h",
+		CacheMaxStale: "12h",
+		Cache: map[string]CacheKindConfig{
+			CacheKindGroups:   {TTL: "168h"},
+			CacheKindOnDemand: {MaxStale: "0", Disabled: true},
+			CacheKindSessions: {TTL: "5m"},
+		},
+	}
+	want := map[string]CachePolicy{
+		CacheKindEligibility: {TTL: 2 * time.Hour, MaxStale: 12 * time.Hour},
+		CacheKindGroups:      {TTL: 168 * time.Hour, MaxStale: 12 * time.Hour},
+		CacheKindOnDemand:    {TTL: 2 * time.Hour, Disabled: true},
+		CacheKindSessions:    {TTL: 5 * time.Minute},
+	}
+	for kind, w := range want {
+		got, err := ParseCachePolicy(cfg, kind)
+		if err != nil || got != w {
+			t.Errorf("ParseCachePolicy(%s) = %+v, %v; want %+v", kind, got, err, w)
+		}
+	}
+
+	// cache_ttl is the eligibility TTL; the session list keeps its own default.
+	if got, _ := ParseCachePolicy(DefaultConfig(), CacheKindSessions); got.TTL != DefaultSessionCacheTTL || got.MaxStale != 0 {
+		t.Errorf("default sessions policy = %+v", got)
+	}
+
+	errTests := []struct {
+		kind    string
+		kc      CacheKindConfig
+		wantErr string
+	}{
+		{kind: CacheKindEligibility, kc: CacheKindConfig{TTL: "0"}, wantErr: `invalid cache.eligibility.ttl "0": must be greater than zero`},
+		{kind: CacheKindGroups, kc: CacheKindConfig{TTL: "weekly"}, wantErr: `invalid cache.groups.ttl "weekly"`},
+		{kind: CacheKindOnDemand, kc: CacheKindConfig{MaxStale: "-1h"}, wantErr: `invalid cache.ondemand.max_stale "-1h": must not be negative`},
+		{kind: CacheKindSessions, kc: CacheKindConfig{MaxStale: "1h"}, wantErr: "never served stale"},
+		{kind: "roles", wantErr: `unknown cache kind "roles"`},
+	}
+	for _, tt := range errTests {
+		cfg := &Config{Cache: map[string]CacheKindConfig{tt.kind: tt.kc}}
+		if _, err := ParseCachePolicy(cfg, tt.kind); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ParseCachePolicy(%s, %+v) error = %v, want %q", tt.kind, tt.kc, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoad_CacheBlock(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.yaml")
+	if err := os.WriteFile(good, []byte("cache:\n  groups:\n    ttl: 168h\n  sessions:\n    disabled: true\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, err := Load(good)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Cache[CacheKindGroups].TTL != "168h" || !cfg.Cache[CacheKindSessions].Disabled {
+		t.Errorf("Cache = %+v", cfg.Cache)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("cache:\n  eligibilty:\n    ttl: 1h\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(bad); err == nil || !strings.Contains(err.Error(), `unknown cache kind "eligibilty"`) {
+		t.Errorf("Load() error = %v, want the unknown kind named", err)
+	}
+}
+
 // TestLoad_PartialYAMLKeepsDefaults pins that a file setting only some keys
 // leaves the rest at their defaults — dropping the DefaultConfig() seed would
 // silently lose `profile: grant`.
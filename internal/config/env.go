@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// LayerEnv is the pseudo-layer of GRANT_* environment variables, above every
+// config file.
+const LayerEnv = "env"
+
+// envPrefix starts every config environment variable.
+const envPrefix = "GRANT_"
+
+// favoriteEnvPrefix starts an inline favorite: GRANT_FAVORITE_<NAME>.
+const favoriteEnvPrefix = envPrefix + "FAVORITE_"
+
+// EnvVar returns the environment variable that overrides the named key:
+// GRANT_ followed by the key in upper case with dots replaced by
+// underscores, e.g. GRANT_CACHE_TTL or GRANT_CACHE_GROUPS_TTL.
+func EnvVar(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// loadEnvLayer builds the env layer from environ, in os.Environ form. Values
+// are checked like grant config set checks them, and an invalid one is an
+// error naming its variable.
+func loadEnvLayer(environ []string) (Layer, error) {
+	layer := Layer{Name: LayerEnv, Path: "environment", keys: map[string]bool{}, vars: map[string]string{}}
+	cfg := &Config{Favorites: make(map[string]Favorite)}
+
+	vars := make(map[string]string)
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if ok && strings.HasPrefix(name, envPrefix) && value != "" {
+			vars[name] = value
+		}
+	}
+
+	for _, k := range keys {
+		name := EnvVar(k.name)
+		value, ok := vars[name]
+		if !ok {
+			continue
+		}
+		if k.parse != nil {
+			if err := k.parse(value); err != nil {
+				return Layer{}, fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+		k.set(cfg, value)
+		layer.keys[k.name] = true
+		layer.vars[k.name] = name
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		suffix, ok := strings.CutPrefix(name, favoriteEnvPrefix)
+		if !ok {
+			continue
+		}
+		favName := strings.ReplaceAll(strings.ToLower(suffix), "_", "-")
+		fav, err := ParseFavoriteSpec(vars[name])
+		if favName == "" && err == nil {
+			err = fmt.Errorf("missing favorite name after %s", favoriteEnvPrefix)
+		}
+		if err != nil {
+			return Layer{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		cfg.Favorites[favName] = fav
+		layer.keys["favorites."+favName] = true
+		layer.vars["favorites."+favName] = name
+	}
+
+	if len(layer.keys) > 0 {
+		layer.Config = cfg
+	}
+	return layer, nil
+}
+
+// ParseFavoriteSpec parses a favorite written as grant favorites list shows
+// it: provider/target/role, or groups/group. The target may itself contain
+// slashes; the role is everything after the last one.
+func ParseFavoriteSpec(spec string) (Favorite, error) {
+	provider, rest, ok := strings.Cut(spec, "/")
+	if ok && provider == FavoriteTypeGroups {
+		if rest == "" {
+			return Favorite{}, fmt.Errorf("invalid favorite %q: want groups/<group>", spec)
+		}
+		return Favorite{Type: FavoriteTypeGroups, Provider: "azure", Group: rest}, nil
+	}
+	i := strings.LastIndex(rest, "/")
+	if !ok || i <= 0 || i == len(rest)-1 {
+		return Favorite{}, fmt.Errorf("invalid favorite %q: want provider/target/role or groups/<group>", spec)
+	}
+	fav := Favorite{Type: FavoriteTypeCloud, Provider: provider, Target: rest[:i], Role: rest[i+1:]}
+	if err := checkProvider("provider", provider); err != nil {
+		return Favorite{}, fmt.Errorf("invalid favorite %q: %w", spec, err)
+	}
+	return fav, nil
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnvVar(t *testing.T) {
+	t.Parallel()
+	for key, want := range map[string]string{
+		"cache_ttl":              "GRANT_CACHE_TTL",
+		"default_provider":       "GRANT_DEFAULT_PROVIDER",
+		"cache.groups.max_stale": "GRANT_CACHE_GROUPS_MAX_STALE",
+	} {
+		if got := EnvVar(key); got != want {
+			t.Errorf("EnvVar(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestLoadLayers_EnvOverridesFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	user := writeLayer(t, dir, "config.yaml",
+		"default_provider: azure\ncache_ttl: 2h\nfavorites:\n  prod-admin:\n    provider: azure\n    target: Prod\n    role: Owner\n")
+
+	l, err := loadLayers(filepath.Join(dir, "system.yaml"), user, "", []string{
+		"GRANT_DEFAULT_PROVIDER=aws",
+		"GRANT_CACHE_GROUPS_TTL=30m",
+		"GRANT_FAVORITE_PROD_ADMIN=aws/Prod/Admin",
+		"GRANT_FAVORITE_IT=groups/IT Admins",
+		"GRANT_CACHE_TTL=",
+		"HOME=/home/x",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := l.Config
+	if cfg.DefaultProvider != "aws" || cfg.CacheTTL != "2h" || cfg.Cache["groups"].TTL != "30m" {
+		t.Errorf("merged config = %+v, want env over file and an empty var ignored", cfg)
+	}
+	if fav := cfg.Favorites["prod-admin"]; fav.Type != FavoriteTypeCloud || fav.Provider != "aws" || fav.Role != "Admin" {
+		t.Errorf("prod-admin = %+v, want the GRANT_FAVORITE_PROD_ADMIN cloud favorite", fav)
+	}
+	if fav := cfg.Favorites["it"]; fav.Type != FavoriteTypeGroups || fav.Group != "IT Admins" {
+		t.Errorf("it = %+v, want the IT Admins group", fav)
+	}
+
+	sources := map[string]string{}
+	for _, s := range l.Settings() {
+		sources[s.Key] = s.Source
+	}
+	for key, want := range map[string]string{
+		"default_provider":     "env GRANT_DEFAULT_PROVIDER",
+		"cache_ttl":            user,
+		"cache.groups.ttl":     "env GRANT_CACHE_GROUPS_TTL",
+		"favorites.prod-admin": "env GRANT_FAVORITE_PROD_ADMIN",
+	} {
+		if sources[key] != want {
+			t.Errorf("source of %s = %q, want %q", key, sources[key], want)
+		}
+	}
+}
+
+func TestLoadLayers_InvalidEnvNamesVariable(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, kv := range []string{
+		"GRANT_CACHE_TTL=soon",
+		"GRANT_DEFAULT_PROVIDER=oracle",
+		"GRANT_FAVORITE_PROD=aws/Prod",
+		"GRANT_FAVORITE_=aws/Prod/Admin",
+	} {
+		_, err := loadLayers(filepath.Join(dir, "system.yaml"), filepath.Join(dir, "config.yaml"), "", []string{kv})
+		name, _, _ := strings.Cut(kv, "=")
+		if err == nil || !strings.Contains(err.Error(), "invalid "+name) {
+			t.Errorf("%s: err = %v, want it to name %s", kv, err, name)
+		}
+	}
+}
+
+func TestParseFavoriteSpec(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		spec    string
+		want    Favorite
+		wantErr bool
+	}{
+		{spec: "aws/Prod/Admin", want: Favorite{Type: FavoriteTypeCloud, Provider: "aws", Target: "Prod", Role: "Admin"}},
+		{spec: "azure/Sub A/rg-1/Reader", want: Favorite{Type: FavoriteTypeCloud, Provider: "azure", Target: "Sub A/rg-1", Role: "Reader"}},
+		{spec: "groups/Ops", want: Favorite{Type: FavoriteTypeGroups, Provider: "azure", Group: "Ops"}},
+		{spec: "oci/Prod/Admin", wantErr: true},
+		{spec: "aws/Prod", wantErr: true},
+		{spec: "aws/Prod/", wantErr: true},
+		{spec: "groups/", wantErr: true},
+		{spec: "aws", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFavoriteSpec(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFavoriteSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFavoriteSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -2,7 +2,11 @@ package config
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // FavoriteEntry pairs a favorite name with its data, used for sorted listing.
@@ -12,14 +16,14 @@ type FavoriteEntry struct {
 }
 
 // AddFavorite adds a named favorite to the config. Returns an error if the name
-// already exists. Defaults provider to "azure" if empty.
+// already exists. Defaults provider to DefaultFavoriteProvider if empty.
 func AddFavorite(cfg *Config, name string, fav Favorite) error {
 	if _, exists := cfg.Favorites[name]; exists {
 		return fmt.Errorf("favorite %q already exists", name)
 	}
 
 	if fav.Provider == "" {
-		fav.Provider = "azure"
+		fav.Provider = DefaultFavoriteProvider
 	}
 
 	cfg.Favorites[name] = fav
@@ -36,6 +40,22 @@ func RemoveFavorite(cfg *Config, name string) error {
 	return nil
 }
 
+// RenameFavorite renames a favorite, keeping its fields. Returns an error if
+// oldName is not found or newName already exists.
+func RenameFavorite(cfg *Config, oldName, newName string) error {
+	fav, exists := cfg.Favorites[oldName]
+	if !exists {
+		return fmt.Errorf("favorite %q not found", oldName)
+	}
+	if _, exists := cfg.Favorites[newName]; exists {
+		return fmt.Errorf("favorite %q already exists", newName)
+	}
+
+	delete(cfg.Favorites, oldName)
+	cfg.Favorites[newName] = fav
+	return nil
+}
+
 // GetFavorite retrieves a favorite by name. Returns an error if not found.
 func GetFavorite(cfg *Config, name string) (Favorite, error) {
 	fav, exists := cfg.Favorites[name]
@@ -54,6 +74,33 @@ func (f Favorite) ResolvedType() string {
 	return f.Type
 }
 
+// AvailableIn reports whether the favorite can be used with the named profile.
+// Favorites saved before profiles existed have no profile and are available in
+// every profile.
+func (f Favorite) AvailableIn(profile string) bool {
+	return f.Profile == "" || f.Profile == profile
+}
+
+// HasTag reports whether the favorite is tagged tag, ignoring case.
+func (f Favorite) HasTag(tag string) bool {
+	return slices.ContainsFunc(f.Tags, func(t string) bool {
+		return strings.EqualFold(t, tag)
+	})
+}
+
+// ParseTags splits a comma-separated tag list, dropping blanks and
+// duplicates; "" clears the tags.
+func ParseTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 // ListFavorites returns all favorites sorted alphabetically by name.
 func ListFavorites(cfg *Config) []FavoriteEntry {
 	entries := make([]FavoriteEntry, 0, len(cfg.Favorites))
@@ -65,3 +112,36 @@ func ListFavorites(cfg *Config) []FavoriteEntry {
 	})
 	return entries
 }
+
+// favoritesFile is the document grant favorites export writes: the version
+// and favorites: block of a config file, so a config file can be imported
+// too.
+type favoritesFile struct {
+	Version   int                 `yaml:"version"`
+	Favorites map[string]Favorite `yaml:"favorites"`
+}
+
+// MarshalFavorites encodes favorites as a config file holding only them.
+func MarshalFavorites(favorites map[string]Favorite) ([]byte, error) {
+	return yaml.Marshal(favoritesFile{Version: CurrentVersion, Favorites: favorites})
+}
+
+// ParseFavorites reads the favorites of a config file or an export, YAML or
+// JSON, migrating it like Load. Other settings in it are ignored.
+func ParseFavorites(data []byte) (map[string]Favorite, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := migrate(doc); err != nil {
+		return nil, err
+	}
+	var file favoritesFile
+	if err := doc.Decode(&file); err != nil {
+		return nil, err
+	}
+	if file.Favorites == nil {
+		file.Favorites = make(map[string]Favorite)
+	}
+	return file.Favorites, nil
+}
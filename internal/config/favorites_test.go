@@ -1,6 +1,7 @@
 package config
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -278,3 +279,65 @@ func TestResolvedType(t *testing.T) {
 		})
 	}
 }
+
+func TestFavorite_AvailableIn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		fav     Favorite
+		profile string
+		want    bool
+	}{
+		{"untagged is available everywhere", Favorite{}, "acme", true},
+		{"same profile", Favorite{Profile: "acme"}, "acme", true},
+		{"other profile", Favorite{Profile: "acme"}, "grant", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.fav.AvailableIn(tt.profile); got != tt.want {
+				t.Errorf("AvailableIn(%q) = %v, want %v", tt.profile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameFavorite(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.Favorites["prod"] = Favorite{Provider: "aws", Target: "Prod", Role: "Admin", Tags: []string{"prod"}}
+	cfg.Favorites["dev"] = Favorite{Provider: "aws", Target: "Dev", Role: "Admin"}
+
+	if err := RenameFavorite(cfg, "prod", "dev"); err == nil {
+		t.Error("RenameFavorite() onto an existing name succeeded")
+	}
+	if err := RenameFavorite(cfg, "missing", "x"); err == nil {
+		t.Error("RenameFavorite() of an unknown favorite succeeded")
+	}
+	if err := RenameFavorite(cfg, "prod", "prod-admin"); err != nil {
+		t.Fatalf("RenameFavorite() error = %v", err)
+	}
+	if _, ok := cfg.Favorites["prod"]; ok {
+		t.Error("old name still present")
+	}
+	if fav := cfg.Favorites["prod-admin"]; fav.Target != "Prod" || !fav.HasTag("PROD") {
+		t.Errorf("renamed favorite = %+v", fav)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "", want: nil},
+		{in: "prod", want: []string{"prod"}},
+		{in: " prod, aws ,,prod", want: []string{"prod", "aws"}},
+	}
+	for _, tt := range tests {
+		if got := ParseTags(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
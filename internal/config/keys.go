@@ -0,0 +1,423 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Providers lists the cloud providers a config value may name.
+var Providers = []string{"azure", "aws", "gcp"}
+
+// SourceDefault is the source of a value no config file sets.
+const SourceDefault = "default"
+
+// Setting is one effective config value and where it came from.
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// key describes one scalar setting of the config file.
+type key struct {
+	name string
+	// get returns the effective value, with defaults applied.
+	get func(cfg *Config) string
+	// set stores value, which has already passed parse.
+	set func(cfg *Config, value string)
+	// unset clears the value so its default applies.
+	unset func(cfg *Config)
+	// parse checks value before it is stored.
+	parse func(value string) error
+}
+
+// keys lists every settable key in display order.
+var keys = buildKeys()
+
+func buildKeys() []key {
+	ks := []key{
+		{
+			name:  "profile",
+			get:   func(cfg *Config) string { return cmp.Or(cfg.Profile, DefaultProfile) },
+			set:   func(cfg *Config, v string) { cfg.Profile = v },
+			unset: func(cfg *Config) { cfg.Profile = DefaultProfile },
+		},
+		{
+			name:  "default_provider",
+			get:   func(cfg *Config) string { return cfg.DefaultProvider },
+			set:   func(cfg *Config, v string) { cfg.DefaultProvider = v },
+			unset: func(cfg *Config) { cfg.DefaultProvider = "" },
+			parse: func(v string) error { return checkProvider("default_provider", v) },
+		},
+		{
+			name:  "providers",
+			get:   func(cfg *Config) string { return strings.Join(cfg.Providers, ",") },
+			set:   func(cfg *Config, v string) { cfg.Providers = splitProviders(v) },
+			unset: func(cfg *Config) { cfg.Providers = nil },
+			parse: func(v string) error { return checkProviders(splitProviders(v)) },
+		},
+		{
+			name: "cache_ttl",
+			get: func(cfg *Config) string {
+				return effectiveDuration(cfg.CacheTTL, func() (time.Duration, error) { return ParseCacheTTL(cfg) })
+			},
+			set:   func(cfg *Config, v string) { cfg.CacheTTL = v },
+			unset: func(cfg *Config) { cfg.CacheTTL = "" },
+			parse: func(v string) error { _, err := parseTTL("cache_ttl", v); return err },
+		},
+		{
+			name: "cache_max_stale",
+			get: func(cfg *Config) string {
+				return effectiveDuration(cfg.CacheMaxStale, func() (time.Duration, error) { return ParseCacheMaxStale(cfg) })
+			},
+			set:   func(cfg *Config, v string) { cfg.CacheMaxStale = v },
+			unset: func(cfg *Config) { cfg.CacheMaxStale = "" },
+			parse: func(v string) error { _, err := parseMaxStale("cache_max_stale", v); return err },
+		},
+		{
+			name:  "cache_encrypt",
+			get:   func(cfg *Config) string { return strconv.FormatBool(cfg.CacheEncrypt) },
+			set:   func(cfg *Config, v string) { cfg.CacheEncrypt, _ = strconv.ParseBool(v) },
+			unset: func(cfg *Config) { cfg.CacheEncrypt = false },
+			parse: func(v string) error { return checkBool("cache_encrypt", v) },
+		},
+		{
+			name:  "team_config",
+			get:   func(cfg *Config) string { return cfg.TeamConfig },
+			set:   func(cfg *Config, v string) { cfg.TeamConfig = v },
+			unset: func(cfg *Config) { cfg.TeamConfig = "" },
+		},
+	}
+	for _, kind := range CacheKinds {
+		ks = append(ks, cacheKindKeys(kind)...)
+	}
+	return ks
+}
+
+// cacheKindKeys returns the keys of kind's entry in the cache: block.
+func cacheKindKeys(kind string) []key {
+	prefix := "cache." + kind + "."
+	policy := func(cfg *Config) CachePolicy {
+		p, _ := ParseCachePolicy(cfg, kind)
+		return p
+	}
+	update := func(cfg *Config, fn func(kc *CacheKindConfig)) {
+		kc := cfg.Cache[kind]
+		fn(&kc)
+		if kc == (CacheKindConfig{}) {
+			delete(cfg.Cache, kind)
+			return
+		}
+		if cfg.Cache == nil {
+			cfg.Cache = make(map[string]CacheKindConfig)
+		}
+		cfg.Cache[kind] = kc
+	}
+	return []key{
+		{
+			name: prefix + "ttl",
+			get: func(cfg *Config) string {
+				return cmp.Or(cfg.Cache[kind].TTL, formatDuration(policy(cfg).TTL))
+			},
+			set:   func(cfg *Config, v string) { update(cfg, func(kc *CacheKindConfig) { kc.TTL = v }) },
+			unset: func(cfg *Config) { update(cfg, func(kc *CacheKindConfig) { kc.TTL = "" }) },
+			parse: func(v string) error { _, err := parseTTL(prefix+"ttl", v); return err },
+		},
+		{
+			name: prefix + "max_stale",
+			get: func(cfg *Config) string {
+				return cmp.Or(cfg.Cache[kind].MaxStale, formatDuration(policy(cfg).MaxStale))
+			},
+			set:   func(cfg *Config, v string) { update(cfg, func(kc *CacheKindConfig) { kc.MaxStale = v }) },
+			unset: func(cfg *Config) { update(cfg, func(kc *CacheKindConfig) { kc.MaxStale = "" }) },
+			parse: func(v string) error {
+				if kind == CacheKindSessions {
+					return fmt.Errorf("invalid %smax_stale %q: the session list is never served stale", prefix, v)
+				}
+				_, err := parseMaxStale(prefix+"max_stale", v)
+				return err
+			},
+		},
+		{
+			name: prefix + "disabled",
+			get:  func(cfg *Config) string { return strconv.FormatBool(cfg.Cache[kind].Disabled) },
+			set: func(cfg *Config, v string) {
+				update(cfg, func(kc *CacheKindConfig) { kc.Disabled, _ = strconv.ParseBool(v) })
+			},
+			unset: func(cfg *Config) { update(cfg, func(kc *CacheKindConfig) { kc.Disabled = false }) },
+			parse: func(v string) error { return checkBool(prefix+"disabled", v) },
+		},
+	}
+}
+
+// Keys returns the names of the settable keys in display order.
+func Keys() []string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.name
+	}
+	return names
+}
+
+// lookupKey returns the key called name.
+func lookupKey(name string) (key, error) {
+	for _, k := range keys {
+		if k.name == name {
+			return k, nil
+		}
+	}
+	return key{}, fmt.Errorf("unknown config key %q: must be one of %s", name, strings.Join(Keys(), ", "))
+}
+
+// Get returns the effective value of the named key, with defaults applied.
+func Get(cfg *Config, name string) (string, error) {
+	k, err := lookupKey(name)
+	if err != nil {
+		return "", err
+	}
+	return k.get(cfg), nil
+}
+
+// Set stores value under the named key. An invalid value leaves cfg unchanged.
+func Set(cfg *Config, name, value string) error {
+	k, err := lookupKey(name)
+	if err != nil {
+		return err
+	}
+	if k.parse != nil {
+		if err := k.parse(value); err != nil {
+			return err
+		}
+	}
+	k.set(cfg, value)
+	return nil
+}
+
+// Unset clears the named key so its default applies.
+func Unset(cfg *Config, name string) error {
+	k, err := lookupKey(name)
+	if err != nil {
+		return err
+	}
+	k.unset(cfg)
+	return nil
+}
+
+// documentKeys returns the dotted names of every value set in the config
+// document doc: the migrated one its values are decoded from, so a value a
+// migration removed is not counted as set. A nil doc sets nothing.
+func documentKeys(doc *yaml.Node) (map[string]bool, error) {
+	if doc == nil {
+		return map[string]bool{}, nil
+	}
+	var raw map[string]any
+	if err := doc.Decode(&raw); err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool)
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			if v == nil {
+				continue
+			}
+			set[prefix+k] = true
+			if child, ok := v.(map[string]any); ok {
+				walk(prefix+k+".", child)
+			}
+		}
+	}
+	walk("", raw)
+	return set, nil
+}
+
+// CheckKeys reports every key of the config text data that grant does not
+// know, with its line: Load ignores them, so a misspelt key such as cache_tll
+// would otherwise silently keep its default.
+func CheckKeys(data []byte) error {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	checkMappingKeys(doc, reflect.TypeFor[Config](), "", &errs)
+	return errors.Join(errs...)
+}
+
+// checkMappingKeys checks the keys of mapping m against the yaml fields of
+// struct t, then the keys nested under them.
+func checkMappingKeys(m *yaml.Node, t reflect.Type, prefix string, errs *[]error) {
+	fields := make(map[string]reflect.Type)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			fields[name] = f.Type
+		}
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		key := m.Content[i]
+		ft, ok := fields[key.Value]
+		if !ok {
+			*errs = append(*errs, fmt.Errorf("line %d: unknown key %q", key.Line, prefix+key.Value))
+			continue
+		}
+		checkValueKeys(m.Content[i+1], ft, prefix+key.Value+".", errs)
+	}
+}
+
+// checkValueKeys checks the keys of v if it is a mapping decoded into a
+// struct, or into a map of structs such as favorites.
+func checkValueKeys(v *yaml.Node, t reflect.Type, prefix string, errs *[]error) {
+	if v.Kind != yaml.MappingNode {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		checkMappingKeys(v, t, prefix, errs)
+	case reflect.Map:
+		for i := 0; i+1 < len(v.Content); i += 2 {
+			checkValueKeys(v.Content[i+1], t.Elem(), prefix+v.Content[i].Value+".", errs)
+		}
+	}
+}
+
+// Validate checks everything Load checks plus the values Load tolerates:
+// default_provider and every favorite's type, provider, target, role and
+// group. It reports all problems at once.
+func Validate(cfg *Config) error {
+	var errs []error
+	if err := validateSettings(cfg); err != nil {
+		errs = append(errs, err)
+	}
+	if cfg.DefaultProvider != "" {
+		if err := checkProvider("default_provider", cfg.DefaultProvider); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, entry := range ListFavorites(cfg) {
+		if err := entry.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("favorite %q: %w", entry.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validate checks the fields of a favorite against its type.
+func (f Favorite) validate() error {
+	switch f.ResolvedType() {
+	case FavoriteTypeCloud:
+		if err := checkProvider("provider", f.Provider); err != nil {
+			return err
+		}
+		if f.Target == "" || f.Role == "" {
+			return errors.New("target and role are required")
+		}
+		if f.Group != "" || f.DirectoryID != "" || f.GroupID != "" {
+			return errors.New("group, directory_id and group_id require type groups")
+		}
+		if (f.RoleID != "" || f.OrganizationID != "") && f.WorkspaceID == "" {
+			return errors.New("role_id and organization_id require workspace_id")
+		}
+	case FavoriteTypeGroups:
+		if f.Provider != "" && f.Provider != "azure" {
+			return fmt.Errorf("invalid provider %q: group favorites must use azure", f.Provider)
+		}
+		if f.Group == "" {
+			return errors.New("group is required")
+		}
+		if f.Target != "" || f.Role != "" || f.WorkspaceID != "" || f.RoleID != "" || f.OrganizationID != "" {
+			return errors.New("target, role and their IDs cannot be used with type groups")
+		}
+	default:
+		return fmt.Errorf("invalid type %q: must be one of: %s, %s", f.Type, FavoriteTypeCloud, FavoriteTypeGroups)
+	}
+	return ValidateTags(f.Tags)
+}
+
+// ValidateTags checks favorite tags: each is one word, without commas.
+func ValidateTags(tags []string) error {
+	for _, tag := range tags {
+		if tag == "" || strings.ContainsAny(tag, ", \t") {
+			return fmt.Errorf("invalid tag %q: tags cannot be empty or contain commas or spaces", tag)
+		}
+	}
+	return nil
+}
+
+// Describe renders the favorite as grant favorites list does:
+// provider/target/role, or groups/group.
+func (f Favorite) Describe() string {
+	if f.ResolvedType() == FavoriteTypeGroups {
+		return "groups/" + f.Group
+	}
+	return f.Provider + "/" + f.Target + "/" + f.Role
+}
+
+func checkProvider(field, value string) error {
+	if !slices.Contains(Providers, value) {
+		return fmt.Errorf("invalid %s %q: must be one of: %s", field, value, strings.Join(Providers, ", "))
+	}
+	return nil
+}
+
+// checkProviders checks a providers allow-list: known providers, each once.
+func checkProviders(providers []string) error {
+	for i, p := range providers {
+		if err := checkProvider("providers entry", p); err != nil {
+			return err
+		}
+		if slices.Contains(providers[:i], p) {
+			return fmt.Errorf("invalid providers: %q is listed twice", p)
+		}
+	}
+	return nil
+}
+
+// splitProviders parses a comma-separated providers value, as given to
+// grant config set or GRANT_PROVIDERS; empty means every provider.
+func splitProviders(v string) []string {
+	var out []string
+	for p := range strings.SplitSeq(v, ",") {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func checkBool(field, value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("invalid %s %q: must be true or false", field, value)
+	}
+	return nil
+}
+
+// effectiveDuration returns raw when set, else the default parse returns.
+func effectiveDuration(raw string, parse func() (time.Duration, error)) string {
+	if raw != "" {
+		return raw
+	}
+	d, _ := parse()
+	return formatDuration(d)
+}
+
+// formatDuration renders d without zero trailing units: 4h rather than 4h0m0s.
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
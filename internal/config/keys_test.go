@@ -0,0 +1,172 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetSetUnset(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+
+	for key, want := range map[string]string{
+		"profile":                  DefaultProfile,
+		"default_provider":         "",
+		"cache_ttl":                "4h",
+		"cache_max_stale":          "24h",
+		"cache_encrypt":            "false",
+		"cache.sessions.ttl":       "5m",
+		"cache.sessions.max_stale": "0s",
+		"cache.groups.disabled":    "false",
+	} {
+		if got, err := Get(cfg, key); err != nil || got != want {
+			t.Errorf("Get(%q) = %q, %v; want %q", key, got, err, want)
+		}
+	}
+
+	if err := Set(cfg, "cache_ttl", "30m"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Get(cfg, "cache.groups.ttl"); got != "30m" {
+		t.Errorf("cache.groups.ttl = %q, want it to inherit cache_ttl", got)
+	}
+	if err := Set(cfg, "cache.groups.ttl", "168h"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Cache["groups"].TTL != "168h" {
+		t.Errorf("cache = %+v", cfg.Cache)
+	}
+	if err := Unset(cfg, "cache.groups.ttl"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Cache["groups"]; ok {
+		t.Errorf("cache = %+v, want the empty groups entry removed", cfg.Cache)
+	}
+	if err := Set(cfg, "cache_encrypt", "true"); err != nil || !cfg.CacheEncrypt {
+		t.Errorf("Set(cache_encrypt) = %v, CacheEncrypt = %v", err, cfg.CacheEncrypt)
+	}
+	if err := Set(cfg, "providers", "aws, GCP"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Get(cfg, "providers"); got != "aws,gcp" || len(cfg.Providers) != 2 {
+		t.Errorf("providers = %q (%q), want aws,gcp", got, cfg.Providers)
+	}
+}
+
+func TestSet_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		key, value, wantErr string
+	}{
+		{"nope", "x", "unknown config key"},
+		{"default_provider", "oracle", "must be one of: azure, aws, gcp"},
+		{"cache_ttl", "0", "must be greater than zero"},
+		{"cache_max_stale", "soon", "invalid cache_max_stale"},
+		{"cache.sessions.max_stale", "1h", "never served stale"},
+		{"cache.eligibility.disabled", "maybe", "must be true or false"},
+		{"providers", "aws,oci", `invalid providers entry "oci"`},
+		{"providers", "aws,aws", "listed twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			t.Parallel()
+			cfg := DefaultConfig()
+			err := Set(cfg, tt.key, tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Set(%q, %q) = %v, want error containing %q", tt.key, tt.value, err, tt.wantErr)
+			}
+			if cfg.DefaultProvider != "" || cfg.CacheTTL != "" || cfg.Cache != nil || cfg.Providers != nil {
+				t.Errorf("config changed by a rejected value: %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestCheckKeys(t *testing.T) {
+	t.Parallel()
+	data := []byte(`version: 1
+cache_tll: 2h
+cache:
+  groups:
+    ttl: 1h
+    max_stael: 2h
+favorites:
+  prod:
+    type: cloud
+    provider: aws
+    target: Prod
+    role: Admin
+    rolle: Reader
+`)
+	err := CheckKeys(data)
+	if err == nil {
+		t.Fatal("CheckKeys() = nil, want unknown keys")
+	}
+	for _, want := range []string{
+		`line 2: unknown key "cache_tll"`,
+		`line 6: unknown key "cache.groups.max_stael"`,
+		`line 13: unknown key "favorites.prod.rolle"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("CheckKeys() missing %q in:\n%v", want, err)
+		}
+	}
+	if n := strings.Count(err.Error(), "unknown key"); n != 3 {
+		t.Errorf("CheckKeys() reported %d keys, want 3:\n%v", n, err)
+	}
+
+	if err := CheckKeys([]byte("cache_ttl: 2h\nfavorites:\n  grp:\n    type: groups\n    group: Admins\n")); err != nil {
+		t.Errorf("CheckKeys(known keys) = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	cfg := DefaultConfig()
+	cfg.DefaultProvider = "oracle"
+	cfg.Favorites = map[string]Favorite{
+		"ok":         {Provider: "aws", Target: "Prod", Role: "Admin"},
+		"grp":        {Type: FavoriteTypeGroups, Provider: "azure", Group: "Admins"},
+		"badtype":    {Type: "vm", Provider: "aws", Target: "Prod", Role: "Admin"},
+		"noprov":     {Provider: "oci", Target: "Prod", Role: "Admin"},
+		"norole":     {Provider: "aws", Target: "Prod"},
+		"nogroup":    {Type: FavoriteTypeGroups, Provider: "azure"},
+		"awsgroups":  {Type: FavoriteTypeGroups, Provider: "aws", Group: "Admins"},
+		"badtag":     {Provider: "aws", Target: "Prod", Role: "Admin", Tags: []string{"two words"}},
+		"pinned":     {Provider: "aws", Target: "Prod", Role: "Admin", WorkspaceID: "111", RoleID: "r"},
+		"roleidonly": {Provider: "aws", Target: "Prod", Role: "Admin", RoleID: "r"},
+		"pinnedgrp":  {Type: FavoriteTypeGroups, Group: "Admins", WorkspaceID: "111"},
+		"grpid":      {Type: FavoriteTypeGroups, Group: "Admins", DirectoryID: "dir", GroupID: "g"},
+		"cloudgrpid": {Provider: "aws", Target: "Prod", Role: "Admin", GroupID: "g"},
+	}
+
+	err := Validate(cfg)
+	if err == nil {
+		t.Fatal("Validate() = nil, want errors")
+	}
+	for _, want := range []string{
+		`invalid default_provider "oracle"`,
+		`favorite "badtype": invalid type "vm"`,
+		`favorite "noprov": invalid provider "oci"`,
+		`favorite "norole": target and role are required`,
+		`favorite "nogroup": group is required`,
+		`favorite "awsgroups": invalid provider "aws"`,
+		`favorite "badtag": invalid tag "two words"`,
+		`favorite "roleidonly": role_id and organization_id require workspace_id`,
+		`favorite "pinnedgrp": target, role and their IDs cannot be used with type groups`,
+		`favorite "cloudgrpid": group, directory_id and group_id require type groups`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() missing %q in:\n%v", want, err)
+		}
+	}
+	for _, ok := range []string{`"ok"`, `"grp"`, `"pinned"`, `"grpid"`} {
+		if strings.Contains(err.Error(), ok) {
+			t.Errorf("Validate() reported valid favorite %s:\n%v", ok, err)
+		}
+	}
+
+	if err := Validate(DefaultConfig()); err != nil {
+		t.Errorf("Validate(DefaultConfig()) = %v", err)
+	}
+}
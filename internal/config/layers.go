@@ -0,0 +1,264 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config layers, lowest precedence first.
+const (
+	LayerSystem  = "system"
+	LayerTeam    = "team"
+	LayerUser    = "user"
+	LayerProject = "project"
+)
+
+// DefaultSystemConfigPath is the machine-wide config file, typically managed by
+// IT. GRANT_SYSTEM_CONFIG overrides it.
+const DefaultSystemConfigPath = "/etc/grant/config.yaml"
+
+// ProjectConfigName is the per-project config file, looked up from the working
+// directory upward.
+const ProjectConfigName = ".grant.yaml"
+
+// Layer is one config file taking part in the merge.
+type Layer struct {
+	Name string
+	Path string
+	// Config is the file alone, with defaults applied; nil if it does not
+	// exist.
+	Config *Config
+	keys   map[string]bool
+	// vars maps each key of the env layer to its variable.
+	vars map[string]string
+}
+
+// Layers is the effective config merged from the system, team, user and
+// project files, then the GRANT_* environment variables. Only the user layer
+// is ever written.
+type Layers struct {
+	// Config is the merged, effective config. Do not Save it: that would copy
+	// the other layers' values into the user file.
+	Config *Config
+	// UserPath is the user config file, ConfigPath().
+	UserPath string
+	// Layers lists every layer, lowest precedence first, including those
+	// whose file does not exist.
+	Layers []Layer
+}
+
+// SystemConfigPath returns the system config path, respecting the
+// GRANT_SYSTEM_CONFIG env var.
+func SystemConfigPath() string {
+	if p := os.Getenv("GRANT_SYSTEM_CONFIG"); p != "" {
+		return p
+	}
+	return DefaultSystemConfigPath
+}
+
+// FindProjectConfig returns the nearest ProjectConfigName in dir or one of its
+// parents, or "" if there is none.
+func FindProjectConfig(dir string) string {
+	for {
+		p := filepath.Join(dir, ProjectConfigName)
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadLayers loads and merges every config layer. A value set in a higher
+// layer replaces the same value from a lower one; favorites are merged by
+// name. The team file is GRANT_TEAM_CONFIG, else the team_config setting of
+// the user or system file, e.g. a path inside a repository checkout.
+// Environment variables named by EnvVar, and GRANT_FAVORITE_<NAME>, override
+// every file.
+func LoadLayers() (*Layers, error) {
+	userPath, err := ConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine config path: %w", err)
+	}
+	wd, _ := os.Getwd()
+	return loadLayers(SystemConfigPath(), userPath, wd, os.Environ())
+}
+
+// loadLayers is LoadLayers with the environment passed in. An empty wd skips
+// the project layer.
+func loadLayers(systemPath, userPath, wd string, environ []string) (*Layers, error) {
+	system, err := loadLayer(LayerSystem, systemPath)
+	if err != nil {
+		return nil, err
+	}
+	// Every layer is migrated in memory: reading the config never writes
+	// it. LoadUserWithPath upgrades the user file before changing it.
+	user, err := loadLayer(LayerUser, userPath)
+	if err != nil {
+		return nil, err
+	}
+	project := Layer{Name: LayerProject}
+	if wd != "" {
+		if p := FindProjectConfig(wd); p != "" && !samePath(p, userPath) {
+			if project, err = loadLayer(LayerProject, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+	env, err := loadEnvLayer(environ)
+	if err != nil {
+		return nil, err
+	}
+	team := Layer{Name: LayerTeam}
+	if p := teamConfigPath(env.Config, system, user); p != "" {
+		if team, err = loadLayer(LayerTeam, p); err != nil {
+			return nil, err
+		}
+	}
+
+	l := &Layers{Config: DefaultConfig(), UserPath: userPath, Layers: []Layer{system, team, user, project, env}}
+	for _, layer := range l.Layers {
+		if layer.Config != nil {
+			merge(l.Config, layer)
+		}
+	}
+	if err := validateSettings(l.Config); err != nil {
+		return nil, fmt.Errorf("invalid merged config: %w", err)
+	}
+	return l, nil
+}
+
+// loadLayer loads the layer file at path; a missing file is an empty layer.
+func loadLayer(name, path string) (Layer, error) {
+	layer := Layer{Name: name, Path: path}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return layer, nil
+	}
+	cfg, doc, err := loadDocument(path)
+	if err != nil {
+		// Name the file: with several layers, or GRANT_CONFIG set, the value
+		// alone leaves the user guessing which config to edit.
+		return Layer{}, fmt.Errorf("failed to load config %s: %w", path, err)
+	}
+	keys, err := documentKeys(doc)
+	if err != nil {
+		return Layer{}, fmt.Errorf("failed to load config %s: %w", path, err)
+	}
+	layer.Config = cfg
+	layer.keys = keys
+	return layer, nil
+}
+
+// teamConfigPath resolves the team file from GRANT_TEAM_CONFIG, held in env,
+// or the team_config setting of the user, then the system, layer. A relative
+// team_config is relative to the file that sets it.
+func teamConfigPath(env *Config, system, user Layer) string {
+	if env != nil && env.TeamConfig != "" {
+		return expandHome(env.TeamConfig)
+	}
+	for _, layer := range []Layer{user, system} {
+		if layer.Config == nil || layer.Config.TeamConfig == "" {
+			continue
+		}
+		p := expandHome(layer.Config.TeamConfig)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(filepath.Dir(layer.Path), p)
+		}
+		return p
+	}
+	return ""
+}
+
+// merge copies the values layer sets onto dst.
+func merge(dst *Config, layer Layer) {
+	for _, k := range keys {
+		if layer.keys[k.name] {
+			k.set(dst, k.get(layer.Config))
+		}
+	}
+	for name, fav := range layer.Config.Favorites {
+		dst.Favorites[name] = fav
+	}
+}
+
+// Source returns the highest layer that sets the dotted key, e.g. cache_ttl
+// or favorites.prod; ok is false when only the default applies.
+func (l *Layers) Source(key string) (Layer, bool) {
+	for i := len(l.Layers) - 1; i >= 0; i-- {
+		if l.Layers[i].keys[key] {
+			return l.Layers[i], true
+		}
+	}
+	return Layer{}, false
+}
+
+// Origin describes where layer takes key from: its file, or for the env layer
+// the variable, e.g. "env GRANT_CACHE_TTL".
+func (layer Layer) Origin(key string) string {
+	if name, ok := layer.vars[key]; ok {
+		return "env " + name
+	}
+	return layer.Path
+}
+
+// FavoriteSource returns the layer the named favorite comes from.
+func (l *Layers) FavoriteSource(name string) (Layer, bool) {
+	return l.Source("favorites." + name)
+}
+
+// Settings returns the effective value and source of every key, followed by
+// the favorites. The source is the Origin in the highest layer setting the
+// value, or SourceDefault.
+func (l *Layers) Settings() []Setting {
+	source := func(key string) string {
+		if layer, ok := l.Source(key); ok {
+			return layer.Origin(key)
+		}
+		return SourceDefault
+	}
+
+	out := make([]Setting, 0, len(keys)+len(l.Config.Favorites))
+	for _, k := range keys {
+		out = append(out, Setting{Key: k.name, Value: k.get(l.Config), Source: source(k.name)})
+	}
+	for _, entry := range ListFavorites(l.Config) {
+		name := "favorites." + entry.Name
+		out = append(out, Setting{Key: name, Value: entry.Describe(), Source: source(name)})
+	}
+	return out
+}
+
+// expandHome replaces a leading ~/ with the home directory.
+func expandHome(p string) string {
+	rest, ok := strings.CutPrefix(p, "~"+string(filepath.Separator))
+	if !ok {
+		rest, ok = strings.CutPrefix(p, "~/")
+	}
+	if !ok {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, rest)
+}
+
+// samePath reports whether a and b name the same file.
+func samePath(a, b string) bool {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(ai, bi)
+}
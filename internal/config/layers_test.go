@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeLayer writes content to dir/name and returns the path.
+func writeLayer(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLayers_Precedence(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	system := writeLayer(t, dir, "etc/config.yaml",
+		"default_provider: gcp\ncache_ttl: 8h\nfavorites:\n  it:\n    provider: azure\n    target: Shared\n    role: Reader\n")
+	team := writeLayer(t, dir, "team/grant.yaml",
+		"cache_ttl: 2h\ncache:\n  groups:\n    ttl: 168h\nfavorites:\n  team-prod:\n    provider: aws\n    target: Prod\n    role: ReadOnly\n")
+	user := writeLayer(t, dir, "home/config.yaml",
+		"default_provider: aws\nteam_config: ../team/grant.yaml\nfavorites:\n  mine:\n    provider: aws\n    target: Dev\n    role: Admin\n")
+	project := writeLayer(t, dir, "repo/.grant.yaml", "cache_ttl: 30m\n")
+	wd := filepath.Join(dir, "repo", "sub", "dir")
+	if err := os.MkdirAll(wd, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := loadLayers(system, user, wd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := l.Config
+	if cfg.DefaultProvider != "aws" || cfg.CacheTTL != "30m" || cfg.Cache["groups"].TTL != "168h" {
+		t.Errorf("merged config = %+v", cfg)
+	}
+	if len(cfg.Favorites) != 3 {
+		t.Errorf("favorites = %v, want it, team-prod and mine", cfg.Favorites)
+	}
+
+	for key, want := range map[string]string{
+		"default_provider":    user,
+		"cache_ttl":           project,
+		"cache.groups.ttl":    team,
+		"favorites.it":        system,
+		"favorites.team-prod": team,
+		"favorites.mine":      user,
+	} {
+		src, ok := l.Source(key)
+		if !ok || src.Path != want {
+			t.Errorf("Source(%q) = %+v, %v; want %s", key, src, ok, want)
+		}
+	}
+	if _, ok := l.Source("cache_max_stale"); ok {
+		t.Error("Source(cache_max_stale) found a layer, want the default")
+	}
+
+	settings := l.Settings()
+	if s := settings[0]; s.Key != "profile" || s.Source != SourceDefault {
+		t.Errorf("settings[0] = %+v, want the default profile", s)
+	}
+}
+
+func TestLoadLayers_TeamEnvWinsOverSetting(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	envTeam := writeLayer(t, dir, "env-team.yaml", "default_provider: gcp\n")
+	writeLayer(t, dir, "team.yaml", "default_provider: azure\n")
+	user := writeLayer(t, dir, "config.yaml", "team_config: team.yaml\n")
+
+	l, err := loadLayers(filepath.Join(dir, "missing.yaml"), user, "", []string{"GRANT_TEAM_CONFIG=" + envTeam})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Config.DefaultProvider != "gcp" {
+		t.Errorf("default_provider = %q, want the GRANT_TEAM_CONFIG file's gcp", l.Config.DefaultProvider)
+	}
+}
+
+func TestLoadLayers_ErrorNamesFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	system := writeLayer(t, dir, "system.yaml", "cache_ttl: soon\n")
+
+	_, err := loadLayers(system, filepath.Join(dir, "config.yaml"), "", nil)
+	if err == nil || !strings.Contains(err.Error(), system) {
+		t.Fatalf("err = %v, want it to name %s", err, system)
+	}
+}
+
+func TestFindProjectConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	want := writeLayer(t, dir, "a/"+ProjectConfigName, "")
+	deep := filepath.Join(dir, "a", "b", "c")
+	if err := os.MkdirAll(deep, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := FindProjectConfig(deep); got != want {
+		t.Errorf("FindProjectConfig(%s) = %q, want %q", deep, got, want)
+	}
+	if got := FindProjectConfig(filepath.Join(dir)); got != "" && strings.HasPrefix(got, dir) {
+		t.Errorf("FindProjectConfig(%s) = %q, want nothing inside the temp dir", dir, got)
+	}
+}
@@ -5,15 +5,19 @@
 package config_test
 
 import (
+	"io"
 	"os"
 	"testing"
 
+	"github.com/aaearon/grant-cli/internal/config"
 	"github.com/aaearon/grant-cli/internal/testenv"
 )
 
 // TestMain redirects HOME and friends at a throwaway directory so tests in this
-// package can never touch the developer's real ~/.grant or ~/.idsec.
+// package can never touch the developer's real ~/.grant or ~/.idsec, and keeps
+// the notices of migrated fixtures out of the test output.
 func TestMain(m *testing.M) {
+	config.Notices = io.Discard
 	os.Exit(testenv.Run(m.Run))
 }
 
@@ -0,0 +1,271 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// migration upgrades a config document from version to-1 to version to. It
+// works on the YAML node tree rather than on Config, so a step can read
+// fields the current Config no longer has, and comments survive the upgrade.
+// A step that only changes what a value means has no apply, just a notice
+// for documents that set the value.
+type migration struct {
+	to          int
+	description string
+	apply       func(doc *yaml.Node) error
+	notice      func(doc *yaml.Node) string
+}
+
+// migrations lists every schema change in order. A file without version: is
+// version 0. Append a step, with its golden files under testdata/migrations,
+// whenever the meaning of an existing field changes.
+var migrations = []migration{
+	{to: 1, description: "write the implicit cloud type of favorites", apply: migrateFavoriteTypes},
+	{to: 2, description: "default_provider also narrows the providers queried", notice: defaultProviderNotice},
+}
+
+// CurrentVersion is the config schema version this grant writes.
+var CurrentVersion = len(migrations)
+
+// now is the clock of upgrade backups, replaced in tests.
+var now = time.Now
+
+// Notices receives the notices of migrations, such as a value whose meaning
+// changed. Each file's are written once per process.
+var Notices io.Writer = os.Stderr
+
+var (
+	noticesMu sync.Mutex
+	noticed   = make(map[string]bool) // by path
+)
+
+// notify writes the migration notices of the file at path to Notices, unless
+// they were written already.
+func notify(path string, notices []string) {
+	if len(notices) == 0 {
+		return
+	}
+	noticesMu.Lock()
+	defer noticesMu.Unlock()
+	if noticed[path] {
+		return
+	}
+	noticed[path] = true
+	for _, n := range notices {
+		fmt.Fprintf(Notices, "Notice: %s: %s\n", path, n)
+	}
+}
+
+// migrateFavoriteTypes makes the type of every favorite explicit: before
+// version 1 an empty type meant cloud.
+func migrateFavoriteTypes(doc *yaml.Node) error {
+	favorites := mappingValue(doc, "favorites")
+	if favorites == nil || favorites.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 1; i < len(favorites.Content); i += 2 {
+		fav := favorites.Content[i]
+		if fav.Kind != yaml.MappingNode {
+			continue
+		}
+		if t := mappingValue(fav, "type"); t != nil && t.Value != "" {
+			continue
+		}
+		setMappingValue(fav, "type", FavoriteTypeCloud)
+	}
+	return nil
+}
+
+// defaultProviderNotice explains a default_provider set before version 2,
+// when it only chose the provider of new favorites; it now also narrows the
+// providers queried. grant configure wrote azure for everyone then, but that
+// looks the same as a value the user chose, so the value is kept, and the
+// notice repeats until the file is upgraded.
+func defaultProviderNotice(doc *yaml.Node) string {
+	v := mappingValue(doc, "default_provider")
+	if v == nil || v.Value == "" {
+		return ""
+	}
+	return fmt.Sprintf("default_provider: %s now also limits the providers grant queries to %s. "+
+		"Run 'grant config unset default_provider' to query every provider, or 'grant config upgrade' to keep it",
+		v.Value, v.Value)
+}
+
+// parseDocument parses data into the mapping node of its document; an empty
+// file is an empty mapping.
+func parseDocument(data []byte) (*yaml.Node, error) {
+	var file yaml.Node
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	doc := file.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil, errors.New("config must be a YAML mapping")
+	}
+	return doc, nil
+}
+
+// documentVersion returns the version: of doc, 0 when it has none.
+func documentVersion(doc *yaml.Node) (int, error) {
+	v := mappingValue(doc, "version")
+	if v == nil {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v.Value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid config version %q: must be a whole number", v.Value)
+	}
+	return n, nil
+}
+
+// migrate upgrades doc to CurrentVersion and reports whether anything ran,
+// with the notices of the steps that did. A document from a newer grant is
+// refused rather than misread.
+func migrate(doc *yaml.Node) (changed bool, notices []string, err error) {
+	version, err := documentVersion(doc)
+	if err != nil {
+		return false, nil, err
+	}
+	if version > CurrentVersion {
+		return false, nil, fmt.Errorf("config version %d is newer than this grant supports (%d); upgrade grant to use it", version, CurrentVersion)
+	}
+	for _, m := range migrations[version:] {
+		if m.notice != nil {
+			if n := m.notice(doc); n != "" {
+				notices = append(notices, n)
+			}
+		}
+		if m.apply != nil {
+			if err := m.apply(doc); err != nil {
+				return false, nil, fmt.Errorf("failed to upgrade config to version %d (%s): %w", m.to, m.description, err)
+			}
+		}
+		setMappingValue(doc, "version", strconv.Itoa(m.to))
+	}
+	return version < CurrentVersion, notices, nil
+}
+
+// UpgradeFile upgrades the config at path in place, first copying the old
+// file to path.bak-<timestamp> (see writeBackup), and returns the backup path. The new file
+// replaces the old one by rename, so a failed write never leaves it
+// truncated. A current, missing or invalid file is left alone and "" is
+// returned: Load reports the problem.
+func UpgradeFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil
+	}
+	doc, err := parseDocument(data)
+	if err != nil {
+		return "", nil
+	}
+	// Reading the file already printed its notices; upgrading acknowledges them.
+	changed, _, err := migrate(doc)
+	if err != nil || !changed {
+		return "", nil
+	}
+	if _, err := decodeDocument(doc); err != nil {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	if err := enc.Encode(doc); err != nil {
+		return "", fmt.Errorf("failed to upgrade config %s: %w", path, err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("failed to upgrade config %s: %w", path, err)
+	}
+
+	backup, err := writeBackup(path, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to back up config %s before upgrading it: %w", path, err)
+	}
+	if err := WriteFile(path, buf.Bytes()); err != nil {
+		return "", fmt.Errorf("failed to upgrade config %s: %w", path, err)
+	}
+	return backup, nil
+}
+
+// writeBackup writes data to a new path.bak-<timestamp> and returns its name.
+// The file is created exclusively, so a backup from the same second is never
+// overwritten: the name then gets a .1, .2, ... suffix.
+func writeBackup(path string, data []byte) (string, error) {
+	base := path + ".bak-" + now().Format("20060102-150405")
+	for i := 0; ; i++ {
+		name := base
+		if i > 0 {
+			name = fmt.Sprintf("%s.%d", base, i)
+		}
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			os.Remove(name)
+			return "", err
+		}
+		if err := f.Close(); err != nil {
+			os.Remove(name)
+			return "", err
+		}
+		return name, nil
+	}
+}
+
+// decodeDocument decodes a migrated document into a Config with defaults and
+// runs the checks Load refuses to start without.
+func decodeDocument(doc *yaml.Node) (*Config, error) {
+	cfg := DefaultConfig()
+	if err := doc.Decode(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Favorites == nil {
+		cfg.Favorites = make(map[string]Favorite)
+	}
+	if err := validateSettings(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// mappingValue returns the value node of key in mapping m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key in mapping m to the scalar value, adding the key
+// first in m if it is missing.
+func setMappingValue(m *yaml.Node, key, value string) {
+	if v := mappingValue(m, key); v != nil {
+		// Keep the node, and so its comments.
+		v.Kind, v.Tag, v.Style, v.Value, v.Content = yaml.ScalarNode, "", 0, value, nil
+		return
+	}
+	m.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: key},
+		{Kind: yaml.ScalarNode, Value: value},
+	}, m.Content...)
+}
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "rewrite the migration golden files")
+
+// TestMigrations_Golden runs each step on testdata/migrations/v<N>.in.yaml
+// and compares the result with v<N>.out.yaml. Run with -update to rewrite
+// the .out files after changing a step.
+func TestMigrations_Golden(t *testing.T) {
+	t.Parallel()
+	for _, m := range migrations {
+		t.Run(fmt.Sprintf("v%d", m.to), func(t *testing.T) {
+			t.Parallel()
+			base := filepath.Join("testdata", "migrations", fmt.Sprintf("v%d", m.to))
+			in, err := os.ReadFile(base + ".in.yaml")
+			if err != nil {
+				t.Fatalf("every migration needs golden files: %v", err)
+			}
+			doc, err := parseDocument(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.apply != nil {
+				if err := m.apply(doc); err != nil {
+					t.Fatalf("apply: %v", err)
+				}
+			}
+			setMappingValue(doc, "version", fmt.Sprint(m.to))
+
+			var got bytes.Buffer
+			enc := yaml.NewEncoder(&got)
+			if err := enc.Encode(doc); err != nil {
+				t.Fatal(err)
+			}
+			if err := enc.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if *update {
+				if err := os.WriteFile(base+".out.yaml", got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(base + ".out.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != string(want) {
+				t.Errorf("v%d migration mismatch\ngot:\n%s\nwant:\n%s", m.to, got.String(), want)
+			}
+		})
+	}
+}
+
+func TestLoad_MigratesInMemory(t *testing.T) {
+	t.Parallel()
+	in, err := os.ReadFile(filepath.Join("testdata", "migrations", "v1.in.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeLayer(t, t.TempDir(), "config.yaml", string(in))
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Version != CurrentVersion || cfg.Favorites["prod-admin"].Type != FavoriteTypeCloud {
+		t.Errorf("Load = %+v, want version %d and typed favorites", cfg, CurrentVersion)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(in) {
+		t.Errorf("Load rewrote %s:\n%s", path, data)
+	}
+}
+
+func TestLoad_RefusesNewerVersion(t *testing.T) {
+	t.Parallel()
+	path := writeLayer(t, t.TempDir(), "config.yaml", fmt.Sprintf("version: %d\nprofile: grant\n", CurrentVersion+1))
+
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), "newer than this grant supports") {
+		t.Fatalf("Load = %v, want a newer-version error", err)
+	}
+	if _, err := UpgradeFile(path); err != nil {
+		t.Errorf("UpgradeFile = %v, want the file left for Load to report", err)
+	}
+}
+
+// Not parallel: replaces the backup clock.
+func TestUpgradeFile(t *testing.T) {
+	saved := now
+	defer func() { now = saved }()
+	now = func() time.Time { return time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC) }
+
+	dir := t.TempDir()
+	old := "favorites:\n  prod:\n    provider: aws\n    target: Prod\n    role: Admin\n"
+	path := writeLayer(t, dir, "config.yaml", old)
+
+	backup, err := UpgradeFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := path + ".bak-20260301-093000"; backup != want {
+		t.Errorf("backup = %q, want %q", backup, want)
+	}
+	if data, err := os.ReadFile(backup); err != nil || string(data) != old {
+		t.Errorf("backup = %q, %v; want the original file", data, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), fmt.Sprintf("version: %d\n", CurrentVersion)) || !strings.Contains(string(data), "type: cloud") {
+		t.Errorf("upgraded file:\n%s", data)
+	}
+
+	// Current and invalid files are left alone.
+	if backup, err := UpgradeFile(path); backup != "" || err != nil {
+		t.Errorf("second upgrade = %q, %v; want nothing to do", backup, err)
+	}
+
+	// A second upgrade in the same second keeps the first backup.
+	older := "favorites: {}\n"
+	if err := os.WriteFile(path, []byte(older), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	second, err := UpgradeFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := path + ".bak-20260301-093000.1"; second != want {
+		t.Errorf("second backup = %q, want %q", second, want)
+	}
+	if data, err := os.ReadFile(second); err != nil || string(data) != older {
+		t.Errorf("second backup = %q, %v; want the file it replaced", data, err)
+	}
+	if data, err := os.ReadFile(backup); err != nil || string(data) != old {
+		t.Errorf("first backup = %q, %v; want it kept", data, err)
+	}
+	invalid := writeLayer(t, dir, "invalid.yaml", "cache_ttl: soon\n")
+	if backup, err := UpgradeFile(invalid); backup != "" || err != nil {
+		t.Errorf("upgrade of an invalid file = %q, %v; want it left for Load to report", backup, err)
+	}
+}
+
+func TestLoadLayers_MigratesInMemoryOnly(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	legacy := "favorites:\n  x:\n    provider: aws\n    target: T\n    role: R\n"
+	system := writeLayer(t, dir, "system.yaml", legacy)
+	user := writeLayer(t, dir, "config.yaml", legacy)
+
+	l, err := loadLayers(system, user, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Config.Favorites["x"].Type; got != FavoriteTypeCloud {
+		t.Errorf("favorite type = %q, want the migrated %q", got, FavoriteTypeCloud)
+	}
+	for _, path := range []string{system, user} {
+		if data, _ := os.ReadFile(path); string(data) != legacy {
+			t.Errorf("%s rewritten by a read:\n%s", path, data)
+		}
+	}
+	if backups, _ := filepath.Glob(user + ".bak-*"); len(backups) != 0 {
+		t.Errorf("a read left backups: %v", backups)
+	}
+}
+
+func TestLoadLayers_KeysOfMigratedDocument(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	system := writeLayer(t, dir, "system.yaml", fmt.Sprintf("version: %d\ndefault_provider: aws\n", CurrentVersion))
+	// A version 1 file: the migrations keep its default_provider and add the
+	// favorite type, which the user layer then sets.
+	user := writeLayer(t, dir, "config.yaml", "version: 1\ndefault_provider: azure\nfavorites:\n  x:\n    provider: azure\n    target: T\n    role: R\n")
+
+	l, err := loadLayers(system, user, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Config.DefaultProvider != "azure" {
+		t.Errorf("default_provider = %q, want the user layer's azure", l.Config.DefaultProvider)
+	}
+	if src, ok := l.Source("default_provider"); !ok || src.Name != LayerUser {
+		t.Errorf("default_provider source = %+v, %v; want the user layer", src, ok)
+	}
+	if src, ok := l.Source("version"); !ok || src.Name != LayerUser {
+		t.Errorf("version source = %+v, %v; want the user layer", src, ok)
+	}
+}
+
+// Not parallel: replaces Notices.
+func TestLoad_DefaultProviderNotice(t *testing.T) {
+	var out bytes.Buffer
+	saved := Notices
+	Notices = &out
+	t.Cleanup(func() { Notices = saved })
+
+	dir := t.TempDir()
+	path := writeLayer(t, dir, "config.yaml", "version: 1\ndefault_provider: azure\n")
+	for range 2 {
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.DefaultProvider != "azure" {
+			t.Errorf("default_provider = %q, want the kept azure", cfg.DefaultProvider)
+		}
+	}
+	if got := strings.Count(out.String(), "default_provider: azure now also limits"); got != 1 {
+		t.Errorf("notice written %d times, want once:\n%s", got, out.String())
+	}
+
+	out.Reset()
+	upgraded := writeLayer(t, dir, "upgraded.yaml", fmt.Sprintf("version: %d\ndefault_provider: azure\n", CurrentVersion))
+	if _, err := Load(upgraded); err != nil {
+		t.Fatal(err)
+	}
+	unset := writeLayer(t, dir, "unset.yaml", "version: 1\nprofile: grant\n")
+	if _, err := Load(unset); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("notice for an upgraded file or an unset default_provider:\n%s", out.String())
+	}
+}
@@ -32,6 +32,14 @@ const (
 	OutcomeNotApplicable RevocationOutcome = "not_applicable"
 	// OutcomeUnknown covers unrecognized, empty and missing statuses. It fails closed.
 	OutcomeUnknown RevocationOutcome = "unknown"
+
+	// OutcomeConfirmedGone means an accepted revocation was verified by the
+	// session no longer being listed. Never returned by ClassifyRevocationStatus:
+	// it is only assigned after polling the session list.
+	OutcomeConfirmedGone RevocationOutcome = "confirmed_gone"
+	// OutcomeStillPresent means an accepted revocation was still listed when
+	// polling gave up. Never returned by ClassifyRevocationStatus.
+	OutcomeStillPresent RevocationOutcome = "still_present"
 )
 
 // ClassifyRevocationStatus maps a raw API status to an outcome. Matching is
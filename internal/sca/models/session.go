@@ -19,6 +19,8 @@ type SessionTarget struct {
 // OpenAPI spec's camelCase. The role_id field contains the role display name
 // (e.g., "User Access Administrator"), not an ARM resource path.
 // For group sessions, role_id is absent and Target.Type is "groups".
+// No start time is returned (see docs/entra-groups-api-findings.md), so
+// remaining time is derived from timestamps grant tracks locally.
 type SessionInfo struct {
 	SessionID       string         `json:"session_id"`
 	UserID          string         `json:"user_id"`
@@ -30,6 +30,7 @@ type SCAAccessService struct {
 	*services.IdsecBaseService
 	ispAuth    *auth.IdsecISPAuth
 	httpClient httpClient
+	pageHook   sdkclient.PageHook
 }
 
 // NewSCAAccessService creates a new SCA Access Service instance.
@@ -87,6 +88,11 @@ func NewSCAAccessServiceWithClient(client httpClient) *SCAAccessService {
 	}
 }
 
+// SetPageHook reports each page of a paginated list call to hook; nil stops.
+func (s *SCAAccessService) SetPageHook(hook sdkclient.PageHook) {
+	s.pageHook = hook
+}
+
 // refreshAuth is the callback for refreshing authentication tokens.
 func (s *SCAAccessService) refreshAuth(client *common.IdsecClient) error {
 	return isp.RefreshClient(client, s.ispAuth)
@@ -97,8 +103,8 @@ func (s *SCAAccessService) ServiceConfig() services.IdsecServiceConfig {
 	return ServiceConfig()
 }
 
-// checkResponse returns an error if the HTTP response status is not 200 OK.
-// It reads up to 4 KB of the response body to include in the error message.
+// checkResponse returns a *sdkclient.StatusError if the HTTP response status is
+// not 200 OK. It reads up to 4 KB of the response body to include in the error message.
 func checkResponse(resp *http.Response, operation string) error {
 	if resp.StatusCode == http.StatusOK {
 		return nil
@@ -107,7 +113,7 @@ func checkResponse(resp *http.Response, operation string) error {
 	if readErr != nil {
 		body = []byte("(failed to read response body)")
 	}
-	return fmt.Errorf("%s failed with status %d: %s", operation, resp.StatusCode, string(body))
+	return &sdkclient.StatusError{Operation: operation, StatusCode: resp.StatusCode, Body: string(body)}
 }
 
 // maxPages is the upper bound on pagination requests to guard against infinite loops.
@@ -157,6 +163,10 @@ func paginate[T any](
 			return nil, 0, fmt.Errorf("failed to decode %s response: %w", errPrefix, decErr)
 		}
 
+		if s.pageHook != nil {
+			s.pageHook(sdkclient.Page{Operation: errPrefix, Route: route, Number: page + 1, Items: len(pageItems)})
+		}
+
 		allItems = append(allItems, pageItems...)
 		if page == 0 {
 			total = pageTotal
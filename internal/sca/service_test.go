@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/aaearon/grant-cli/internal/sca/models"
+	"github.com/aaearon/grant-cli/internal/sdkclient"
 )
 
 // mockHTTPClient is a simple mock that returns pre-configured responses
@@ -816,6 +817,8 @@ func TestListEligibility_Pagination(t *testing.T) {
 	}
 
 	svc := &SCAAccessService{httpClient: mock}
+	var pages []sdkclient.Page
+	svc.SetPageHook(func(p sdkclient.Page) { pages = append(pages, p) })
 	result, err := svc.ListEligibility(t.Context(), models.CSPAzure)
 
 	if err != nil {
@@ -827,6 +830,13 @@ func TestListEligibility_Pagination(t *testing.T) {
 	if callCount != 2 {
 		t.Errorf("expected 2 API calls for pagination, got %d", callCount)
 	}
+	wantPages := []sdkclient.Page{
+		{Operation: "eligibility", Route: "/api/access/AZURE/eligibility", Number: 1, Items: 1},
+		{Operation: "eligibility", Route: "/api/access/AZURE/eligibility", Number: 2, Items: 1},
+	}
+	if !reflect.DeepEqual(pages, wantPages) {
+		t.Errorf("page hook got %+v, want %+v", pages, wantPages)
+	}
 }
 
 // TestListEligibility_GCPRouteAndPagination pins the route construction and
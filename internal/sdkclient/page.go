@@ -0,0 +1,13 @@
+package sdkclient
+
+// Page describes one fetched page of a paginated list call.
+type Page struct {
+	Operation string // what was listed, e.g. "eligibility" or "list requests"
+	Route     string
+	Number    int // 1-based
+	Items     int
+}
+
+// PageHook is told about each page a service fetches, as it arrives. Services
+// list concurrently, so a hook must be safe for concurrent use.
+type PageHook func(Page)
@@ -0,0 +1,59 @@
+package sdkclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// StatusError is returned by grant's services when an API call completes with
+// a non-200 HTTP status. Callers classify it with errors.As; the message format
+// predates the type and is unchanged.
+type StatusError struct {
+	Operation  string
+	StatusCode int
+	Body       string // up to the first 4 KB of the response body
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s failed with status %d: %s", e.Operation, e.StatusCode, e.Body)
+}
+
+// HTTPStatus returns the response status, for callers that classify errors
+// without importing this package.
+func (e *StatusError) HTTPStatus() int { return e.StatusCode }
+
+// ErrorDetail is the service's own description of a failed call.
+type ErrorDetail struct {
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	Description string `json:"description"`
+	Link        string `json:"link"`
+}
+
+// Detail returns the error the service described in the response body, either
+// at its top level or under "errorInfo" or "error", or nil when the body is
+// not such a JSON object.
+func (e *StatusError) Detail() *ErrorDetail {
+	var body struct {
+		ErrorDetail
+		ErrorInfo *ErrorDetail     `json:"errorInfo"`
+		Error     *json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal([]byte(e.Body), &body) != nil {
+		return nil
+	}
+	candidates := []*ErrorDetail{body.ErrorInfo}
+	if body.Error != nil {
+		var nested ErrorDetail
+		if json.Unmarshal(*body.Error, &nested) == nil {
+			candidates = append(candidates, &nested)
+		}
+	}
+	candidates = append(candidates, &body.ErrorDetail)
+	for _, d := range candidates {
+		if d != nil && (d.Code != "" || d.Message != "") {
+			return d
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package sdkclient
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestStatusError pins the message format, which predates the type and is what
+// users see, and that the type survives wrapping.
+func TestStatusError(t *testing.T) {
+	err := fmt.Errorf("failed to elevate: %w", &StatusError{Operation: "elevate", StatusCode: 403, Body: `{"code":"DENIED"}`})
+
+	if got, want := err.Error(), `failed to elevate: elevate failed with status 403: {"code":"DENIED"}`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	var se *StatusError
+	if !errors.As(err, &se) || se.StatusCode != 403 {
+		t.Errorf("errors.As did not recover the status: %v", se)
+	}
+}
+
+func TestStatusError_Detail(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *ErrorDetail
+	}{
+		{"top level", `{"code":"DENIED","message":"no","description":"why","link":"https://x.test"}`,
+			&ErrorDetail{Code: "DENIED", Message: "no", Description: "why", Link: "https://x.test"}},
+		{"errorInfo", `{"errorInfo":{"code":"NOT_ELIGIBLE","message":"not eligible"}}`,
+			&ErrorDetail{Code: "NOT_ELIGIBLE", Message: "not eligible"}},
+		{"error object", `{"error":{"code":"BAD","message":"bad request"}}`,
+			&ErrorDetail{Code: "BAD", Message: "bad request"}},
+		{"error string", `{"error":"invalid_token"}`, nil},
+		{"not JSON", `<html>Bad Gateway</html>`, nil},
+		{"empty object", `{}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := (&StatusError{StatusCode: 400, Body: tt.body}).Detail()
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("Detail() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,9 @@
 //   - A direct os.UserHomeDir call is covered only because HOME/USERPROFILE are
 //     redirected; a hardcoded path or a newly-added SDK variable is not covered
 //     at all, and nothing here discovers one automatically.
+//   - The per-project .grant.yaml is found by walking up from the working
+//     directory, not through a variable; one above the checkout is merged
+//     into the config of every test that loads it.
 //   - The OS keyring is a daemon, not a path, so no environment redirect can
 //     sandbox it. Run sets IDSEC_BASIC_KEYRING=1 to force the SDK's file
 //     backend into the sandboxed IDSEC_KEYRING_FOLDER instead. If a future SDK
@@ -43,6 +46,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/aaearon/grant-cli/internal/cache"
@@ -66,6 +70,11 @@ import (
 //     helper would reach for, and a redirect costs nothing.
 //   - IDSEC_PROFILES_FOLDER — takes precedence over HOME in the SDK loader.
 //   - GRANT_CONFIG       — overrides config.ConfigPath.
+//   - GRANT_SYSTEM_CONFIG — overrides config.SystemConfigPath, so a
+//     /etc/grant/config.yaml on the machine running the
+//     suite is not merged into every test's config. Only
+//     read, never written; it points at a file that does
+//     not exist.
 //   - IDSEC_KEYRING_FOLDER — overrides the SDK file-keyring folder outright
 //     (pkg/common/keyring/idsec_basic_keyring.go), bypassing
 //     its HOME fallback. A pre-existing value in the
@@ -84,6 +93,7 @@ var redirectedVars = []string{
 	"XDG_CONFIG_HOME",
 	"IDSEC_PROFILES_FOLDER",
 	"GRANT_CONFIG",
+	"GRANT_SYSTEM_CONFIG",
 	"IDSEC_KEYRING_FOLDER",
 	"IDSEC_FILE_LOG_PATH",
 	"IDSEC_BASIC_KEYRING",
@@ -102,12 +112,30 @@ var redirectedVars = []string{
 //     isp.FromISPAuth (pkg/common/isp/idsec_isp_service_client.go), which the
 //     internal/sca and internal/workflows retry-policy tests now drive for
 //     real, so an exported value changes what those constructors resolve.
+//   - GRANT_PROFILE — selects grant's tenant profile (cmd/profile.go) when no
+//     --profile flag is given, so an exported value points the cmd tests at a
+//     different SDK profile and cache namespace.
+//   - GRANT_TEAM_CONFIG — merges a team config file into every test's config.
+//   - GRANT_OUTPUT — overrides the --output default, switching text output
+//     to JSON under every cmd test that reads it.
 //
 // Restoration is exact: a variable that was set comes back with its original
 // value, one that was unset stays unset.
 var unsetVars = []string{
 	"IDSEC_PROFILE",
 	"DEPLOY_ENV",
+	"GRANT_PROFILE",
+	"GRANT_TEAM_CONFIG",
+	"GRANT_OUTPUT",
+}
+
+// unsetPrefixes extends unsetVars to the inline GRANT_FAVORITE_<NAME>
+// favorites, whose names are open ended. Every variable starting with one is
+// removed and restored like an unsetVars entry, as is the override of every
+// config key, config.EnvVar(key): keys are added with the config, so that
+// list is derived rather than written out here.
+var unsetPrefixes = []string{
+	"GRANT_FAVORITE_",
 }
 
 // nonPathVars are the entries of redirectedVars whose value is a mode switch
@@ -153,6 +181,7 @@ func Run(run func() int) int {
 		"XDG_CONFIG_HOME":       filepath.Join(home, ".config"),
 		"IDSEC_PROFILES_FOLDER": filepath.Join(home, ".idsec", "profiles"),
 		"GRANT_CONFIG":          filepath.Join(home, ".grant", "config.yaml"),
+		"GRANT_SYSTEM_CONFIG":   filepath.Join(root, "etc", "grant", "config.yaml"),
 		"IDSEC_KEYRING_FOLDER":  filepath.Join(home, ".idsec", "cache", "keyring"),
 		"IDSEC_FILE_LOG_PATH":   filepath.Join(home, ".idsec", "logs", "idsec.log"),
 		"IDSEC_BASIC_KEYRING":   "1",
@@ -186,7 +215,17 @@ func Run(run func() int) int {
 		}
 	}
 
-	for _, k := range unsetVars {
+	toUnset := slices.Clone(unsetVars)
+	for _, key := range config.Keys() {
+		toUnset = append(toUnset, config.EnvVar(key))
+	}
+	for _, kv := range os.Environ() {
+		k, _, _ := strings.Cut(kv, "=")
+		if slices.ContainsFunc(unsetPrefixes, func(p string) bool { return strings.HasPrefix(k, p) }) {
+			toUnset = append(toUnset, k)
+		}
+	}
+	for _, k := range toUnset {
 		capture(k)
 		if err := os.Unsetenv(k); err != nil {
 			fmt.Fprintf(os.Stderr, "testenv: failed to unset %s: %v\n", k, err)
@@ -57,6 +57,7 @@ var wantRedirectedVars = []string{
 	"XDG_CONFIG_HOME",
 	"IDSEC_PROFILES_FOLDER",
 	"GRANT_CONFIG",
+	"GRANT_SYSTEM_CONFIG",
 	"IDSEC_KEYRING_FOLDER",
 	"IDSEC_FILE_LOG_PATH",
 	"IDSEC_BASIC_KEYRING",
@@ -80,6 +81,14 @@ func TestRedirectedVarsIsExactlyTheExpectedSet(t *testing.T) {
 var wantUnsetVars = []string{
 	"IDSEC_PROFILE",
 	"DEPLOY_ENV",
+	"GRANT_PROFILE",
+	"GRANT_TEAM_CONFIG",
+	"GRANT_OUTPUT",
+}
+
+// wantUnsetPrefixes pins unsetPrefixes the same way.
+var wantUnsetPrefixes = []string{
+	"GRANT_FAVORITE_",
 }
 
 // Not parallel: kept serial with the rest of the file.
@@ -87,6 +96,39 @@ func TestUnsetVarsIsExactlyTheExpectedSet(t *testing.T) {
 	if !slices.Equal(unsetVars, wantUnsetVars) {
 		t.Errorf("unsetVars = %q, want exactly %q", unsetVars, wantUnsetVars)
 	}
+	if !slices.Equal(unsetPrefixes, wantUnsetPrefixes) {
+		t.Errorf("unsetPrefixes = %q, want exactly %q", unsetPrefixes, wantUnsetPrefixes)
+	}
+}
+
+// Not parallel: mutates process-wide environment variables.
+func TestRun_UnsetsConfigOverridesAndRestoresThem(t *testing.T) {
+	t.Setenv("GRANT_FAVORITE_PROD", "aws/Prod/Admin")
+	t.Setenv("GRANT_CACHE_GROUPS_TTL", "1h")
+	t.Setenv("GRANT_DEFAULT_PROVIDER", "gcp")
+	t.Setenv("GRANT_CACHE_STRESS_DIR", "kept") // not a config key
+
+	var inside []string
+	kept := false
+	Run(func() int {
+		for _, k := range []string{"GRANT_FAVORITE_PROD", "GRANT_CACHE_GROUPS_TTL", "GRANT_DEFAULT_PROVIDER"} {
+			if _, ok := os.LookupEnv(k); ok {
+				inside = append(inside, k)
+			}
+		}
+		kept = os.Getenv("GRANT_CACHE_STRESS_DIR") == "kept"
+		return 0
+	})
+
+	if len(inside) > 0 {
+		t.Errorf("%q still set inside Run; every config override must be unset", inside)
+	}
+	if !kept {
+		t.Error("GRANT_CACHE_STRESS_DIR was unset inside Run; only config overrides are removed")
+	}
+	if got := os.Getenv("GRANT_FAVORITE_PROD"); got != "aws/Prod/Admin" {
+		t.Errorf("GRANT_FAVORITE_PROD = %q after Run, want it restored", got)
+	}
 }
 
 // TestRun_UnsetsSDKBehaviorVarsAndRestoresThem covers both halves of the
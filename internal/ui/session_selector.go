@@ -12,15 +12,17 @@ import (
 )
 
 // FormatSessionOption formats a session for display in the multi-select UI.
-// groupNameMap and remainingMap are nil-safe; when nil, behavior is identical
-// to the original (backwards compatible).
+// groupNameMap, remainingMap and upperBounds are nil-safe; when nil, behavior
+// is identical to the original (backwards compatible). upperBounds marks the
+// sessions whose remaining time is an upper bound rather than exact.
 func FormatSessionOption(
 	session models.SessionInfo,
 	nameMap map[string]string,
 	groupNameMap map[string]string,
 	remainingMap map[string]time.Duration,
+	upperBounds map[string]bool,
 ) string {
-	timeStr := formatTimeString(session, remainingMap)
+	timeStr := formatTimeString(session, remainingMap, upperBounds)
 
 	if session.IsGroupSession() {
 		directory := session.WorkspaceID
@@ -49,19 +51,24 @@ func FormatSessionOption(
 }
 
 // formatTimeString returns the time display string for a session.
-// If the session has a remaining time in remainingMap, it shows "remaining: Xm" or "expired".
+// If the session has a remaining time in remainingMap, it shows "remaining: Xm" or "expired",
+// or "remaining: at most Xm" when upperBounds marks it as an upper bound.
 // Otherwise it falls back to "duration: Xh Ym".
-func formatTimeString(session models.SessionInfo, remainingMap map[string]time.Duration) string {
+func formatTimeString(session models.SessionInfo, remainingMap map[string]time.Duration, upperBounds map[string]bool) string {
 	if remainingMap != nil {
 		if remaining, ok := remainingMap[session.SessionID]; ok {
 			if remaining <= 0 {
 				return "expired"
 			}
+			prefix := "remaining: "
+			if upperBounds[session.SessionID] {
+				prefix = "remaining: at most "
+			}
 			totalMin := int(remaining.Minutes())
 			if totalMin >= 60 {
-				return fmt.Sprintf("remaining: %dh %dm", totalMin/60, totalMin%60)
+				return fmt.Sprintf("%s%dh %dm", prefix, totalMin/60, totalMin%60)
 			}
-			return fmt.Sprintf("remaining: %dm", totalMin)
+			return fmt.Sprintf("%s%dm", prefix, totalMin)
 		}
 	}
 
@@ -81,7 +88,7 @@ func BuildSessionOptions(
 ) []string {
 	options := make([]string, len(sessions))
 	for i, s := range sessions {
-		options[i] = FormatSessionOption(s, nameMap, groupNameMap, remainingMap)
+		options[i] = FormatSessionOption(s, nameMap, groupNameMap, remainingMap, nil)
 	}
 	sort.Strings(options)
 	return options
@@ -96,7 +103,7 @@ func FindSessionByDisplay(
 	display string,
 ) (*models.SessionInfo, error) {
 	for i := range sessions {
-		if FormatSessionOption(sessions[i], nameMap, groupNameMap, remainingMap) == display {
+		if FormatSessionOption(sessions[i], nameMap, groupNameMap, remainingMap, nil) == display {
 			return &sessions[i], nil
 		}
 	}
@@ -187,7 +187,7 @@ func TestFormatSessionOption(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got := FormatSessionOption(tt.session, tt.nameMap, tt.groupNameMap, tt.remainingMap)
+			got := FormatSessionOption(tt.session, tt.nameMap, tt.groupNameMap, tt.remainingMap, nil)
 			if got != tt.want {
 				t.Errorf("FormatSessionOption() = %q, want %q", got, tt.want)
 			}
@@ -223,7 +223,7 @@ func TestFindSessionByDisplay(t *testing.T) {
 
 	t.Run("found", func(t *testing.T) {
 		t.Parallel()
-		display := FormatSessionOption(sessions[0], nameMap, nil, nil)
+		display := FormatSessionOption(sessions[0], nameMap, nil, nil, nil)
 		found, err := FindSessionByDisplay(sessions, nameMap, nil, nil, display)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -324,7 +324,7 @@ func TestFormatSessionOption_ExactlyZeroRemaining(t *testing.T) {
 	remainingMap := map[string]time.Duration{"session-rem-zero": 0}
 
 	want := "Reader on /subscriptions/sub-1 - expired (session: session-rem-zero)"
-	if got := FormatSessionOption(session, nil, nil, remainingMap); got != want {
+	if got := FormatSessionOption(session, nil, nil, remainingMap, nil); got != want {
 		t.Errorf("FormatSessionOption() = %q, want %q", got, want)
 	}
 }
@@ -343,3 +343,21 @@ func TestSelectSessions_EmptyList(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestFormatSessionOption_UpperBoundRemaining(t *testing.T) {
+	t.Parallel()
+	session := models.SessionInfo{
+		SessionID:       "session-remote",
+		CSP:             models.CSPAWS,
+		WorkspaceID:     "111111111111",
+		RoleID:          "Admin",
+		SessionDuration: 3600,
+	}
+	remainingMap := map[string]time.Duration{"session-remote": 42 * time.Minute}
+	upperBounds := map[string]bool{"session-remote": true}
+
+	want := "Admin on 111111111111 - remaining: at most 42m (session: session-remote)"
+	if got := FormatSessionOption(session, nil, nil, remainingMap, upperBounds); got != want {
+		t.Errorf("FormatSessionOption() = %q, want %q", got, want)
+	}
+}
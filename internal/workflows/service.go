@@ -29,6 +29,7 @@ type AccessRequestService struct {
 	*services.IdsecBaseService
 	ispAuth    *auth.IdsecISPAuth
 	httpClient httpClient
+	pageHook   sdkclient.PageHook
 }
 
 // NewAccessRequestService creates a new Access Request Service instance.
@@ -76,6 +77,11 @@ func NewAccessRequestServiceWithClient(client httpClient) *AccessRequestService
 	}
 }
 
+// SetPageHook reports each page of ListRequests to hook; nil stops.
+func (s *AccessRequestService) SetPageHook(hook sdkclient.PageHook) {
+	s.pageHook = hook
+}
+
 func (s *AccessRequestService) refreshAuth(client *common.IdsecClient) error {
 	return isp.RefreshClient(client, s.ispAuth)
 }
@@ -136,7 +142,7 @@ func (s *AccessRequestService) ListRequests(ctx context.Context, params ListRequ
 	totalCount := 0
 	offset := params.Offset
 
-	for range maxPages {
+	for n := range maxPages {
 		qp := make(map[string]string)
 		qp["limit"] = strconv.Itoa(limit)
 		qp["offset"] = strconv.Itoa(offset)
@@ -170,6 +176,10 @@ func (s *AccessRequestService) ListRequests(ctx context.Context, params ListRequ
 		}
 		resp.Body.Close()
 
+		if s.pageHook != nil {
+			s.pageHook(sdkclient.Page{Operation: "list requests", Route: "/api/workflows/requests", Number: n + 1, Items: len(page.Items)})
+		}
+
 		allItems = append(allItems, page.Items...)
 		totalCount = page.TotalCount
 
@@ -295,5 +305,5 @@ func checkResponse(resp *http.Response, operation string) error {
 	if readErr != nil {
 		body = []byte("(failed to read response body)")
 	}
-	return fmt.Errorf("%s failed with status %d: %s", operation, resp.StatusCode, string(body))
+	return &sdkclient.StatusError{Operation: operation, StatusCode: resp.StatusCode, Body: string(body)}
 }
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/aaearon/grant-cli/internal/sdkclient"
 	"github.com/aaearon/grant-cli/internal/workflows/models"
 )
 
@@ -148,6 +149,8 @@ func TestListRequests(t *testing.T) {
 	}
 
 	svc := NewAccessRequestServiceWithClient(mock)
+	var pages []sdkclient.Page
+	svc.SetPageHook(func(p sdkclient.Page) { pages = append(pages, p) })
 	items, total, err := svc.ListRequests(t.Context(), ListRequestsParams{Limit: 2})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -161,6 +164,9 @@ func TestListRequests(t *testing.T) {
 	if callCount != 2 {
 		t.Errorf("expected 2 API calls, got %d", callCount)
 	}
+	if len(pages) != 2 || pages[0].Items != 2 || pages[1].Number != 2 || pages[1].Items != 1 || pages[1].Route != "/api/workflows/requests" {
+		t.Errorf("page hook got %+v, want pages 1 (2 items) and 2 (1 item)", pages)
+	}
 }
 
 func TestListRequests_MaxPagesExceeded(t *testing.T) {